@@ -0,0 +1,335 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/api/v1beta1"
+)
+
+// The spec of every kind has the same shape in v1alpha1 and v1beta1, so it is
+// converted through its JSON representation. The status fields whose shape
+// changed in v1beta1 are converted field by field.
+
+// lostConditionsAnnotation keeps the fields of the v1alpha1 conditions which the v1beta1 conditions can't hold,
+// like the lastUpdateTime, so they are restored when the object is converted back to v1alpha1.
+const lostConditionsAnnotation = "operator.ibm.com/v1alpha1-conditions"
+
+// lostCondition is the v1alpha1 fields of a condition at the path of the status, like status or members/etcd.
+// The fields are only restored while the v1beta1 condition still has the transition time and the reason
+// it was converted to, otherwise the condition has been changed in v1beta1 since.
+type lostCondition struct {
+	Path                  string      `json:"path"`
+	Type                  string      `json:"type"`
+	LastTransitionTime    string      `json:"lastTransitionTime,omitempty"`
+	LastUpdateTime        string      `json:"lastUpdateTime,omitempty"`
+	Reason                string      `json:"reason,omitempty"`
+	HubLastTransitionTime metav1.Time `json:"hubLastTransitionTime"`
+	HubReason             string      `json:"hubReason"`
+}
+
+// ConvertTo converts this OperandRequest to the Hub version (v1beta1).
+func (r *OperandRequest) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.OperandRequest)
+	dst.ObjectMeta = *r.ObjectMeta.DeepCopy()
+	if err := convertByJSON(&r.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	var lost []lostCondition
+	dst.Status.Members = nil
+	for _, m := range r.Status.Members {
+		member := v1beta1.MemberStatus{}
+		conds := m.Conditions
+		m.Conditions = nil
+		if err := convertByJSON(&m, &member); err != nil {
+			return err
+		}
+		member.Conditions = convertConditionsTo(conds, r.CreationTimestamp, "members/"+m.Name, &lost)
+		dst.Status.Members = append(dst.Status.Members, member)
+	}
+	dst.Status.ObservedGeneration = r.Status.ObservedGeneration
+	dst.Status.Conditions = convertConditionsTo(r.Status.Conditions, r.CreationTimestamp, "status", &lost)
+	dst.Status.Phase = v1beta1.ClusterPhase(r.Status.Phase)
+	return setLostConditions(&dst.ObjectMeta, lost)
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (r *OperandRequest) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.OperandRequest)
+	r.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	lost := getLostConditions(&r.ObjectMeta)
+	if err := convertByJSON(&src.Spec, &r.Spec); err != nil {
+		return err
+	}
+	r.Status.Members = nil
+	for _, m := range src.Status.Members {
+		member := MemberStatus{}
+		conds := m.Conditions
+		m.Conditions = nil
+		if err := convertByJSON(&m, &member); err != nil {
+			return err
+		}
+		member.Conditions = convertConditionsFrom(conds, "members/"+m.Name, lost)
+		r.Status.Members = append(r.Status.Members, member)
+	}
+	r.Status.ObservedGeneration = src.Status.ObservedGeneration
+	r.Status.Conditions = convertConditionsFrom(src.Status.Conditions, "status", lost)
+	r.Status.Phase = ClusterPhase(src.Status.Phase)
+	return nil
+}
+
+// ConvertTo converts this OperandRegistry to the Hub version (v1beta1).
+func (r *OperandRegistry) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.OperandRegistry)
+	dst.ObjectMeta = *r.ObjectMeta.DeepCopy()
+	if err := convertByJSON(&r.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	dst.Status.ObservedGeneration = r.Status.ObservedGeneration
+	dst.Status.Phase = v1beta1.RegistryPhase(r.Status.Phase)
+	var lost []lostCondition
+	dst.Status.Conditions = convertConditionsTo(r.Status.Conditions, r.CreationTimestamp, "status", &lost)
+	dst.Status.OperatorsStatus = nil
+	// Sort the operators by name, so the list is stable across conversions
+	names := make([]string, 0, len(r.Status.OperatorsStatus))
+	for name := range r.Status.OperatorsStatus {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := r.Status.OperatorsStatus[name]
+		os := v1beta1.OperatorStatus{
+			Name:  name,
+			Phase: v1beta1.OperatorPhase(s.Phase),
+		}
+		for _, rr := range s.ReconcileRequests {
+			os.ReconcileRequests = append(os.ReconcileRequests, v1beta1.ReconcileRequest{Name: rr.Name, Namespace: rr.Namespace})
+		}
+		dst.Status.OperatorsStatus = append(dst.Status.OperatorsStatus, os)
+	}
+	return setLostConditions(&dst.ObjectMeta, lost)
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (r *OperandRegistry) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.OperandRegistry)
+	r.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	lost := getLostConditions(&r.ObjectMeta)
+	if err := convertByJSON(&src.Spec, &r.Spec); err != nil {
+		return err
+	}
+	r.Status.ObservedGeneration = src.Status.ObservedGeneration
+	r.Status.Phase = RegistryPhase(src.Status.Phase)
+	r.Status.Conditions = convertConditionsFrom(src.Status.Conditions, "status", lost)
+	r.Status.OperatorsStatus = nil
+	if len(src.Status.OperatorsStatus) != 0 {
+		r.Status.OperatorsStatus = make(map[string]OperatorStatus, len(src.Status.OperatorsStatus))
+	}
+	for _, os := range src.Status.OperatorsStatus {
+		s := OperatorStatus{Phase: OperatorPhase(os.Phase)}
+		for _, rr := range os.ReconcileRequests {
+			s.ReconcileRequests = append(s.ReconcileRequests, ReconcileRequest{Name: rr.Name, Namespace: rr.Namespace})
+		}
+		r.Status.OperatorsStatus[os.Name] = s
+	}
+	return nil
+}
+
+// ConvertTo converts this OperandConfig to the Hub version (v1beta1).
+func (r *OperandConfig) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.OperandConfig)
+	dst.ObjectMeta = *r.ObjectMeta.DeepCopy()
+	if err := convertByJSON(&r.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertByJSON(&r.Status, &dst.Status)
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (r *OperandConfig) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.OperandConfig)
+	r.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	if err := convertByJSON(&src.Spec, &r.Spec); err != nil {
+		return err
+	}
+	return convertByJSON(&src.Status, &r.Status)
+}
+
+// ConvertTo converts this OperandBindInfo to the Hub version (v1beta1).
+func (r *OperandBindInfo) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.OperandBindInfo)
+	dst.ObjectMeta = *r.ObjectMeta.DeepCopy()
+	if err := convertByJSON(&r.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertByJSON(&r.Status, &dst.Status)
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (r *OperandBindInfo) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.OperandBindInfo)
+	r.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	if err := convertByJSON(&src.Spec, &r.Spec); err != nil {
+		return err
+	}
+	return convertByJSON(&src.Status, &r.Status)
+}
+
+// convertByJSON copies src into dst through their JSON representation.
+func convertByJSON(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// convertConditionsTo converts the conditions to the metav1.Condition schema of the Hub.
+// A condition written without a transition time falls back to its update time, then
+// to the creation time of the object, and a condition without a reason uses its type.
+// The v1alpha1 fields which are changed or dropped by the conversion are added to lost.
+func convertConditionsTo(conds []Condition, created metav1.Time, path string, lost *[]lostCondition) []v1beta1.Condition {
+	if conds == nil {
+		return nil
+	}
+	out := make([]v1beta1.Condition, 0, len(conds))
+	for _, c := range conds {
+		transitionTime := parseConditionTime(c.LastTransitionTime)
+		if transitionTime.IsZero() {
+			transitionTime = parseConditionTime(c.LastUpdateTime)
+		}
+		if transitionTime.IsZero() {
+			transitionTime = created
+		}
+		reason := c.Reason
+		if reason == "" {
+			reason = string(c.Type)
+		}
+		if c.LastUpdateTime != "" || c.LastTransitionTime != formatConditionTime(transitionTime) || c.Reason != reason {
+			*lost = append(*lost, lostCondition{
+				Path:                  path,
+				Type:                  string(c.Type),
+				LastTransitionTime:    c.LastTransitionTime,
+				LastUpdateTime:        c.LastUpdateTime,
+				Reason:                c.Reason,
+				HubLastTransitionTime: transitionTime,
+				HubReason:             reason,
+			})
+		}
+		out = append(out, v1beta1.Condition{
+			Type:               string(c.Type),
+			Status:             c.Status,
+			ObservedGeneration: c.ObservedGeneration,
+			LastTransitionTime: transitionTime,
+			Reason:             reason,
+			Message:            c.Message,
+		})
+	}
+	return out
+}
+
+// convertConditionsFrom converts the conditions from the Hub, and restores their lost v1alpha1 fields
+func convertConditionsFrom(conds []v1beta1.Condition, path string, lost []lostCondition) []Condition {
+	if conds == nil {
+		return nil
+	}
+	out := make([]Condition, 0, len(conds))
+	for _, c := range conds {
+		cond := Condition{
+			Type:               ConditionType(c.Type),
+			Status:             c.Status,
+			ObservedGeneration: c.ObservedGeneration,
+			LastTransitionTime: formatConditionTime(c.LastTransitionTime),
+			Reason:             c.Reason,
+			Message:            c.Message,
+		}
+		for _, l := range lost {
+			if l.Path == path && l.Type == c.Type && l.HubReason == c.Reason && l.HubLastTransitionTime.Equal(&c.LastTransitionTime) {
+				cond.LastTransitionTime = l.LastTransitionTime
+				cond.LastUpdateTime = l.LastUpdateTime
+				cond.Reason = l.Reason
+				break
+			}
+		}
+		out = append(out, cond)
+	}
+	return out
+}
+
+// setLostConditions keeps the lost fields of the conditions in the annotation of the Hub
+func setLostConditions(meta *metav1.ObjectMeta, lost []lostCondition) error {
+	if len(lost) == 0 {
+		delete(meta.Annotations, lostConditionsAnnotation)
+		return nil
+	}
+	data, err := json.Marshal(lost)
+	if err != nil {
+		return err
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[lostConditionsAnnotation] = string(data)
+	return nil
+}
+
+// getLostConditions takes the lost fields of the conditions out of the annotation of the Hub.
+// The annotation which can't be decoded is dropped.
+func getLostConditions(meta *metav1.ObjectMeta) []lostCondition {
+	data, ok := meta.Annotations[lostConditionsAnnotation]
+	if !ok {
+		return nil
+	}
+	delete(meta.Annotations, lostConditionsAnnotation)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+	var lost []lostCondition
+	if err := json.Unmarshal([]byte(data), &lost); err != nil {
+		klog.Warningf("failed to decode the annotation %s: %v", lostConditionsAnnotation, err)
+		return nil
+	}
+	return lost
+}
+
+// parseConditionTime parses a v1alpha1 condition timestamp.
+// A timestamp which isn't in RFC3339 format is replaced in v1beta1, and it is kept in the lost fields.
+func parseConditionTime(t string) metav1.Time {
+	if t == "" {
+		return metav1.Time{}
+	}
+	parsed, err := time.Parse(time.RFC3339, t)
+	if err != nil {
+		klog.V(2).Infof("failed to parse the condition time %s: %v", t, err)
+		return metav1.Time{}
+	}
+	return metav1.NewTime(parsed)
+}
+
+func formatConditionTime(t metav1.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
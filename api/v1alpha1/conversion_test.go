@@ -0,0 +1,286 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/api/v1beta1"
+)
+
+var _ = Describe("Conversion", func() {
+	meta := metav1.ObjectMeta{
+		Name:        "common-service",
+		Namespace:   "ibm-common-services",
+		Labels:      map[string]string{"app": "odlm"},
+		Annotations: map[string]string{"note": "conversion"},
+	}
+
+	Context("Convert OperandRequest", func() {
+		It("Should round trip v1alpha1 through v1beta1 without loss", func() {
+			src := &OperandRequest{
+				ObjectMeta: meta,
+				Spec: OperandRequestSpec{
+					Requests: []Request{
+						{
+							Registry:          "common-service",
+							RegistryNamespace: "ibm-common-services",
+							Description:       "request the etcd",
+							Operands: []Operand{
+								{
+									Name: "etcd",
+									Bindings: map[string]SecretConfigmap{
+										"public": {Secret: "etcd-secret", Configmap: "etcd-cm"},
+									},
+								},
+								{
+									Name:         "jenkins",
+									Kind:         "Jenkins",
+									InstanceName: "example",
+									Spec:         &runtime.RawExtension{Raw: []byte(`{"service":{"port":8081}}`)},
+								},
+							},
+						},
+					},
+				},
+				Status: OperandRequestStatus{
+					ObservedGeneration: 2,
+					Conditions: []Condition{
+						{
+							Type:               ConditionReady,
+							Status:             "False",
+							ObservedGeneration: 2,
+							LastTransitionTime: "2021-01-02T03:04:05Z",
+							Reason:             "Installing",
+							Message:            "etcd: Creating Subscription etcd",
+						},
+						{
+							Type:               ConditionNotFound,
+							Status:             "True",
+							LastTransitionTime: "2021-01-02 03:04:05",
+							LastUpdateTime:     "2021-01-02 03:04:05",
+							Message:            "jenkins: Not found",
+						},
+					},
+					Members: []MemberStatus{
+						{
+							Name:          "etcd",
+							Phase:         MemberPhase{OperatorPhase: OperatorRunning, OperandPhase: ServiceRunning},
+							OperandCRList: []OperandCRMember{{Name: "example", Kind: "EtcdCluster", APIVersion: "etcd.database.coreos.com/v1beta2"}},
+						},
+						{
+							Name:  "jenkins",
+							Phase: MemberPhase{OperatorPhase: OperatorInstalling},
+							Conditions: []Condition{
+								{
+									Type:               ConditionCreating,
+									Status:             "True",
+									ObservedGeneration: 2,
+									LastTransitionTime: "2021-01-02T03:04:05Z",
+									Reason:             "CreatingSubscription",
+									Message:            "Creating Subscription jenkins",
+								},
+								{
+									Type:           ConditionUpdating,
+									Status:         "True",
+									LastUpdateTime: "2021-01-02T03:04:05Z",
+									Message:        "Updating Subscription jenkins",
+								},
+							},
+						},
+					},
+					Phase: ClusterPhaseRunning,
+				},
+			}
+
+			hub := &v1beta1.OperandRequest{}
+			Expect(src.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Status.Conditions[0].LastTransitionTime.UTC().Format("2006-01-02T15:04:05Z")).Should(Equal("2021-01-02T03:04:05Z"))
+			Expect(hub.Annotations).Should(HaveKey(lostConditionsAnnotation))
+
+			dst := &OperandRequest{}
+			Expect(dst.ConvertFrom(hub)).To(Succeed())
+			Expect(dst).Should(Equal(src))
+		})
+
+		It("Should not restore the fields of a condition changed in v1beta1", func() {
+			src := &OperandRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services"},
+				Status: OperandRequestStatus{
+					Conditions: []Condition{
+						{Type: ConditionReady, Status: "False", LastUpdateTime: "2021-01-02T03:04:05Z"},
+					},
+				},
+			}
+			hub := &v1beta1.OperandRequest{}
+			Expect(src.ConvertTo(hub)).To(Succeed())
+			hub.Status.Conditions[0].Status = "True"
+			hub.Status.Conditions[0].Reason = "Running"
+			hub.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC))
+
+			dst := &OperandRequest{}
+			Expect(dst.ConvertFrom(hub)).To(Succeed())
+			Expect(dst.Annotations).Should(BeNil())
+			Expect(dst.Status.Conditions).Should(Equal([]Condition{
+				{Type: ConditionReady, Status: "True", LastTransitionTime: "2021-02-01T00:00:00Z", Reason: "Running"},
+			}))
+		})
+	})
+
+	Context("Convert the conditions of OperandRequest", func() {
+		It("Should fill the required fields of the v1beta1 conditions", func() {
+			created := metav1.NewTime(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+			src := &OperandRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services", CreationTimestamp: created},
+				Status: OperandRequestStatus{
+					Conditions: []Condition{
+						{Type: ConditionReady, Status: "True", LastUpdateTime: "2021-01-02T03:04:05Z"},
+						{Type: ConditionNotFound, Status: "True", Reason: "OperatorNotFound"},
+					},
+				},
+			}
+
+			hub := &v1beta1.OperandRequest{}
+			Expect(src.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Status.Conditions[0].LastTransitionTime.UTC().Format(time.RFC3339)).Should(Equal("2021-01-02T03:04:05Z"))
+			Expect(hub.Status.Conditions[0].Reason).Should(Equal("Ready"))
+			Expect(hub.Status.Conditions[1].LastTransitionTime).Should(Equal(created))
+			Expect(hub.Status.Conditions[1].Reason).Should(Equal("OperatorNotFound"))
+		})
+	})
+
+	Context("Convert OperandRegistry", func() {
+		It("Should round trip v1alpha1 through v1beta1 without loss", func() {
+			src := &OperandRegistry{
+				ObjectMeta: meta,
+				Spec: OperandRegistrySpec{
+					Operators: []Operator{
+						{
+							Name:                "etcd",
+							Namespace:           "etcd-operator",
+							SourceName:          "community-operators",
+							SourceNamespace:     "openshift-marketplace",
+							PackageName:         "etcd",
+							Channel:             "singlenamespace-alpha",
+							Scope:               ScopePublic,
+							InstallMode:         "cluster",
+							TargetNamespaces:    []string{"etcd-operator"},
+							InstallPlanApproval: olmv1alpha1.ApprovalManual,
+							StartingCSV:         "etcdoperator.v0.9.4",
+							VersionConstraint:   ">=0.9.0 <0.10.0",
+							Dependencies:        []string{"jenkins"},
+							SubscriptionConfig:  &olmv1alpha1.SubscriptionConfig{NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""}},
+						},
+					},
+				},
+				Status: OperandRegistryStatus{
+					ObservedGeneration: 3,
+					Phase:              RegistryRunning,
+					Conditions: []Condition{
+						{Type: ConditionReady, Status: "True", LastUpdateTime: "2021-01-02T03:04:05Z"},
+					},
+					OperatorsStatus: map[string]OperatorStatus{
+						"jenkins": {Phase: OperatorRunning},
+						"etcd": {
+							Phase: OperatorRunning,
+							ReconcileRequests: []ReconcileRequest{
+								{Name: "common-service", Namespace: "ibm-common-services"},
+							},
+						},
+					},
+				},
+			}
+
+			hub := &v1beta1.OperandRegistry{}
+			Expect(src.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.Operators[0].Scope).Should(Equal(v1beta1.ScopePublic))
+			Expect(hub.Status.OperatorsStatus).Should(HaveLen(2))
+			Expect(hub.Status.OperatorsStatus[0].Name).Should(Equal("etcd"))
+
+			dst := &OperandRegistry{}
+			Expect(dst.ConvertFrom(hub)).To(Succeed())
+			Expect(dst).Should(Equal(src))
+		})
+	})
+
+	Context("Convert OperandConfig", func() {
+		It("Should round trip v1alpha1 through v1beta1 without loss", func() {
+			src := &OperandConfig{
+				ObjectMeta: meta,
+				Spec: OperandConfigSpec{
+					Services: []ConfigService{
+						{
+							Name:  "etcd",
+							State: "present",
+							Spec: map[string]runtime.RawExtension{
+								"etcdCluster": {Raw: []byte(`{"size":3}`)},
+							},
+						},
+					},
+				},
+				Status: OperandConfigStatus{
+					Phase: ServiceRunning,
+					ServiceStatus: map[string]CrStatus{
+						"etcd": {CrStatus: map[string]ServicePhase{"etcdCluster": ServiceRunning}},
+					},
+				},
+			}
+
+			hub := &v1beta1.OperandConfig{}
+			Expect(src.ConvertTo(hub)).To(Succeed())
+
+			dst := &OperandConfig{}
+			Expect(dst.ConvertFrom(hub)).To(Succeed())
+			Expect(dst).Should(Equal(src))
+		})
+	})
+
+	Context("Convert OperandBindInfo", func() {
+		It("Should round trip v1alpha1 through v1beta1 without loss", func() {
+			src := &OperandBindInfo{
+				ObjectMeta: meta,
+				Spec: OperandBindInfoSpec{
+					Operand:           "jenkins",
+					Registry:          "common-service",
+					RegistryNamespace: "ibm-common-services",
+					Description:       "Binding information that should be accessible to jenkins adopters",
+					Bindings: map[string]SecretConfigmap{
+						"public": {Secret: "secret1", Configmap: "cm1"},
+					},
+				},
+				Status: OperandBindInfoStatus{
+					Phase:             BindInfoCompleted,
+					RequestNamespaces: []string{"ibm-common-services"},
+				},
+			}
+
+			hub := &v1beta1.OperandBindInfo{}
+			Expect(src.ConvertTo(hub)).To(Succeed())
+
+			dst := &OperandBindInfo{}
+			Expect(dst.ConvertFrom(hub)).To(Succeed())
+			Expect(dst).Should(Equal(src))
+		})
+	})
+})
@@ -71,6 +71,9 @@ type SecretConfigmap struct {
 
 // OperandBindInfoStatus defines the observed state of OperandBindInfo.
 type OperandBindInfoStatus struct {
+	// ObservedGeneration is the most recent generation observed for this OperandBindInfo.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// Phase describes the overall phase of OperandBindInfo.
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:io.kubernetes.phase"
 	// +optional
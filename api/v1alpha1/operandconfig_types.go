@@ -17,6 +17,7 @@
 package v1alpha1
 
 import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -38,11 +39,45 @@ type ConfigService struct {
 	// Spec is the configuration map of custom resource.
 	Spec map[string]runtime.RawExtension `json:"spec"`
 	// State is a flag to enable or disable service.
+	// Valid values are:
+	// - "enabled" (default): the custom resources of the service are created;
+	// - "disabled": the custom resources of the service are not created, and the existing ones created by ODLM are deleted;
+	// +kubebuilder:validation:Enum=enabled;disabled
 	State string `json:"state,omitempty"`
+	// MergeStrategy defines how the lists in the alm-examples template are merged with the lists in the spec.
+	// The key is the dotted path of the list, starting with the key of the spec map, for example "jenkins.containers".
+	// The value is "replace" (default), "append", or "merge:<key>" to merge the items with the same value of the key field.
+	// +optional
+	MergeStrategy map[string]string `json:"mergeStrategy,omitempty"`
+	// Patches are the ordered JSON patch operations applied to the merged spec of the custom resources.
+	// The key is the key of the spec map.
+	// +optional
+	Patches map[string]JSONPatch `json:"patches,omitempty"`
+}
+
+// JSONPatch is an ordered list of JSON patch operations.
+type JSONPatch []JSONPatchOperation
+
+// JSONPatchOperation is a JSON patch operation defined in RFC 6902.
+type JSONPatchOperation struct {
+	// Op is the operation to perform.
+	// +kubebuilder:validation:Enum=add;remove;replace;move;copy;test
+	Op string `json:"op"`
+	// Path is the JSON pointer to the target location, relative to the spec of the custom resource.
+	Path string `json:"path"`
+	// From is the JSON pointer to the source location of the move and copy operations.
+	// +optional
+	From string `json:"from,omitempty"`
+	// Value is the value of the add, replace and test operations. It can be any JSON value.
+	// +optional
+	Value *apiextensionsv1.JSON `json:"value,omitempty"`
 }
 
 // OperandConfigStatus defines the observed state of OperandConfig.
 type OperandConfigStatus struct {
+	// ObservedGeneration is the most recent generation observed for this OperandConfig.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// Phase describes the overall phase of operands in the OperandConfig.
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:io.kubernetes.phase"
 	// +optional
@@ -50,6 +85,9 @@ type OperandConfigStatus struct {
 	// ServiceStatus defines all the status of a operator.
 	// +optional
 	ServiceStatus map[string]CrStatus `json:"serviceStatus,omitempty"`
+	// DisabledServices lists the services whose state is disabled.
+	// +optional
+	DisabledServices []string `json:"disabledServices,omitempty"`
 }
 
 // CrStatus defines the status of the custom resource.
@@ -92,10 +130,15 @@ const (
 	// when an OperandConfig is deleted.
 	ConfigFinalizer = "finalizer.config.ibm.com"
 
-	ServiceRunning ServicePhase = "Running"
-	ServiceFailed  ServicePhase = "Failed"
-	ServiceInit    ServicePhase = "Initialized"
-	ServiceNone    ServicePhase = ""
+	ServiceRunning  ServicePhase = "Running"
+	ServiceCreating ServicePhase = "Creating"
+	ServiceFailed   ServicePhase = "Failed"
+	ServiceInit     ServicePhase = "Initialized"
+	ServiceDisabled ServicePhase = "Disabled"
+	ServiceNone     ServicePhase = ""
+
+	ServiceStateEnabled  string = "enabled"
+	ServiceStateDisabled string = "disabled"
 )
 
 // GetService obtains the service definition with the operand name.
@@ -108,6 +151,11 @@ func (r *OperandConfig) GetService(operandName string) *ConfigService {
 	return nil
 }
 
+// IsEnabled checks if the service is enabled. The service is enabled when the state is not set.
+func (s *ConfigService) IsEnabled() bool {
+	return s.State != ServiceStateDisabled
+}
+
 //InitConfigServiceStatus initializes service status in the OperandConfig instance.
 func (r *OperandConfig) InitConfigServiceStatus() {
 	r.Status.ServiceStatus = make(map[string]CrStatus)
@@ -137,13 +185,18 @@ func (r *OperandConfig) UpdateOperandPhase() {
 			switch service {
 			case ServiceRunning:
 				operandStatusStat.runningNum++
+			case ServiceCreating:
+				operandStatusStat.notReadyNum++
 			case ServiceFailed:
 				operandStatusStat.failedNum++
 			}
 		}
 	}
+	r.Status.ObservedGeneration = r.Generation
 	if operandStatusStat.failedNum > 0 {
 		r.Status.Phase = ServiceFailed
+	} else if operandStatusStat.notReadyNum > 0 {
+		r.Status.Phase = ServiceCreating
 	} else if operandStatusStat.runningNum > 0 {
 		r.Status.Phase = ServiceRunning
 	} else {
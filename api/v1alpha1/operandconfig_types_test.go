@@ -0,0 +1,40 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OperandConfig service state", func() {
+
+	It("Should enable the service when the state is not set", func() {
+		service := &ConfigService{Name: "jenkins"}
+		Expect(service.IsEnabled()).Should(BeTrue())
+	})
+
+	It("Should enable the service when the state is enabled", func() {
+		service := &ConfigService{Name: "jenkins", State: ServiceStateEnabled}
+		Expect(service.IsEnabled()).Should(BeTrue())
+	})
+
+	It("Should disable the service when the state is disabled", func() {
+		service := &ConfigService{Name: "jenkins", State: ServiceStateDisabled}
+		Expect(service.IsEnabled()).Should(BeFalse())
+	})
+})
@@ -17,9 +17,13 @@
 package v1alpha1
 
 import (
+	"fmt"
+	"strings"
+
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -47,14 +51,23 @@ type Operator struct {
 	// +optional
 	Namespace string `json:"namespace,omitempty"`
 	// Name of a CatalogSource that defines where and how to find the channel.
+	// It is required when the operator is installed by OLM.
+	// With OLM v1, it is the name of the ClusterCatalog, and the package is resolved from all the ClusterCatalogs when it is empty.
+	// +optional
 	SourceName string `json:"sourceName"`
 	// The Kubernetes namespace where the CatalogSource used is located.
+	// It is required when the operator is installed by OLM. It isn't used by OLM v1.
+	// +optional
 	SourceNamespace string `json:"sourceNamespace"`
 	// The target namespace of the OperatorGroups.
 	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
 	// Name of the package that defines the applications.
+	// It is required when the operator is installed by OLM.
+	// +optional
 	PackageName string `json:"packageName"`
 	// Name of the channel to track.
+	// It is required when the operator is installed by OLM. It is optional with OLM v1.
+	// +optional
 	Channel string `json:"channel"`
 	// Description of a common service.
 	// +optional
@@ -62,6 +75,115 @@ type Operator struct {
 	// Approval mode for emitted InstallPlans.
 	// +optional
 	InstallPlanApproval olmv1alpha1.Approval `json:"installPlanApproval,omitempty"`
+	// StartingCSV is the name of the ClusterServiceVersion to install first.
+	// +optional
+	StartingCSV string `json:"startingCSV,omitempty"`
+	// VersionConstraint is the semantic version range the ClusterServiceVersion must satisfy, for example ">=3.5.0 <3.7.0".
+	// When it is set, the InstallPlans are approved by ODLM only if all their ClusterServiceVersions satisfy it,
+	// or if they are listed in the approval annotation on the OperandRequest or the OperandRegistry.
+	// With OLM v1, it is the version range of the ClusterExtension.
+	// +optional
+	VersionConstraint string `json:"versionConstraint,omitempty"`
+	// InstallPlanAllowlist is a list of the patterns of the ClusterServiceVersion names, like etcdoperator.v0.9.*.
+	// When it is set, the Subscription is created with the Manual approval, and the InstallPlans are approved by ODLM
+	// only if all their ClusterServiceVersions match one of the patterns. It isn't used when VersionConstraint is set.
+	// The other InstallPlans wait for the approval annotation on the OperandRequest or the OperandRegistry.
+	// +optional
+	InstallPlanAllowlist []string `json:"installPlanAllowlist,omitempty"`
+	// SubscriptionConfig is the configuration of the operator pods, like env, resources, nodeSelector and tolerations.
+	// It is set in the config of the Subscription. The config of the Subscription isn't managed if it is not set.
+	// +optional
+	SubscriptionConfig *olmv1alpha1.SubscriptionConfig `json:"subscriptionConfig,omitempty"`
+	// ServiceAccountName is the ServiceAccount in the namespace of the operator which OLM v1 uses to install the operator.
+	// It is required when the operator is installed by OLM v1.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// Dependencies is a list of the names of the operators in the same OperandRegistry that this operator depends on.
+	// The dependencies are installed before the operator, and uninstalled after it.
+	// +optional
+	Dependencies []string `json:"dependencies,omitempty"`
+	// Readiness defines the readiness of the custom resources of the operator by their status.
+	// If it is not set, the readiness is computed from the Ready and Available conditions of the custom resources.
+	// +optional
+	Readiness *ReadinessExpression `json:"readiness,omitempty"`
+	// Helm installs the operator as a Helm release instead of an OLM Subscription.
+	// The OLM fields, like sourceName, packageName and channel, are not used when it is set.
+	// +optional
+	Helm *HelmChart `json:"helm,omitempty"`
+	// Source installs the operator from the manifests instead of an OLM Subscription.
+	// The OLM fields, like sourceName, packageName and channel, are not used when it is set.
+	// +optional
+	Source *OperatorSource `json:"source,omitempty"`
+}
+
+// OperatorSource defines where the operator is installed from when it isn't installed by OLM.
+type OperatorSource struct {
+	// Manifests are the Kubernetes manifests of the operator, like its Deployment, RBAC and CRDs.
+	// ODLM applies them with its own service account, which is granted to manage CRDs, ClusterRoles,
+	// ClusterRoleBindings, Roles and RoleBindings, and to escalate and bind roles, so the manifests are
+	// only read from a ConfigMap when the OperandRegistry is in the namespace of ODLM.
+	// +optional
+	Manifests *ManifestsSource `json:"manifests,omitempty"`
+}
+
+// ManifestsSource defines the YAML or JSON manifests applied into the namespace of an operator.
+// Exactly one of path and configMap must be set.
+type ManifestsSource struct {
+	// Path is a directory of manifest files in the file system of ODLM.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// ConfigMap is the name of the ConfigMap in the namespace of the OperandRegistry which stores the manifests in its data.
+	// It can only be set when the OperandRegistry is in the namespace of ODLM.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+}
+
+// GetManifests returns the manifests of the operator, it returns nil if the operator isn't installed from manifests.
+func (o *Operator) GetManifests() *ManifestsSource {
+	if o.Source == nil {
+		return nil
+	}
+	return o.Source.Manifests
+}
+
+// HelmChart defines the Helm chart and the values of the release that installs an operator.
+// Exactly one of chartPath and chartConfigMap must be set.
+type HelmChart struct {
+	// ChartPath is the path of a chart directory or a packaged chart in the file system of ODLM.
+	// +optional
+	ChartPath string `json:"chartPath,omitempty"`
+	// ChartConfigMap is the ConfigMap in the namespace of the OperandRegistry which stores the packaged chart.
+	// It can only be set when the OperandRegistry is in the namespace of ODLM.
+	// +optional
+	ChartConfigMap *ChartConfigMapSelector `json:"chartConfigMap,omitempty"`
+	// ReleaseName is the name of the Helm release. The default is the name of the operator.
+	// +optional
+	ReleaseName string `json:"releaseName,omitempty"`
+	// Values override the default values of the chart.
+	// +nullable
+	// +optional
+	Values *runtime.RawExtension `json:"values,omitempty"`
+}
+
+// ChartConfigMapSelector selects the key of a ConfigMap which stores a packaged chart in its binaryData.
+type ChartConfigMapSelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+	// Key of the packaged chart in the binaryData of the ConfigMap. The default is "chart.tgz".
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// ReadinessExpression defines the readiness of a custom resource by the result of a JSONPath expression.
+type ReadinessExpression struct {
+	// JSONPath is the expression evaluated against the custom resource, for example "{.status.phase}".
+	JSONPath string `json:"jsonPath"`
+	// ReadyValues are the results of the expression when the custom resource is ready.
+	ReadyValues []string `json:"readyValues"`
+	// FailedValues are the results of the expression when the custom resource is failed.
+	// The custom resource is in progress if the result is neither ready nor failed.
+	// +optional
+	FailedValues []string `json:"failedValues,omitempty"`
 }
 
 // +kubebuilder:validation:Enum=public;private
@@ -83,16 +205,34 @@ const (
 	InstallModeNamespace string = "namespace"
 )
 
+const (
+	// InstallerGenerationV0 means install the operators by the Subscriptions of OLM v0.
+	InstallerGenerationV0 string = "v0"
+	// InstallerGenerationV1 means install the operators by the ClusterExtensions of OLM v1.
+	InstallerGenerationV1 string = "v1"
+)
+
 // OperandRegistrySpec defines the desired state of OperandRegistry.
 type OperandRegistrySpec struct {
 	// Operators is a list of operator OLM definition.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Operators Registry List"
 	// +optional
 	Operators []Operator `json:"operators,omitempty"`
+	// InstallerGeneration is the generation of OLM which installs the operators.
+	// Valid values are:
+	// - "v0" (default): the operators are installed by the Subscriptions of OLM v0;
+	// - "v1": the operators are installed by the ClusterExtensions of OLM v1;
+	// The operators with a Helm chart or manifests are installed from them with any generation.
+	// +kubebuilder:validation:Enum=v0;v1
+	// +optional
+	InstallerGeneration string `json:"installerGeneration,omitempty"`
 }
 
 // OperandRegistryStatus defines the observed state of OperandRegistry.
 type OperandRegistryStatus struct {
+	// ObservedGeneration is the most recent generation observed for this OperandRegistry.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// Phase describes the overall phase of operators in the OperandRegistry.
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:io.kubernetes.phase"
 	// +optional
@@ -100,8 +240,11 @@ type OperandRegistryStatus struct {
 	// OperatorsStatus defines operators status and the number of reconcile request.
 	// +optional
 	OperatorsStatus map[string]OperatorStatus `json:"operatorsStatus,omitempty"`
-	// Conditions represents the current state of the Request Service.
+	// Conditions represents the current state of the OperandRegistry.
+	// It only contains the aggregate Ready condition.
 	// +optional
+	// +listType=map
+	// +listMapKey=type
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
 	Conditions []Condition `json:"conditions,omitempty"`
 }
@@ -193,6 +336,24 @@ func (r *OperandRegistry) SetOperatorStatus(name string, phase OperatorPhase, re
 	r.Status.OperatorsStatus[name] = s
 }
 
+// Default sets the default value for the operators in the OperandRegistry spec.
+func (r *OperandRegistry) Default() {
+	if r.Spec.InstallerGeneration == "" {
+		r.Spec.InstallerGeneration = InstallerGenerationV0
+	}
+	for i, o := range r.Spec.Operators {
+		if o.Scope == "" {
+			r.Spec.Operators[i].Scope = ScopePrivate
+		}
+		if o.InstallMode == "" {
+			r.Spec.Operators[i].InstallMode = InstallModeNamespace
+		}
+		if o.InstallPlanApproval == "" {
+			r.Spec.Operators[i].InstallPlanApproval = olmv1alpha1.ApprovalAutomatic
+		}
+	}
+}
+
 // GetOperator obtains the operator definition with the operand name.
 func (r *OperandRegistry) GetOperator(operandName string) *Operator {
 	for _, o := range r.Spec.Operators {
@@ -203,6 +364,50 @@ func (r *OperandRegistry) GetOperator(operandName string) *Operator {
 	return nil
 }
 
+// ResolveDependencies returns the given operators and all the operators they depend on.
+// Every operator comes after its dependencies in the result, which is the order to install them.
+// The names which are not found in the OperandRegistry are kept in the result without dependencies.
+func (r *OperandRegistry) ResolveDependencies(names []string) ([]string, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	var order []string
+	state := make(map[string]int)
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("found dependency cycle %s in the OperandRegistry %s/%s", strings.Join(append(path, name), " -> "), r.Namespace, r.Name)
+		}
+		state[name] = visiting
+		if o := r.GetOperator(name); o != nil {
+			path = append(path[:len(path):len(path)], name)
+			for _, dep := range o.Dependencies {
+				if r.GetOperator(dep) == nil {
+					return fmt.Errorf("not found the dependency %s of the operator %s in the OperandRegistry %s/%s", dep, name, r.Namespace, r.Name)
+				}
+				if err := visit(dep, path); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
 // GetAllReconcileRequest gets all the ReconcileRequest from OperandRegistry status.
 func (r *OperandRegistry) GetAllReconcileRequest() []reconcile.Request {
 	maprrs := make(map[string]reconcile.Request)
@@ -223,30 +428,19 @@ func (r *OperandRegistry) GetAllReconcileRequest() []reconcile.Request {
 	return rrs
 }
 
-// SetReadyCondition creates a Condition to claim Ready.
-func (r *OperandRegistry) SetReadyCondition(name string, rt ResourceType, cs corev1.ConditionStatus) {
-	c := newCondition(ConditionReady, cs, string(rt)+" is ready", string(rt)+" "+name+" is ready")
-	r.setCondition(*c)
-}
-
-// SetNotFoundCondition creates a Condition to claim NotFound.
-func (r *OperandRegistry) SetNotFoundCondition(name string, rt ResourceType, cs corev1.ConditionStatus) {
-	c := newCondition(ConditionNotFound, cs, "Not found "+string(rt), "Not found "+string(rt)+" "+name)
-	r.setCondition(*c)
-}
+// UpdateRegistryPhase sets the current Phase status and the aggregate Ready condition.
+func (r *OperandRegistry) UpdateRegistryPhase(phase RegistryPhase) {
+	r.Status.Phase = phase
+	r.Status.ObservedGeneration = r.Generation
 
-func (r *OperandRegistry) setCondition(c Condition) {
-	pos, cp := getCondition(&r.Status.Conditions, c.Type, c.Message)
-	if cp != nil {
-		r.Status.Conditions[pos] = c
+	var c *Condition
+	if phase == RegistryReady || phase == RegistryRunning {
+		c = newCondition(ConditionReady, corev1.ConditionTrue, "Ready", "OperandRegistry is ready")
 	} else {
-		r.Status.Conditions = append(r.Status.Conditions, c)
+		c = newCondition(ConditionReady, corev1.ConditionFalse, "NotReady", "OperandRegistry is "+string(phase))
 	}
-}
-
-// UpdateRegistryPhase sets the current Phase status.
-func (r *OperandRegistry) UpdateRegistryPhase(phase RegistryPhase) {
-	r.Status.Phase = phase
+	c.ObservedGeneration = r.Generation
+	setCondition(&r.Status.Conditions, *c)
 }
 
 // RemoveFinalizer removes the operator source finalizer from the
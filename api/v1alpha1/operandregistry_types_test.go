@@ -0,0 +1,66 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("OperandRegistry dependencies", func() {
+
+	var registry *OperandRegistry
+
+	BeforeEach(func() {
+		registry = &OperandRegistry{
+			ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services"},
+			Spec: OperandRegistrySpec{
+				Operators: []Operator{
+					{Name: "iam", Dependencies: []string{"mongodb", "cert-manager"}},
+					{Name: "mongodb", Dependencies: []string{"cert-manager"}},
+					{Name: "cert-manager"},
+					{Name: "monitoring"},
+				},
+			},
+		}
+	})
+
+	It("Should order the operators after their dependencies", func() {
+		order, err := registry.ResolveDependencies([]string{"iam", "monitoring"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order).Should(Equal([]string{"cert-manager", "mongodb", "iam", "monitoring"}))
+	})
+
+	It("Should keep the operators which are not in the OperandRegistry", func() {
+		order, err := registry.ResolveDependencies([]string{"jenkins", "mongodb"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order).Should(Equal([]string{"jenkins", "cert-manager", "mongodb"}))
+	})
+
+	It("Should report the dependency which is not found", func() {
+		registry.Spec.Operators[3].Dependencies = []string{"jenkins"}
+		_, err := registry.ResolveDependencies([]string{"monitoring"})
+		Expect(err).Should(MatchError("not found the dependency jenkins of the operator monitoring in the OperandRegistry ibm-common-services/common-service"))
+	})
+
+	It("Should report the dependency cycle", func() {
+		registry.Spec.Operators[2].Dependencies = []string{"iam"}
+		_, err := registry.ResolveDependencies([]string{"monitoring", "iam"})
+		Expect(err).Should(MatchError("found dependency cycle iam -> mongodb -> cert-manager -> iam in the OperandRegistry ibm-common-services/common-service"))
+	})
+})
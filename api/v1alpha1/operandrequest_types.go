@@ -34,6 +34,15 @@ type OperandRequestSpec struct {
 	// Requests defines a list of operands installation.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Operators Request List"
 	Requests []Request `json:"requests"`
+	// DriftPolicy defines how the changes made outside ODLM to the fields rendered by ODLM in the custom resources are handled.
+	// "revert" (default) reports and reverts the changes, "report" only reports them, and "ignore" neither reports nor reverts them.
+	// +kubebuilder:validation:Enum=revert;report;ignore
+	// +optional
+	DriftPolicy string `json:"driftPolicy,omitempty"`
+	// ForegroundDeletion sets blockOwnerDeletion in the ownerReferences of the custom resources created in the namespace of the OperandRequest,
+	// so the OperandRequest deleted with the foreground propagation policy is only removed after its custom resources.
+	// +optional
+	ForegroundDeletion bool `json:"foregroundDeletion,omitempty"`
 }
 
 // Request identifies a operand detail.
@@ -66,11 +75,40 @@ type Operand struct {
 	// It is the name of the custom resource.
 	// +optional
 	InstanceName string `json:"instanceName,omitempty"`
+	// Namespace is the namespace of the custom resource created from the kind.
+	// The default is the namespace of the OperandRequest. The requester must be allowed to create the kind in the namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 	// Spec is used when users want to deploy multiple custom resources.
 	// It is the configuration map of custom resource.
 	// +nullable
 	// +optional
 	Spec *runtime.RawExtension `json:"spec,omitempty"`
+	// MergeStrategy defines how the lists in the alm-examples template are merged with the lists in the spec.
+	// The key is the dotted path of the list in the spec, for example "containers".
+	// The value is "replace" (default), "append", or "merge:<key>" to merge the items with the same value of the key field.
+	// +optional
+	MergeStrategy map[string]string `json:"mergeStrategy,omitempty"`
+	// Patches are the ordered JSON patch operations applied to the merged spec of the custom resource.
+	// +optional
+	Patches JSONPatch `json:"patches,omitempty"`
+	// Resources are the custom resources created from the operator's alm-examples, one for each entry.
+	// Resources can't be set with the kind, instanceName and spec of the operand.
+	// +optional
+	Resources []OperandResource `json:"resources,omitempty"`
+}
+
+// OperandResource defines a custom resource created for an operand.
+type OperandResource struct {
+	// Kind identifies the kind of the custom resource.
+	Kind string `json:"kind"`
+	// Name is the name of the custom resource. The default is the name of the OperandRequest.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Spec is the configuration map of the custom resource.
+	// +nullable
+	// +optional
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
 }
 
 // ConditionType is the condition of a service.
@@ -91,12 +129,20 @@ const (
 	// when an OperandRequest is deleted.
 	RequestFinalizer = "finalizer.request.ibm.com"
 
-	ConditionCreating   ConditionType = "Creating"
-	ConditionUpdating   ConditionType = "Updating"
-	ConditionDeleting   ConditionType = "Deleting"
-	ConditionNotFound   ConditionType = "NotFound"
-	ConditionOutofScope ConditionType = "OutofScope"
-	ConditionReady      ConditionType = "Ready"
+	ConditionCreating        ConditionType = "Creating"
+	ConditionUpdating        ConditionType = "Updating"
+	ConditionDeleting        ConditionType = "Deleting"
+	ConditionNotFound        ConditionType = "NotFound"
+	ConditionOutofScope      ConditionType = "OutofScope"
+	ConditionWaiting         ConditionType = "Waiting"
+	ConditionOutofConstraint ConditionType = "OutofConstraint"
+	ConditionUnresolved      ConditionType = "Unresolved"
+	ConditionPatchFailed     ConditionType = "PatchFailed"
+	ConditionForbidden       ConditionType = "Forbidden"
+	ConditionDrifted         ConditionType = "Drifted"
+	ConditionOperandNotReady ConditionType = "OperandNotReady"
+	ConditionPendingApproval ConditionType = "PendingApproval"
+	ConditionReady           ConditionType = "Ready"
 
 	OperatorReady      OperatorPhase = "Ready for Deployment"
 	OperatorRunning    OperatorPhase = "Running"
@@ -119,8 +165,41 @@ const (
 	ResourceTypeCsv             ResourceType = "csv"
 	ResourceTypeOperator        ResourceType = "operator"
 	ResourceTypeOperand         ResourceType = "operands"
+	ResourceTypeHelmRelease     ResourceType = "helmrelease"
+	ResourceTypeManifests       ResourceType = "manifests"
+	ResourceTypeExtension       ResourceType = "clusterextension"
+
+	DriftPolicyRevert string = "revert"
+	DriftPolicyReport string = "report"
+	DriftPolicyIgnore string = "ignore"
 )
 
+// Reason returns the resource type in the CamelCase form used by the condition reason.
+// A resource type not listed here is reported as "Resource".
+func (rt ResourceType) Reason() string {
+	switch rt {
+	case ResourceTypeOperandRegistry:
+		return "OperandRegistry"
+	case ResourceTypeCatalogSource:
+		return "CatalogSource"
+	case ResourceTypeSub:
+		return "Subscription"
+	case ResourceTypeCsv:
+		return "ClusterServiceVersion"
+	case ResourceTypeOperator:
+		return "Operator"
+	case ResourceTypeOperand:
+		return "Operand"
+	case ResourceTypeHelmRelease:
+		return "HelmRelease"
+	case ResourceTypeManifests:
+		return "Manifests"
+	case ResourceTypeExtension:
+		return "ClusterExtension"
+	}
+	return "Resource"
+}
+
 // Condition represents the current state of the Request Service.
 // A condition might not show up if it is not happening.
 type Condition struct {
@@ -128,7 +207,11 @@ type Condition struct {
 	Type ConditionType `json:"type"`
 	// Status of the condition, one of True, False, Unknown.
 	Status corev1.ConditionStatus `json:"status"`
+	// ObservedGeneration is the .metadata.generation that the condition was set based upon.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// The last time this condition was updated.
+	// Deprecated: it is no longer set, and it isn't kept by the v1beta1 storage version.
 	// +optional
 	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
 	// Last time the condition transitioned from one status to another.
@@ -144,8 +227,14 @@ type Condition struct {
 
 // OperandRequestStatus defines the observed state of OperandRequest.
 type OperandRequestStatus struct {
+	// ObservedGeneration is the most recent generation observed for this OperandRequest.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// Conditions represents the current state of the Request Service.
+	// It only contains the aggregate Ready condition, the details of each operand are in the Members.
 	// +optional
+	// +listType=map
+	// +listMapKey=type
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
 	Conditions []Condition `json:"conditions,omitempty"`
 	// Members represnets the current operand status of the set.
@@ -178,6 +267,10 @@ type OperandCRMember struct {
 	// APIVersion is the APIVersion of the custom resource.
 	// +optional
 	APIVersion string `json:"apiVersion,omitempty"`
+	// Namespace is the namespace of the custom resource.
+	// The custom resources recorded without the namespace are in the namespace of the OperandRequest.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // MemberStatus shows if the Operator is ready.
@@ -187,9 +280,35 @@ type MemberStatus struct {
 	// The operand phase include None, Creating, Running, Failed.
 	// +optional
 	Phase MemberPhase `json:"phase,omitempty"`
+	// Registry is the name of the OperandRegistry the operator of the member is installed from.
+	// +optional
+	Registry string `json:"registry,omitempty"`
+	// RegistryNamespace is the namespace of the OperandRegistry the operator of the member is installed from.
+	// +optional
+	RegistryNamespace string `json:"registryNamespace,omitempty"`
 	// OperandCRList shows the list of custom resource created by OperandRequest.
 	// +optional
 	OperandCRList []OperandCRMember `json:"operandCRList,omitempty"`
+	// Conditions represents the current state of the operator and operand of the member.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []Condition `json:"conditions,omitempty"`
+	// InstallPlans are the InstallPlans of the operator waiting for the approval.
+	// +optional
+	InstallPlans []PendingInstallPlan `json:"installPlans,omitempty"`
+}
+
+// PendingInstallPlan is an InstallPlan waiting for the approval, with the ClusterServiceVersions it installs.
+type PendingInstallPlan struct {
+	// Name is the name of the InstallPlan.
+	Name string `json:"name"`
+	// Namespace is the namespace of the InstallPlan.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// ClusterServiceVersions are the names of the ClusterServiceVersions the InstallPlan installs.
+	// +optional
+	ClusterServiceVersions []string `json:"clusterServiceVersions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -218,65 +337,203 @@ type OperandRequestList struct {
 	Items           []OperandRequest `json:"items"`
 }
 
-// SetCreatingCondition creates a new condition status.
+// SetCreatingCondition sets the Creating condition of the member.
+// The member doesn't wait for its dependencies anymore once it is being created.
 func (r *OperandRequest) SetCreatingCondition(name string, rt ResourceType, cs corev1.ConditionStatus) {
-	c := newCondition(ConditionCreating, cs, "Creating "+string(rt), "Creating "+string(rt)+" "+name)
-	r.setCondition(*c)
+	c := newCondition(ConditionCreating, cs, "Creating"+rt.Reason(), "Creating "+string(rt)+" "+name)
+	r.setMemberCondition(name, *c)
+	r.RemoveMemberCondition(name, ConditionWaiting)
 }
 
-// SetUpdatingCondition creates an updating condition status.
+// SetUpdatingCondition sets the Updating condition of the member.
 func (r *OperandRequest) SetUpdatingCondition(name string, rt ResourceType, cs corev1.ConditionStatus) {
-	c := newCondition(ConditionUpdating, cs, "Updating "+string(rt), "Updating "+string(rt)+" "+name)
-	r.setCondition(*c)
+	c := newCondition(ConditionUpdating, cs, "Updating"+rt.Reason(), "Updating "+string(rt)+" "+name)
+	r.setMemberCondition(name, *c)
 }
 
-// SetDeletingCondition creates a deleting condition status.
+// SetDeletingCondition sets the Deleting condition of the member.
 func (r *OperandRequest) SetDeletingCondition(name string, rt ResourceType, cs corev1.ConditionStatus) {
-	c := newCondition(ConditionDeleting, cs, "Deleting "+string(rt), "Deleting "+string(rt)+" "+name)
-	r.setCondition(*c)
+	c := newCondition(ConditionDeleting, cs, "Deleting"+rt.Reason(), "Deleting "+string(rt)+" "+name)
+	r.setMemberCondition(name, *c)
 }
 
-// SetNotFoundOperatorFromRegistryCondition creates a NotFoundCondition when an operator is not found.
+// SetNotFoundOperatorFromRegistryCondition sets the NotFound condition of the member when an operator is not found.
 func (r *OperandRequest) SetNotFoundOperatorFromRegistryCondition(name string, rt ResourceType, cs corev1.ConditionStatus) {
-	c := newCondition(ConditionNotFound, cs, "Not found "+string(rt), "Not found "+string(rt)+" "+name+" in the cluster")
-	r.setCondition(*c)
+	c := newCondition(ConditionNotFound, cs, "NotFound"+rt.Reason(), "Not found "+string(rt)+" "+name+" in the cluster")
+	r.setMemberCondition(name, *c)
 }
 
-// SetOutofScopeCondition creates a NotFoundCondition.
+// SetOutofScopeCondition sets the OutofScope condition of the member when a private operator is requested.
 func (r *OperandRequest) SetOutofScopeCondition(name string, rt ResourceType, cs corev1.ConditionStatus) {
-	c := newCondition(ConditionOutofScope, cs, string(rt)+" "+name+" is a private operator", string(rt)+" "+name+" is a private operator. It can only be request within the OperandRegistry namespace")
-	r.setCondition(*c)
+	c := newCondition(ConditionOutofScope, cs, "Private"+rt.Reason(), string(rt)+" "+name+" is a private operator. It can only be request within the OperandRegistry namespace")
+	r.setMemberCondition(name, *c)
 }
 
-// SetNotFoundOperandRegistryCondition creates a NotFoundCondition when an operandRegistry is not found.
-func (r *OperandRequest) SetNotFoundOperandRegistryCondition(name string, rt ResourceType, cs corev1.ConditionStatus) {
-	c := newCondition(ConditionNotFound, cs, "Not found "+string(rt), "Not found operandRegistry "+string(rt))
-	r.setCondition(*c)
+// SetWaitingForDependencyCondition sets the Waiting condition of the member when the ClusterServiceVersion of its dependency hasn't succeeded.
+func (r *OperandRequest) SetWaitingForDependencyCondition(name, dependency string) {
+	c := newCondition(ConditionWaiting, corev1.ConditionTrue, "WaitingForDependency", "Waiting for the "+string(ResourceTypeCsv)+" of the dependency "+dependency+" to succeed")
+	r.setMemberCondition(name, *c)
 }
 
-// SetReadyCondition creates a Condition to claim Ready.
-func (r *OperandRequest) SetReadyCondition(name string, rt ResourceType, cs corev1.ConditionStatus) {
-	c := &Condition{}
-	if rt == ResourceTypeOperator {
-		c = newCondition(ConditionReady, cs, string(rt)+" is ready", string(rt)+" "+name+" is ready")
-	} else if rt == ResourceTypeOperand {
-		c = newCondition(ConditionReady, cs, string(rt)+" are created", string(rt)+" from "+name+" are created")
+// SetOutofConstraintCondition sets the OutofConstraint condition of the member when its ClusterServiceVersions don't satisfy the version constraint.
+func (r *OperandRequest) SetOutofConstraintCondition(name string, csvNames []string, constraint string) {
+	c := newCondition(ConditionOutofConstraint, corev1.ConditionTrue, "CSVOutofConstraint", string(ResourceTypeCsv)+" "+strings.Join(csvNames, ", ")+" doesn't satisfy the version constraint "+constraint)
+	r.setMemberCondition(name, *c)
+}
+
+// SetPendingInstallPlans records the InstallPlans of the member waiting for the approval, and sets its PendingApproval condition.
+// The condition is removed when no InstallPlan is pending.
+func (r *OperandRequest) SetPendingInstallPlans(name string, plans []PendingInstallPlan) {
+	if len(plans) == 0 {
+		if pos, _ := getMemberStatus(&r.Status, name); pos != -1 {
+			r.Status.Members[pos].InstallPlans = nil
+			removeCondition(&r.Status.Members[pos].Conditions, ConditionPendingApproval)
+		}
+		return
+	}
+	var details []string
+	for _, p := range plans {
+		details = append(details, "InstallPlan "+p.Name+" installs "+strings.Join(p.ClusterServiceVersions, ", "))
 	}
-	r.setCondition(*c)
+	c := newCondition(ConditionPendingApproval, corev1.ConditionTrue, "InstallPlanPendingApproval", strings.Join(details, "; ")+", waiting for the approval")
+	r.setMemberCondition(name, *c)
+	pos, _ := getMemberStatus(&r.Status, name)
+	r.Status.Members[pos].InstallPlans = plans
 }
 
-func (r *OperandRequest) setCondition(c Condition) {
-	pos, cp := getCondition(&r.Status.Conditions, c.Type, c.Message)
-	if cp != nil {
-		r.Status.Conditions[pos] = c
-	} else {
-		r.Status.Conditions = append(r.Status.Conditions, c)
+// SetUnresolvedTemplateCondition sets the Unresolved condition of the member when the templates in its spec can't be resolved.
+func (r *OperandRequest) SetUnresolvedTemplateCondition(name, message string) {
+	c := newCondition(ConditionUnresolved, corev1.ConditionTrue, "UnresolvedTemplate", "Unresolved template in the spec of the operand "+name+": "+message)
+	r.setMemberCondition(name, *c)
+}
+
+// SetPatchFailedCondition sets the PatchFailed condition of the member when its JSON patches fail to apply.
+func (r *OperandRequest) SetPatchFailedCondition(name, message string) {
+	c := newCondition(ConditionPatchFailed, corev1.ConditionTrue, "JSONPatchFailed", message)
+	r.setMemberCondition(name, *c)
+}
+
+// SetForbiddenCondition sets the Forbidden condition of the member when its requester isn't allowed to create
+// its custom resources in the target namespace.
+func (r *OperandRequest) SetForbiddenCondition(name, message string) {
+	c := newCondition(ConditionForbidden, corev1.ConditionTrue, "NamespaceForbidden", message)
+	r.setMemberCondition(name, *c)
+}
+
+// SetOperandNotReadyCondition reports the custom resources of the member which are not ready by their status.
+func (r *OperandRequest) SetOperandNotReadyCondition(name, reason, message string) {
+	c := newCondition(ConditionOperandNotReady, corev1.ConditionTrue, reason, message)
+	r.setMemberCondition(name, *c)
+}
+
+// SetDriftedCondition reports the custom resources of the member changed outside ODLM.
+func (r *OperandRequest) SetDriftedCondition(name, message string) {
+	c := newCondition(ConditionDrifted, corev1.ConditionTrue, "SpecDrifted", message)
+	r.setMemberCondition(name, *c)
+}
+
+// GetDriftPolicy returns the drift policy of the OperandRequest, the default is revert.
+func (r *OperandRequest) GetDriftPolicy() string {
+	if r.Spec.DriftPolicy == "" {
+		return DriftPolicyRevert
+	}
+	return r.Spec.DriftPolicy
+}
+
+// HasMemberCondition checks if the member has the condition with the type.
+func (r *OperandRequest) HasMemberCondition(name string, t ConditionType) bool {
+	if pos, _ := getMemberStatus(&r.Status, name); pos != -1 {
+		_, c := getCondition(&r.Status.Members[pos].Conditions, t)
+		return c != nil
+	}
+	return false
+}
+
+// RemoveMemberCondition removes the condition with the type from the member.
+func (r *OperandRequest) RemoveMemberCondition(name string, t ConditionType) {
+	if pos, _ := getMemberStatus(&r.Status, name); pos != -1 {
+		removeCondition(&r.Status.Members[pos].Conditions, t)
+	}
+}
+
+// SetNotFoundOperandRegistryCondition sets the Ready condition to False when an OperandRegistry is not found.
+func (r *OperandRequest) SetNotFoundOperandRegistryCondition(name string, rt ResourceType) {
+	c := newCondition(ConditionReady, corev1.ConditionFalse, "NotFound"+rt.Reason(), "Not found "+string(rt)+" "+name)
+	c.ObservedGeneration = r.Generation
+	setCondition(&r.Status.Conditions, *c)
+}
+
+// SetReadyCondition summarizes the phase and the members into the aggregate Ready condition.
+func (r *OperandRequest) SetReadyCondition() {
+	var notReady []string
+	for _, m := range r.Status.Members {
+		if m.Phase.OperatorPhase == OperatorRunning && m.Phase.OperandPhase != ServiceFailed && m.Phase.OperandPhase != ServiceCreating {
+			continue
+		}
+		detail := m.Name + ": operator " + string(m.Phase.OperatorPhase)
+		if m.Phase.OperatorPhase == OperatorNone {
+			detail = m.Name + ": operator is not installed"
+		}
+		if m.Phase.OperandPhase == ServiceFailed || m.Phase.OperandPhase == ServiceCreating {
+			detail += ", operand " + string(m.Phase.OperandPhase)
+		}
+		for _, mc := range m.Conditions {
+			if mc.Status == corev1.ConditionTrue {
+				detail += ", " + mc.Message
+			}
+		}
+		notReady = append(notReady, detail)
 	}
+
+	var c *Condition
+	switch {
+	case r.Status.Phase == ClusterPhaseRunning && len(notReady) == 0:
+		c = newCondition(ConditionReady, corev1.ConditionTrue, "Ready", "All the operators and operands are ready")
+	case len(notReady) == 0:
+		c = newCondition(ConditionReady, corev1.ConditionFalse, string(r.Status.Phase), "Waiting for the operators and operands to be deployed")
+	case r.Status.Phase == ClusterPhaseRunning:
+		c = newCondition(ConditionReady, corev1.ConditionFalse, "NotReady", strings.Join(notReady, "; "))
+	default:
+		c = newCondition(ConditionReady, corev1.ConditionFalse, string(r.Status.Phase), strings.Join(notReady, "; "))
+	}
+	c.ObservedGeneration = r.Generation
+	setCondition(&r.Status.Conditions, *c)
 }
 
-func getCondition(conds *[]Condition, t ConditionType, msg string) (int, *Condition) {
+func (r *OperandRequest) setMemberCondition(name string, c Condition) {
+	pos, m := getMemberStatus(&r.Status, name)
+	if m == nil {
+		r.Status.Members = append(r.Status.Members, newMemberStatus(name, OperatorNone, ServiceNone))
+		pos = len(r.Status.Members) - 1
+	}
+	c.ObservedGeneration = r.Generation
+	setCondition(&r.Status.Members[pos].Conditions, c)
+}
+
+// setCondition sets the condition with the semantics of meta.SetStatusCondition.
+// There is only one condition for each type, the LastTransitionTime is only changed
+// when the status changes, and the other fields are always updated.
+func setCondition(conds *[]Condition, c Condition) {
+	pos, cp := getCondition(conds, c.Type)
+	if cp == nil {
+		*conds = append(*conds, c)
+		return
+	}
+	if cp.Status == c.Status {
+		c.LastTransitionTime = cp.LastTransitionTime
+	}
+	(*conds)[pos] = c
+}
+
+func removeCondition(conds *[]Condition, t ConditionType) {
+	if pos, cp := getCondition(conds, t); cp != nil {
+		*conds = append((*conds)[:pos], (*conds)[pos+1:]...)
+	}
+}
+
+func getCondition(conds *[]Condition, t ConditionType) (int, *Condition) {
 	for i, c := range *conds {
-		if t == c.Type && msg == c.Message {
+		if t == c.Type {
 			return i, &c
 		}
 	}
@@ -284,11 +541,10 @@ func getCondition(conds *[]Condition, t ConditionType, msg string) (int, *Condit
 }
 
 func newCondition(condType ConditionType, status corev1.ConditionStatus, reason, message string) *Condition {
-	now := time.Now().Format(time.RFC3339)
+	now := time.Now().UTC().Format(time.RFC3339)
 	return &Condition{
 		Type:               condType,
 		Status:             status,
-		LastUpdateTime:     now,
 		LastTransitionTime: now,
 		Reason:             reason,
 		Message:            message,
@@ -301,38 +557,60 @@ func (r *OperandRequest) SetMemberStatus(name string, operatorPhase OperatorPhas
 	if m != nil {
 		if operatorPhase != "" && operatorPhase != m.Phase.OperatorPhase {
 			r.Status.Members[pos].Phase.OperatorPhase = operatorPhase
-			r.setOperatorReadyCondition(operatorPhase, name)
 		}
 		if operandPhase != "" && operandPhase != m.Phase.OperandPhase {
 			r.Status.Members[pos].Phase.OperandPhase = operandPhase
-			r.setOperandReadyCondition(operandPhase, name)
 		}
 	} else {
 		newM := newMemberStatus(name, operatorPhase, operandPhase)
 		r.Status.Members = append(r.Status.Members, newM)
-		r.setOperatorReadyCondition(operatorPhase, name)
+		pos = len(r.Status.Members) - 1
+	}
+	r.Status.Members[pos].refreshConditions()
+}
+
+// refreshConditions removes the conditions which no longer apply to the member phase.
+func (m *MemberStatus) refreshConditions() {
+	if m.Phase.OperatorPhase == OperatorNone {
+		return
+	}
+	// The operator has been found in the OperandRegistry
+	removeCondition(&m.Conditions, ConditionNotFound)
+	removeCondition(&m.Conditions, ConditionOutofScope)
+	if m.Phase.OperatorPhase == OperatorRunning {
+		removeCondition(&m.Conditions, ConditionWaiting)
+		removeCondition(&m.Conditions, ConditionCreating)
+		removeCondition(&m.Conditions, ConditionUpdating)
 	}
 }
 
 // SetMemberCRStatus appends a Member CR in the Member status list.
-func (r *OperandRequest) SetMemberCRStatus(name, CRName, CRKind, CRAPIVersion string) {
+func (r *OperandRequest) SetMemberCRStatus(name, CRName, CRKind, CRAPIVersion, CRNamespace string) {
 	pos, m := getMemberStatus(&r.Status, name)
 	if m != nil {
 		for _, OperandCR := range r.Status.Members[pos].OperandCRList {
-			if OperandCR.Kind == CRKind && OperandCR.Name == CRName {
+			if OperandCR.Kind == CRKind && OperandCR.Name == CRName && r.GetMemberCRNamespace(OperandCR) == CRNamespace {
 				return
 			}
 		}
-		r.Status.Members[pos].OperandCRList = append(r.Status.Members[pos].OperandCRList, OperandCRMember{APIVersion: CRAPIVersion, Kind: CRKind, Name: CRName})
+		r.Status.Members[pos].OperandCRList = append(r.Status.Members[pos].OperandCRList, OperandCRMember{APIVersion: CRAPIVersion, Kind: CRKind, Name: CRName, Namespace: CRNamespace})
 	}
 }
 
+// GetMemberCRNamespace returns the namespace of the custom resource in the member status.
+func (r *OperandRequest) GetMemberCRNamespace(cr OperandCRMember) string {
+	if cr.Namespace == "" {
+		return r.Namespace
+	}
+	return cr.Namespace
+}
+
 // RemoveMemberCRStatus removes a Member CR in the Member status list.
-func (r *OperandRequest) RemoveMemberCRStatus(name, CRName, CRKind string) {
+func (r *OperandRequest) RemoveMemberCRStatus(name, CRName, CRKind, CRNamespace string) {
 	pos, m := getMemberStatus(&r.Status, name)
 	if m != nil {
 		for index, OperandCR := range r.Status.Members[pos].OperandCRList {
-			if OperandCR.Kind == CRKind && OperandCR.Name == CRName {
+			if OperandCR.Kind == CRKind && OperandCR.Name == CRName && r.GetMemberCRNamespace(OperandCR) == CRNamespace {
 				r.Status.Members[pos].OperandCRList = append(r.Status.Members[pos].OperandCRList[:index], r.Status.Members[pos].OperandCRList[index+1:]...)
 			}
 		}
@@ -340,27 +618,33 @@ func (r *OperandRequest) RemoveMemberCRStatus(name, CRName, CRKind string) {
 	}
 }
 
-func (r *OperandRequest) setOperatorReadyCondition(operatorPhase OperatorPhase, name string) {
-	if operatorPhase == OperatorRunning {
-		r.SetReadyCondition(name, ResourceTypeOperator, corev1.ConditionTrue)
-	} else {
-		r.SetReadyCondition(name, ResourceTypeOperator, corev1.ConditionFalse)
+// SetMemberRegistry records the OperandRegistry the operator of the member is installed from.
+func (r *OperandRequest) SetMemberRegistry(name string, registryKey types.NamespacedName) {
+	pos, m := getMemberStatus(&r.Status, name)
+	if m == nil {
+		r.Status.Members = append(r.Status.Members, newMemberStatus(name, OperatorNone, ServiceNone))
+		pos = len(r.Status.Members) - 1
 	}
+	r.Status.Members[pos].Registry = registryKey.Name
+	r.Status.Members[pos].RegistryNamespace = registryKey.Namespace
 }
 
-func (r *OperandRequest) setOperandReadyCondition(operandPhase ServicePhase, name string) {
-	if operandPhase == ServiceRunning {
-		r.SetReadyCondition(name, ResourceTypeOperand, corev1.ConditionTrue)
-	} else {
-		r.SetReadyCondition(name, ResourceTypeOperand, corev1.ConditionFalse)
+// GetMemberRegistryKey returns the OperandRegistry the operator of the member is installed from.
+// The member which was installed before the OperandRegistry is recorded returns false.
+func (r *OperandRequest) GetMemberRegistryKey(name string) (types.NamespacedName, bool) {
+	_, m := getMemberStatus(&r.Status, name)
+	if m == nil || m.Registry == "" {
+		return types.NamespacedName{}, false
 	}
+	return types.NamespacedName{Namespace: m.RegistryNamespace, Name: m.Registry}, true
 }
 
 // FreshMemberStatus cleanup Member status from the Member status list.
-func (r *OperandRequest) FreshMemberStatus() {
+// The members which are dependencies of the requested operands are kept.
+func (r *OperandRequest) FreshMemberStatus(dependencies ...string) {
 	newMembers := []MemberStatus{}
 	for index, m := range r.Status.Members {
-		if foundOperand(r.Spec.Requests, m.Name) {
+		if foundOperand(r.Spec.Requests, m.Name) || containsString(dependencies, m.Name) {
 			newMembers = append(newMembers, r.Status.Members[index])
 		}
 	}
@@ -378,6 +662,15 @@ func foundOperand(requests []Request, name string) bool {
 	return false
 }
 
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
 func getMemberStatus(status *OperandRequestStatus, name string) (int, *MemberStatus) {
 	for i, m := range status.Members {
 		if name == m.Name {
@@ -433,6 +726,8 @@ func (r *OperandRequest) UpdateClusterPhase() {
 		switch m.Phase.OperandPhase {
 		case ServiceRunning:
 			clusterStatusStat.runningNum++
+		case ServiceCreating:
+			clusterStatusStat.creatingNum++
 		case ServiceFailed:
 			clusterStatusStat.failedNum++
 		default:
@@ -452,6 +747,17 @@ func (r *OperandRequest) UpdateClusterPhase() {
 		clusterPhase = ClusterPhaseNone
 	}
 	r.SetClusterPhase(clusterPhase)
+	r.Status.ObservedGeneration = r.Generation
+	r.SetReadyCondition()
+}
+
+// Default sets the default value for the requests in the OperandRequest spec.
+func (r *OperandRequest) Default() {
+	for i, req := range r.Spec.Requests {
+		if req.RegistryNamespace == "" {
+			r.Spec.Requests[i].RegistryNamespace = r.Namespace
+		}
+	}
 }
 
 // GetRegistryKey Set the default value for Request spec.
@@ -464,6 +770,31 @@ func (r *OperandRequest) GetRegistryKey(req Request) types.NamespacedName {
 	return types.NamespacedName{Namespace: regNs, Name: regName}
 }
 
+// GetOperandNamespace returns the namespace of the custom resource created from the kind of the operand.
+func (r *OperandRequest) GetOperandNamespace(operand Operand) string {
+	if operand.Namespace == "" {
+		return r.Namespace
+	}
+	return operand.Namespace
+}
+
+// GetOperandResources returns the custom resources created for the operand, from its resources or its kind,
+// with the default names set. It is empty when the custom resources are created from the OperandConfig.
+func (r *OperandRequest) GetOperandResources(operand Operand) []OperandResource {
+	var resources []OperandResource
+	if len(operand.Resources) != 0 {
+		resources = append(resources, operand.Resources...)
+	} else if operand.Kind != "" {
+		resources = append(resources, OperandResource{Kind: operand.Kind, Name: operand.InstanceName, Spec: operand.Spec})
+	}
+	for i := range resources {
+		if resources[i].Name == "" {
+			resources[i].Name = r.Name
+		}
+	}
+	return resources
+}
+
 //InitRequestStatus OperandConfig status.
 func (r *OperandRequest) InitRequestStatus() bool {
 	isInitialized := true
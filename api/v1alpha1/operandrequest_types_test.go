@@ -0,0 +1,178 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("OperandRequest conditions", func() {
+
+	var req *OperandRequest
+
+	BeforeEach(func() {
+		req = &OperandRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services", Generation: 2},
+			Spec: OperandRequestSpec{
+				Requests: []Request{
+					{Registry: "common-service", Operands: []Operand{{Name: "etcd"}, {Name: "jenkins"}}},
+				},
+			},
+		}
+	})
+
+	It("Should only keep the aggregate Ready condition in the status", func() {
+		req.SetCreatingCondition("etcd", ResourceTypeSub, corev1.ConditionTrue)
+		req.SetMemberStatus("etcd", OperatorInstalling, "")
+		req.UpdateClusterPhase()
+
+		Expect(req.Status.ObservedGeneration).Should(Equal(int64(2)))
+		Expect(req.Status.Conditions).Should(HaveLen(1))
+		ready := req.Status.Conditions[0]
+		Expect(ready.Type).Should(Equal(ConditionReady))
+		Expect(ready.Status).Should(Equal(corev1.ConditionFalse))
+		Expect(ready.Reason).Should(Equal(string(ClusterPhaseInstalling)))
+		Expect(ready.Message).Should(ContainSubstring("Creating subscription etcd"))
+		Expect(ready.ObservedGeneration).Should(Equal(int64(2)))
+
+		req.SetMemberStatus("etcd", OperatorRunning, ServiceRunning)
+		req.UpdateClusterPhase()
+		Expect(req.Status.Conditions).Should(HaveLen(1))
+		Expect(req.Status.Conditions[0].Status).Should(Equal(corev1.ConditionTrue))
+		Expect(req.Status.Conditions[0].Reason).Should(Equal("Ready"))
+	})
+
+	It("Should not be ready until the custom resources of the operands are ready", func() {
+		req.SetMemberStatus("etcd", OperatorRunning, ServiceCreating)
+		req.SetOperandNotReadyCondition("etcd", "OperandCreating", "EtcdCluster ibm-common-services/example is Creating: Ready is False")
+		req.UpdateClusterPhase()
+		Expect(req.Status.Phase).Should(Equal(ClusterPhaseCreating))
+		Expect(req.Status.Conditions[0].Status).Should(Equal(corev1.ConditionFalse))
+		Expect(req.Status.Conditions[0].Message).Should(ContainSubstring("etcd: operator Running, operand Creating, EtcdCluster ibm-common-services/example is Creating"))
+
+		req.SetMemberStatus("etcd", OperatorRunning, ServiceRunning)
+		req.RemoveMemberCondition("etcd", ConditionOperandNotReady)
+		req.UpdateClusterPhase()
+		Expect(req.Status.Phase).Should(Equal(ClusterPhaseRunning))
+		Expect(req.Status.Conditions[0].Status).Should(Equal(corev1.ConditionTrue))
+	})
+
+	It("Should keep one condition per type in the member status", func() {
+		req.SetCreatingCondition("etcd", ResourceTypeSub, corev1.ConditionTrue)
+		req.SetCreatingCondition("etcd", ResourceTypeSub, corev1.ConditionTrue)
+		req.SetCreatingCondition("etcd", ResourceTypeSub, corev1.ConditionFalse)
+		req.SetUpdatingCondition("etcd", ResourceTypeSub, corev1.ConditionTrue)
+
+		Expect(req.Status.Members).Should(HaveLen(1))
+		conds := req.Status.Members[0].Conditions
+		Expect(conds).Should(HaveLen(2))
+		Expect(conds[0].Type).Should(Equal(ConditionCreating))
+		Expect(conds[0].Status).Should(Equal(corev1.ConditionFalse))
+		Expect(conds[0].Reason).Should(Equal("CreatingSubscription"))
+		Expect(conds[1].Type).Should(Equal(ConditionUpdating))
+	})
+
+	It("Should use the CamelCase resource type in the condition reason", func() {
+		Expect(ResourceTypeHelmRelease.Reason()).Should(Equal("HelmRelease"))
+		Expect(ResourceTypeExtension.Reason()).Should(Equal("ClusterExtension"))
+		Expect(ResourceType("unknown").Reason()).Should(Equal("Resource"))
+
+		req.SetUpdatingCondition("etcd", ResourceTypeManifests, corev1.ConditionTrue)
+		Expect(req.Status.Members[0].Conditions[0].Reason).Should(Equal("UpdatingManifests"))
+	})
+
+	It("Should keep the LastTransitionTime when the status doesn't change", func() {
+		req.Status.Members = []MemberStatus{
+			{
+				Name: "etcd",
+				Conditions: []Condition{
+					{
+						Type:               ConditionCreating,
+						Status:             corev1.ConditionTrue,
+						LastTransitionTime: "2021-01-02T03:04:05Z",
+						Reason:             "CreatingSubscription",
+						Message:            "Creating subscription etcd",
+						ObservedGeneration: 2,
+					},
+				},
+			},
+		}
+
+		req.SetCreatingCondition("etcd", ResourceTypeSub, corev1.ConditionTrue)
+		c := req.Status.Members[0].Conditions[0]
+		Expect(c.LastTransitionTime).Should(Equal("2021-01-02T03:04:05Z"))
+		Expect(c.LastUpdateTime).Should(BeEmpty())
+
+		req.SetCreatingCondition("etcd", ResourceTypeCsv, corev1.ConditionTrue)
+		c = req.Status.Members[0].Conditions[0]
+		Expect(c.LastTransitionTime).Should(Equal("2021-01-02T03:04:05Z"))
+		Expect(c.Reason).Should(Equal("CreatingClusterServiceVersion"))
+
+		req.SetCreatingCondition("etcd", ResourceTypeCsv, corev1.ConditionFalse)
+		c = req.Status.Members[0].Conditions[0]
+		Expect(c.LastTransitionTime).ShouldNot(Equal("2021-01-02T03:04:05Z"))
+	})
+
+	It("Should remove the conditions which no longer apply to the member", func() {
+		req.SetNotFoundOperatorFromRegistryCondition("jenkins", ResourceTypeOperator, corev1.ConditionTrue)
+		req.SetCreatingCondition("jenkins", ResourceTypeSub, corev1.ConditionTrue)
+		Expect(req.Status.Members[0].Conditions).Should(HaveLen(2))
+
+		req.SetMemberStatus("jenkins", OperatorInstalling, "")
+		Expect(req.Status.Members[0].Conditions).Should(HaveLen(1))
+		Expect(req.Status.Members[0].Conditions[0].Type).Should(Equal(ConditionCreating))
+
+		req.SetMemberStatus("jenkins", OperatorRunning, "")
+		Expect(req.Status.Members[0].Conditions).Should(BeEmpty())
+	})
+
+	It("Should report the missing OperandRegistry in the Ready condition", func() {
+		req.UpdateClusterPhase()
+		req.SetNotFoundOperandRegistryCondition("ibm-common-services/common-service", ResourceTypeOperandRegistry)
+
+		Expect(req.Status.Conditions).Should(HaveLen(1))
+		Expect(req.Status.Conditions[0].Status).Should(Equal(corev1.ConditionFalse))
+		Expect(req.Status.Conditions[0].Reason).Should(Equal("NotFoundOperandRegistry"))
+	})
+
+	It("Should keep the members which are dependencies of the requested operands", func() {
+		req.SetMemberStatus("etcd", OperatorRunning, "")
+		req.SetMemberStatus("jenkins", OperatorRunning, "")
+		req.SetMemberStatus("mongodb", OperatorRunning, "")
+		req.SetMemberStatus("redis", OperatorRunning, "")
+
+		req.FreshMemberStatus("mongodb")
+		var names []string
+		for _, m := range req.Status.Members {
+			names = append(names, m.Name)
+		}
+		Expect(names).Should(Equal([]string{"etcd", "jenkins", "mongodb"}))
+	})
+
+	It("Should stop waiting for the dependency once the member is being created", func() {
+		req.SetWaitingForDependencyCondition("jenkins", "etcd")
+		Expect(req.Status.Members[0].Conditions).Should(HaveLen(1))
+		Expect(req.Status.Members[0].Conditions[0].Reason).Should(Equal("WaitingForDependency"))
+
+		req.SetCreatingCondition("jenkins", ResourceTypeSub, corev1.ConditionTrue)
+		Expect(req.Status.Members[0].Conditions).Should(HaveLen(1))
+		Expect(req.Status.Members[0].Conditions[0].Type).Should(Equal(ConditionCreating))
+	})
+})
@@ -21,9 +21,26 @@
 package v1alpha1
 
 import (
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChartConfigMapSelector) DeepCopyInto(out *ChartConfigMapSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartConfigMapSelector.
+func (in *ChartConfigMapSelector) DeepCopy() *ChartConfigMapSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ChartConfigMapSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -49,6 +66,30 @@ func (in *ConfigService) DeepCopyInto(out *ConfigService) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.MergeStrategy != nil {
+		in, out := &in.MergeStrategy, &out.MergeStrategy
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make(map[string]JSONPatch, len(*in))
+		for key, val := range *in {
+			var outVal []JSONPatchOperation
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(JSONPatch, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigService.
@@ -83,6 +124,87 @@ func (in *CrStatus) DeepCopy() *CrStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmChart) DeepCopyInto(out *HelmChart) {
+	*out = *in
+	if in.ChartConfigMap != nil {
+		in, out := &in.ChartConfigMap, &out.ChartConfigMap
+		*out = new(ChartConfigMapSelector)
+		**out = **in
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmChart.
+func (in *HelmChart) DeepCopy() *HelmChart {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmChart)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in JSONPatch) DeepCopyInto(out *JSONPatch) {
+	{
+		in := &in
+		*out = make(JSONPatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONPatch.
+func (in JSONPatch) DeepCopy() JSONPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONPatch)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONPatchOperation) DeepCopyInto(out *JSONPatchOperation) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONPatchOperation.
+func (in *JSONPatchOperation) DeepCopy() *JSONPatchOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONPatchOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestsSource) DeepCopyInto(out *ManifestsSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestsSource.
+func (in *ManifestsSource) DeepCopy() *ManifestsSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestsSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MemberPhase) DeepCopyInto(out *MemberPhase) {
 	*out = *in
@@ -107,6 +229,18 @@ func (in *MemberStatus) DeepCopyInto(out *MemberStatus) {
 		*out = make([]OperandCRMember, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstallPlans != nil {
+		in, out := &in.InstallPlans, &out.InstallPlans
+		*out = make([]PendingInstallPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberStatus.
@@ -134,6 +268,27 @@ func (in *Operand) DeepCopyInto(out *Operand) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MergeStrategy != nil {
+		in, out := &in.MergeStrategy, &out.MergeStrategy
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make(JSONPatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]OperandResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Operand.
@@ -353,6 +508,11 @@ func (in *OperandConfigStatus) DeepCopyInto(out *OperandConfigStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.DisabledServices != nil {
+		in, out := &in.DisabledServices, &out.DisabledServices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandConfigStatus.
@@ -581,6 +741,26 @@ func (in *OperandRequestStatus) DeepCopy() *OperandRequestStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandResource) DeepCopyInto(out *OperandResource) {
+	*out = *in
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandResource.
+func (in *OperandResource) DeepCopy() *OperandResource {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Operator) DeepCopyInto(out *Operator) {
 	*out = *in
@@ -589,6 +769,36 @@ func (in *Operator) DeepCopyInto(out *Operator) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.InstallPlanAllowlist != nil {
+		in, out := &in.InstallPlanAllowlist, &out.InstallPlanAllowlist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubscriptionConfig != nil {
+		in, out := &in.SubscriptionConfig, &out.SubscriptionConfig
+		*out = new(operatorsv1alpha1.SubscriptionConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ReadinessExpression)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Helm != nil {
+		in, out := &in.Helm, &out.Helm
+		*out = new(HelmChart)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(OperatorSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Operator.
@@ -601,6 +811,26 @@ func (in *Operator) DeepCopy() *Operator {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorSource) DeepCopyInto(out *OperatorSource) {
+	*out = *in
+	if in.Manifests != nil {
+		in, out := &in.Manifests, &out.Manifests
+		*out = new(ManifestsSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorSource.
+func (in *OperatorSource) DeepCopy() *OperatorSource {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorStatus) DeepCopyInto(out *OperatorStatus) {
 	*out = *in
@@ -621,6 +851,51 @@ func (in *OperatorStatus) DeepCopy() *OperatorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingInstallPlan) DeepCopyInto(out *PendingInstallPlan) {
+	*out = *in
+	if in.ClusterServiceVersions != nil {
+		in, out := &in.ClusterServiceVersions, &out.ClusterServiceVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingInstallPlan.
+func (in *PendingInstallPlan) DeepCopy() *PendingInstallPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingInstallPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessExpression) DeepCopyInto(out *ReadinessExpression) {
+	*out = *in
+	if in.ReadyValues != nil {
+		in, out := &in.ReadyValues, &out.ReadyValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailedValues != nil {
+		in, out := &in.FailedValues, &out.FailedValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessExpression.
+func (in *ReadinessExpression) DeepCopy() *ReadinessExpression {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessExpression)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReconcileRequest) DeepCopyInto(out *ReconcileRequest) {
 	*out = *in
@@ -0,0 +1,64 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1beta1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// v1beta1 is the storage version and the conversion hub of the operator.ibm.com API group.
+// The older versions implement conversion.Convertible against the types in this package.
+
+// Hub marks OperandRequest as a conversion hub.
+func (*OperandRequest) Hub() {}
+
+// Hub marks OperandRegistry as a conversion hub.
+func (*OperandRegistry) Hub() {}
+
+// Hub marks OperandConfig as a conversion hub.
+func (*OperandConfig) Hub() {}
+
+// Hub marks OperandBindInfo as a conversion hub.
+func (*OperandBindInfo) Hub() {}
+
+// SetupWebhookWithManager registers the conversion webhook of OperandRequest.
+func (r *OperandRequest) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// SetupWebhookWithManager registers the conversion webhook of OperandRegistry.
+func (r *OperandRegistry) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// SetupWebhookWithManager registers the conversion webhook of OperandConfig.
+func (r *OperandConfig) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// SetupWebhookWithManager registers the conversion webhook of OperandBindInfo.
+func (r *OperandBindInfo) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
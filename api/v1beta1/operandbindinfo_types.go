@@ -0,0 +1,98 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// BindInfoPhase defines the BindInfo status.
+type BindInfoPhase string
+
+// OperandBindInfoSpec defines the desired state of OperandBindInfo.
+type OperandBindInfoSpec struct {
+	// The deployed service identifies itself with its operand.
+	// This must match the name in the OperandRegistry in the current namespace.
+	Operand string `json:"operand"`
+	// The registry identifies the name of the name of the OperandRegistry CR from which this operand deployment is being requested.
+	Registry string `json:"registry"`
+	// Specifies the namespace in which the OperandRegistry reside.
+	// The default is the current namespace in which the request is defined.
+	// +optional
+	RegistryNamespace string `json:"registryNamespace,omitempty"`
+	// +optional
+	Description string `json:"description,omitempty"`
+	// The bindings section is used to specify information about the access/configuration data that is to be shared.
+	// +optional
+	Bindings map[string]SecretConfigmap `json:"bindings,omitempty"`
+}
+
+// SecretConfigmap is a pair of Secret and/or Configmap.
+type SecretConfigmap struct {
+	// The secret identifies an existing secret. if it exists, the ODLM will share to the namespace of the OperandRequest.
+	// +optional
+	Secret string `json:"secret,omitempty"`
+	// The configmap identifies an existing configmap object. if it exists, the ODLM will share to the namespace of the OperandRequest.
+	// +optional
+	Configmap string `json:"configmap,omitempty"`
+}
+
+// OperandBindInfoStatus defines the observed state of OperandBindInfo.
+type OperandBindInfoStatus struct {
+	// ObservedGeneration is the most recent generation observed for this OperandBindInfo.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Phase describes the overall phase of OperandBindInfo.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:io.kubernetes.phase"
+	// +optional
+	Phase BindInfoPhase `json:"phase,omitempty"`
+	// RequestNamespaces defines the namespaces of OperandRequest.
+	// +optional
+	RequestNamespaces []string `json:"requestNamespaces,omitempty"`
+}
+
+// OperandBindInfo is the Schema for the operandbindinfoes API.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=operandbindinfos,shortName=opbi,scope=Namespaced
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=.status.phase,description="Current Phase"
+// +kubebuilder:printcolumn:name="Created At",type=string,JSONPath=.metadata.creationTimestamp
+// +operator-sdk:csv:customresourcedefinitions:displayName="OperandBindInfo"
+type OperandBindInfo struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperandBindInfoSpec   `json:"spec,omitempty"`
+	Status OperandBindInfoStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperandBindInfoList contains a list of OperandBindInfo.
+type OperandBindInfoList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperandBindInfo `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperandBindInfo{}, &OperandBindInfoList{})
+}
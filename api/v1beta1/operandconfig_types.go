@@ -0,0 +1,131 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1beta1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// OperandConfigSpec defines the desired state of OperandConfig.
+type OperandConfigSpec struct {
+	// Services is a list of configuration of service.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Operand Services Config List"
+	// +optional
+	Services []ConfigService `json:"services,omitempty"`
+}
+
+// ConfigService defines the configuration of the service.
+type ConfigService struct {
+	// Name is the subscription name.
+	Name string `json:"name"`
+	// Spec is the configuration map of custom resource.
+	Spec map[string]runtime.RawExtension `json:"spec"`
+	// State is a flag to enable or disable service.
+	// Valid values are:
+	// - "enabled" (default): the custom resources of the service are created;
+	// - "disabled": the custom resources of the service are not created, and the existing ones created by ODLM are deleted;
+	// +kubebuilder:validation:Enum=enabled;disabled
+	// +optional
+	State string `json:"state,omitempty"`
+	// MergeStrategy defines how the lists in the alm-examples template are merged with the lists in the spec.
+	// The key is the dotted path of the list, starting with the key of the spec map, for example "jenkins.containers".
+	// The value is "replace" (default), "append", or "merge:<key>" to merge the items with the same value of the key field.
+	// +optional
+	MergeStrategy map[string]string `json:"mergeStrategy,omitempty"`
+	// Patches are the ordered JSON patch operations applied to the merged spec of the custom resources.
+	// The key is the key of the spec map.
+	// +optional
+	Patches map[string]JSONPatch `json:"patches,omitempty"`
+}
+
+// JSONPatch is an ordered list of JSON patch operations.
+type JSONPatch []JSONPatchOperation
+
+// JSONPatchOperation is a JSON patch operation defined in RFC 6902.
+type JSONPatchOperation struct {
+	// Op is the operation to perform.
+	// +kubebuilder:validation:Enum=add;remove;replace;move;copy;test
+	Op string `json:"op"`
+	// Path is the JSON pointer to the target location, relative to the spec of the custom resource.
+	Path string `json:"path"`
+	// From is the JSON pointer to the source location of the move and copy operations.
+	// +optional
+	From string `json:"from,omitempty"`
+	// Value is the value of the add, replace and test operations. It can be any JSON value.
+	// +optional
+	Value *apiextensionsv1.JSON `json:"value,omitempty"`
+}
+
+// OperandConfigStatus defines the observed state of OperandConfig.
+type OperandConfigStatus struct {
+	// ObservedGeneration is the most recent generation observed for this OperandConfig.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Phase describes the overall phase of operands in the OperandConfig.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:io.kubernetes.phase"
+	// +optional
+	Phase ServicePhase `json:"phase,omitempty"`
+	// ServiceStatus defines all the status of a operator.
+	// +optional
+	ServiceStatus map[string]CrStatus `json:"serviceStatus,omitempty"`
+	// DisabledServices lists the services whose state is disabled.
+	// +optional
+	DisabledServices []string `json:"disabledServices,omitempty"`
+}
+
+// CrStatus defines the status of the custom resource.
+type CrStatus struct {
+	// +optional
+	CrStatus map[string]ServicePhase `json:"customResourceStatus,omitempty"`
+}
+
+// OperandConfig is the Schema for the operandconfigs API.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=operandconfigs,shortName=opcon,scope=Namespaced
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=.status.phase,description="Current Phase"
+// +kubebuilder:printcolumn:name="Created At",type=string,JSONPath=.metadata.creationTimestamp
+// +operator-sdk:csv:customresourcedefinitions:displayName="OperandConfig"
+type OperandConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperandConfigSpec   `json:"spec,omitempty"`
+	Status OperandConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperandConfigList contains a list of OperandConfig.
+type OperandConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperandConfig `json:"items"`
+}
+
+// ServicePhase defines the service status.
+type ServicePhase string
+
+func init() {
+	SchemeBuilder.Register(&OperandConfig{}, &OperandConfigList{})
+}
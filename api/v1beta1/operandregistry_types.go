@@ -0,0 +1,283 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1beta1
+
+import (
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// Operator defines the desired state of Operators.
+type Operator struct {
+	// A unique name for the operator whose operand may be deployed.
+	Name string `json:"name"`
+	// A scope indicator, either public or private.
+	// Valid values are:
+	// - "private" (default): deployment only request from the containing names;
+	// - "public": deployment can be requested from other namespaces;
+	// +optional
+	Scope Scope `json:"scope,omitempty"`
+	// The install mode of an operator, either namespace or cluster.
+	// Valid values are:
+	// - "namespace" (default): operator is deployed in namespace of OperandRegistry;
+	// - "cluster": operator is deployed in "openshift-operators" namespace;
+	// +optional
+	InstallMode string `json:"installMode,omitempty"`
+	// The namespace in which operator CR should be deployed.
+	// Also the namespace in which operator should be deployed when InstallMode is empty or set to "namespace".
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name of a CatalogSource that defines where and how to find the channel.
+	// It is required when the operator is installed by OLM.
+	// With OLM v1, it is the name of the ClusterCatalog, and the package is resolved from all the ClusterCatalogs when it is empty.
+	// +optional
+	SourceName string `json:"sourceName"`
+	// The Kubernetes namespace where the CatalogSource used is located.
+	// It is required when the operator is installed by OLM. It isn't used by OLM v1.
+	// +optional
+	SourceNamespace string `json:"sourceNamespace"`
+	// The target namespace of the OperatorGroups.
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+	// Name of the package that defines the applications.
+	// It is required when the operator is installed by OLM.
+	// +optional
+	PackageName string `json:"packageName"`
+	// Name of the channel to track.
+	// It is required when the operator is installed by OLM. It is optional with OLM v1.
+	// +optional
+	Channel string `json:"channel"`
+	// Description of a common service.
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Approval mode for emitted InstallPlans.
+	// +optional
+	InstallPlanApproval olmv1alpha1.Approval `json:"installPlanApproval,omitempty"`
+	// StartingCSV is the name of the ClusterServiceVersion to install first.
+	// +optional
+	StartingCSV string `json:"startingCSV,omitempty"`
+	// VersionConstraint is the semantic version range the ClusterServiceVersion must satisfy, for example ">=3.5.0 <3.7.0".
+	// When it is set, the InstallPlans are approved by ODLM only if all their ClusterServiceVersions satisfy it,
+	// or if they are listed in the approval annotation on the OperandRequest or the OperandRegistry.
+	// With OLM v1, it is the version range of the ClusterExtension.
+	// +optional
+	VersionConstraint string `json:"versionConstraint,omitempty"`
+	// InstallPlanAllowlist is a list of the patterns of the ClusterServiceVersion names, like etcdoperator.v0.9.*.
+	// When it is set, the Subscription is created with the Manual approval, and the InstallPlans are approved by ODLM
+	// only if all their ClusterServiceVersions match one of the patterns. It isn't used when VersionConstraint is set.
+	// The other InstallPlans wait for the approval annotation on the OperandRequest or the OperandRegistry.
+	// +optional
+	InstallPlanAllowlist []string `json:"installPlanAllowlist,omitempty"`
+	// SubscriptionConfig is the configuration of the operator pods, like env, resources, nodeSelector and tolerations.
+	// It is set in the config of the Subscription. The config of the Subscription isn't managed if it is not set.
+	// +optional
+	SubscriptionConfig *olmv1alpha1.SubscriptionConfig `json:"subscriptionConfig,omitempty"`
+	// ServiceAccountName is the ServiceAccount in the namespace of the operator which OLM v1 uses to install the operator.
+	// It is required when the operator is installed by OLM v1.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// Dependencies is a list of the names of the operators in the same OperandRegistry that this operator depends on.
+	// The dependencies are installed before the operator, and uninstalled after it.
+	// +optional
+	Dependencies []string `json:"dependencies,omitempty"`
+	// Readiness defines the readiness of the custom resources of the operator by their status.
+	// If it is not set, the readiness is computed from the Ready and Available conditions of the custom resources.
+	// +optional
+	Readiness *ReadinessExpression `json:"readiness,omitempty"`
+	// Helm installs the operator as a Helm release instead of an OLM Subscription.
+	// The OLM fields, like sourceName, packageName and channel, are not used when it is set.
+	// +optional
+	Helm *HelmChart `json:"helm,omitempty"`
+	// Source installs the operator from the manifests instead of an OLM Subscription.
+	// The OLM fields, like sourceName, packageName and channel, are not used when it is set.
+	// +optional
+	Source *OperatorSource `json:"source,omitempty"`
+}
+
+// OperatorSource defines where the operator is installed from when it isn't installed by OLM.
+type OperatorSource struct {
+	// Manifests are the Kubernetes manifests of the operator, like its Deployment, RBAC and CRDs.
+	// ODLM applies them with its own service account, which is granted to manage CRDs, ClusterRoles,
+	// ClusterRoleBindings, Roles and RoleBindings, and to escalate and bind roles, so the manifests are
+	// only read from a ConfigMap when the OperandRegistry is in the namespace of ODLM.
+	// +optional
+	Manifests *ManifestsSource `json:"manifests,omitempty"`
+}
+
+// ManifestsSource defines the YAML or JSON manifests applied into the namespace of an operator.
+// Exactly one of path and configMap must be set.
+type ManifestsSource struct {
+	// Path is a directory of manifest files in the file system of ODLM.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// ConfigMap is the name of the ConfigMap in the namespace of the OperandRegistry which stores the manifests in its data.
+	// It can only be set when the OperandRegistry is in the namespace of ODLM.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+}
+
+// HelmChart defines the Helm chart and the values of the release that installs an operator.
+// Exactly one of chartPath and chartConfigMap must be set.
+type HelmChart struct {
+	// ChartPath is the path of a chart directory or a packaged chart in the file system of ODLM.
+	// +optional
+	ChartPath string `json:"chartPath,omitempty"`
+	// ChartConfigMap is the ConfigMap in the namespace of the OperandRegistry which stores the packaged chart.
+	// It can only be set when the OperandRegistry is in the namespace of ODLM.
+	// +optional
+	ChartConfigMap *ChartConfigMapSelector `json:"chartConfigMap,omitempty"`
+	// ReleaseName is the name of the Helm release. The default is the name of the operator.
+	// +optional
+	ReleaseName string `json:"releaseName,omitempty"`
+	// Values override the default values of the chart.
+	// +nullable
+	// +optional
+	Values *runtime.RawExtension `json:"values,omitempty"`
+}
+
+// ChartConfigMapSelector selects the key of a ConfigMap which stores a packaged chart in its binaryData.
+type ChartConfigMapSelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+	// Key of the packaged chart in the binaryData of the ConfigMap. The default is "chart.tgz".
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// ReadinessExpression defines the readiness of a custom resource by the result of a JSONPath expression.
+type ReadinessExpression struct {
+	// JSONPath is the expression evaluated against the custom resource, for example "{.status.phase}".
+	JSONPath string `json:"jsonPath"`
+	// ReadyValues are the results of the expression when the custom resource is ready.
+	ReadyValues []string `json:"readyValues"`
+	// FailedValues are the results of the expression when the custom resource is failed.
+	// The custom resource is in progress if the result is neither ready nor failed.
+	// +optional
+	FailedValues []string `json:"failedValues,omitempty"`
+}
+
+// Scope indicates whether an operator can be requested from other namespaces.
+// +kubebuilder:validation:Enum=public;private
+type Scope string
+
+const (
+	//ScopePrivate means the operand resource can only
+	//be used within the namespace.
+	ScopePrivate Scope = "private"
+	//ScopePublic means the operand resource can only
+	//be used in the cluster.
+	ScopePublic Scope = "public"
+)
+
+// OperandRegistrySpec defines the desired state of OperandRegistry.
+type OperandRegistrySpec struct {
+	// Operators is a list of operator OLM definition.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Operators Registry List"
+	// +optional
+	Operators []Operator `json:"operators,omitempty"`
+	// InstallerGeneration is the generation of OLM which installs the operators.
+	// Valid values are:
+	// - "v0" (default): the operators are installed by the Subscriptions of OLM v0;
+	// - "v1": the operators are installed by the ClusterExtensions of OLM v1;
+	// The operators with a Helm chart or manifests are installed from them with any generation.
+	// +kubebuilder:validation:Enum=v0;v1
+	// +optional
+	InstallerGeneration string `json:"installerGeneration,omitempty"`
+}
+
+// OperandRegistryStatus defines the observed state of OperandRegistry.
+type OperandRegistryStatus struct {
+	// ObservedGeneration is the most recent generation observed for this OperandRegistry.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Phase describes the overall phase of operators in the OperandRegistry.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:io.kubernetes.phase"
+	// +optional
+	Phase RegistryPhase `json:"phase,omitempty"`
+	// OperatorsStatus defines operators status and the number of reconcile request.
+	// There is at most one entry for each operator in the OperandRegistry spec.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	OperatorsStatus []OperatorStatus `json:"operatorsStatus,omitempty"`
+	// Conditions represents the current state of the OperandRegistry.
+	// It only contains the aggregate Ready condition.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// OperatorStatus defines operators status and the number of reconcile request.
+type OperatorStatus struct {
+	// Name is the name of the operator in the OperandRegistry spec.
+	Name string `json:"name"`
+	// Phase is the state of operator.
+	// +optional
+	Phase OperatorPhase `json:"phase,omitempty"`
+	// ReconcileRequests stores the namespace/name of all the requests.
+	// +listType=map
+	// +listMapKey=namespace
+	// +listMapKey=name
+	// +optional
+	ReconcileRequests []ReconcileRequest `json:"reconcileRequests,omitempty"`
+}
+
+// ReconcileRequest records the information of the operandRequest.
+type ReconcileRequest struct {
+	// Name defines the name of request.
+	Name string `json:"name"`
+	// Namespace defines the namespace of request.
+	Namespace string `json:"namespace"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=operandregistries,shortName=opreg,scope=Namespaced
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=.status.phase,description="Current Phase"
+// +kubebuilder:printcolumn:name="Created At",type=string,JSONPath=.metadata.creationTimestamp
+// +operator-sdk:csv:customresourcedefinitions:displayName="OperandRegistry"
+
+// OperandRegistry is the Schema for the operandregistries API.
+type OperandRegistry struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperandRegistrySpec   `json:"spec,omitempty"`
+	Status OperandRegistryStatus `json:"status,omitempty"`
+}
+
+// RegistryPhase defines the operator status.
+type RegistryPhase string
+
+// +kubebuilder:object:root=true
+
+// OperandRegistryList contains a list of OperandRegistry.
+type OperandRegistryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperandRegistry `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperandRegistry{}, &OperandRegistryList{})
+}
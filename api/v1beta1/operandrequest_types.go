@@ -0,0 +1,264 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// The OperandRequestSpec identifies one or more specific operands (from a specific Registry) that should actually be installed.
+type OperandRequestSpec struct {
+	// Requests defines a list of operands installation.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Operators Request List"
+	Requests []Request `json:"requests"`
+	// DriftPolicy defines how the changes made outside ODLM to the fields rendered by ODLM in the custom resources are handled.
+	// "revert" (default) reports and reverts the changes, "report" only reports them, and "ignore" neither reports nor reverts them.
+	// +kubebuilder:validation:Enum=revert;report;ignore
+	// +optional
+	DriftPolicy string `json:"driftPolicy,omitempty"`
+	// ForegroundDeletion sets blockOwnerDeletion in the ownerReferences of the custom resources created in the namespace of the OperandRequest,
+	// so the OperandRequest deleted with the foreground propagation policy is only removed after its custom resources.
+	// +optional
+	ForegroundDeletion bool `json:"foregroundDeletion,omitempty"`
+}
+
+// Request identifies a operand detail.
+type Request struct {
+	// Operands defines a list of the OperandRegistry entry for the operand to be deployed.
+	Operands []Operand `json:"operands"`
+	// Specifies the name in which the OperandRegistry reside.
+	Registry string `json:"registry"`
+	// Specifies the namespace in which the OperandRegistry reside.
+	// The default is the current namespace in which the request is defined.
+	// +optional
+	RegistryNamespace string `json:"registryNamespace,omitempty"`
+	// Description is an optional description for the request.
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// Operand defines the name and binding information for one operator.
+type Operand struct {
+	// Name of the operand to be deployed.
+	Name string `json:"name"`
+	// The bindings section is used to specify names of secret and/or configmap.
+	// +optional
+	Bindings map[string]SecretConfigmap `json:"bindings,omitempty"`
+	// Kind is used when users want to deploy multiple custom resources.
+	// Kind identifies the kind of the custom resource.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// InstanceName is used when users want to deploy multiple custom resources.
+	// It is the name of the custom resource.
+	// +optional
+	InstanceName string `json:"instanceName,omitempty"`
+	// Namespace is the namespace of the custom resource created from the kind.
+	// The default is the namespace of the OperandRequest. The requester must be allowed to create the kind in the namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Spec is used when users want to deploy multiple custom resources.
+	// It is the configuration map of custom resource.
+	// +nullable
+	// +optional
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
+	// MergeStrategy defines how the lists in the alm-examples template are merged with the lists in the spec.
+	// The key is the dotted path of the list in the spec, for example "containers".
+	// The value is "replace" (default), "append", or "merge:<key>" to merge the items with the same value of the key field.
+	// +optional
+	MergeStrategy map[string]string `json:"mergeStrategy,omitempty"`
+	// Patches are the ordered JSON patch operations applied to the merged spec of the custom resource.
+	// +optional
+	Patches JSONPatch `json:"patches,omitempty"`
+	// Resources are the custom resources created from the operator's alm-examples, one for each entry.
+	// Resources can't be set with the kind, instanceName and spec of the operand.
+	// +optional
+	Resources []OperandResource `json:"resources,omitempty"`
+}
+
+// OperandResource defines a custom resource created for an operand.
+type OperandResource struct {
+	// Kind identifies the kind of the custom resource.
+	Kind string `json:"kind"`
+	// Name is the name of the custom resource. The default is the name of the OperandRequest.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Spec is the configuration map of the custom resource.
+	// +nullable
+	// +optional
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
+}
+
+// ClusterPhase is the phase of the installation.
+type ClusterPhase string
+
+// OperatorPhase defines the operator status.
+type OperatorPhase string
+
+// Condition represents the current state of the Request Service.
+// It has the schema of metav1.Condition, which isn't in k8s.io/apimachinery v0.18 yet,
+// so the conditions are read by kstatus, Argo CD and Flux like the ones of the built-in resources.
+type Condition struct {
+	// Type of condition in CamelCase.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])$`
+	// +kubebuilder:validation:MaxLength=316
+	Type string `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status corev1.ConditionStatus `json:"status"`
+	// ObservedGeneration is the .metadata.generation that the condition was set based upon.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Last time the condition transitioned from one status to another.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=date-time
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// The reason for the condition's last transition in CamelCase.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=1024
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$`
+	Reason string `json:"reason"`
+	// A human readable message indicating details about the transition.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=32768
+	Message string `json:"message"`
+}
+
+// OperandRequestStatus defines the observed state of OperandRequest.
+type OperandRequestStatus struct {
+	// ObservedGeneration is the most recent generation observed for this OperandRequest.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represents the current state of the Request Service.
+	// It only contains the aggregate Ready condition, the details of each operand are in the Members.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []Condition `json:"conditions,omitempty"`
+	// Members represnets the current operand status of the set.
+	// +optional
+	Members []MemberStatus `json:"members,omitempty"`
+	// Phase is the cluster running phase.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:io.kubernetes.phase"
+	// +optional
+	Phase ClusterPhase `json:"phase,omitempty"`
+}
+
+// MemberPhase shows the phase of the operator and operator instance.
+type MemberPhase struct {
+	// OperatorPhase shows the deploy phase of the operator.
+	// +optional
+	OperatorPhase OperatorPhase `json:"operatorPhase,omitempty"`
+	// OperandPhase shows the deploy phase of the operator instance.
+	// +optional
+	OperandPhase ServicePhase `json:"operandPhase,omitempty"`
+}
+
+// OperandCRMember defines a custom resource created by OperandRequest.
+type OperandCRMember struct {
+	// Name is the name of the custom resource.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Kind is the kind of the custom resource.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// APIVersion is the APIVersion of the custom resource.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Namespace is the namespace of the custom resource.
+	// The custom resources recorded without the namespace are in the namespace of the OperandRequest.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// MemberStatus shows if the Operator is ready.
+type MemberStatus struct {
+	// The member name are the same as the subscription name.
+	Name string `json:"name"`
+	// The operand phase include None, Creating, Running, Failed.
+	// +optional
+	Phase MemberPhase `json:"phase,omitempty"`
+	// Registry is the name of the OperandRegistry the operator of the member is installed from.
+	// +optional
+	Registry string `json:"registry,omitempty"`
+	// RegistryNamespace is the namespace of the OperandRegistry the operator of the member is installed from.
+	// +optional
+	RegistryNamespace string `json:"registryNamespace,omitempty"`
+	// OperandCRList shows the list of custom resource created by OperandRequest.
+	// +optional
+	OperandCRList []OperandCRMember `json:"operandCRList,omitempty"`
+	// Conditions represents the current state of the operator and operand of the member.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []Condition `json:"conditions,omitempty"`
+	// InstallPlans are the InstallPlans of the operator waiting for the approval.
+	// +optional
+	InstallPlans []PendingInstallPlan `json:"installPlans,omitempty"`
+}
+
+// PendingInstallPlan is an InstallPlan waiting for the approval, with the ClusterServiceVersions it installs.
+type PendingInstallPlan struct {
+	// Name is the name of the InstallPlan.
+	Name string `json:"name"`
+	// Namespace is the namespace of the InstallPlan.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// ClusterServiceVersions are the names of the ClusterServiceVersions the InstallPlan installs.
+	// +optional
+	ClusterServiceVersions []string `json:"clusterServiceVersions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=operandrequests,shortName=opreq,scope=Namespaced
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=.status.phase,description="Current Phase"
+// +kubebuilder:printcolumn:name="Created At",type=string,JSONPath=.metadata.creationTimestamp
+// +operator-sdk:csv:customresourcedefinitions:displayName="OperandRequest"
+
+// OperandRequest is the Schema for the operandrequests API.
+type OperandRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperandRequestSpec   `json:"spec,omitempty"`
+	Status OperandRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperandRequestList contains a list of OperandRequest.
+type OperandRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperandRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperandRequest{}, &OperandRequestList{})
+}
@@ -46,6 +46,32 @@ const (
 	//FindOperandRegistry is the key for checking if the OperandRegistry is found
 	FindOperandRegistry string = "operator.ibm.com/operandregistry-is-not-found"
 
+	//OpreqReferencesAnnotation is the annotation of the OperandRequest listing the secrets/configmaps referenced by the valueFrom of its OperandConfig and OperandRequest specs
+	OpreqReferencesAnnotation string = "operator.ibm.com/referenced-objects"
+
+	//OpreqRequesterAnnotation is the annotation of the OperandRequest recording the user who last changed its spec, set by the mutating webhook
+	OpreqRequesterAnnotation string = "operator.ibm.com/requester"
+
+	//FieldManager is the field manager of the custom resources applied by ODLM
+	FieldManager string = "odlm"
+
+	//OpreqSpecHashAnnotation is the annotation of the hash of the spec rendered by ODLM, it is used to tell the drift of the CR from the config changes
+	OpreqSpecHashAnnotation string = "operator.ibm.com/odlm-spec-hash"
+
+	//ApproveInstallPlansAnnotation is the annotation of the OperandRequest or OperandRegistry listing the ClusterServiceVersions whose InstallPlans are approved by ODLM
+	ApproveInstallPlansAnnotation string = "operator.ibm.com/approve-installplans"
+	//TemplateParametersConfigMap is the name of the ConfigMap in the ODLM namespace whose data can be referenced by the templated specs
+	TemplateParametersConfigMap string = "odlm-template-parameters"
+
+	//DefaultHelmChartKey is the default key of the packaged chart in the binaryData of the chart ConfigMap
+	DefaultHelmChartKey string = "chart.tgz"
+
+	//DefaultHelmTimeout is the default timeout for the hooks of the Helm releases
+	DefaultHelmTimeout = 5 * time.Minute
+
+	//DefaultCatalogTimeout is the default timeout for the requests to the catalogs served by the ClusterCatalogs of OLM v1
+	DefaultCatalogTimeout = 30 * time.Second
+
 	//DefaultRequestTimeout is the default timeout for kube request
 	DefaultRequestTimeout = 5 * time.Second
 
@@ -484,9 +484,10 @@ func (r *Reconciler) updateBindInfoPhase(bindInfoInstance *operatorv1alpha1.Oper
 		requestNsList = append(requestNsList, ns.Namespace)
 	}
 	requestNsList = unique(requestNsList)
-	if bindInfoInstance.Status.Phase == phase && reflect.DeepEqual(requestNsList, bindInfoInstance.Status.RequestNamespaces) {
+	if bindInfoInstance.Status.Phase == phase && bindInfoInstance.Status.ObservedGeneration == bindInfoInstance.Generation && reflect.DeepEqual(requestNsList, bindInfoInstance.Status.RequestNamespaces) {
 		return
 	}
+	bindInfoInstance.Status.ObservedGeneration = bindInfoInstance.Generation
 	if len(requestNsList) != 0 {
 		bindInfoInstance.Status.RequestNamespaces = requestNsList
 	}
@@ -18,7 +18,6 @@ package operandconfig
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
@@ -106,6 +105,14 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 
 	instance.Status.ServiceStatus = make(map[string]operatorv1alpha1.CrStatus)
 
+	// The disabled services are reported separately from the service status
+	instance.Status.DisabledServices = nil
+	for _, s := range instance.Spec.Services {
+		if !s.IsEnabled() {
+			instance.Status.DisabledServices = append(instance.Status.DisabledServices, s.Name)
+		}
+	}
+
 	registryInstance, err := r.GetOperandRegistry(ctx, types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace})
 	if err != nil {
 		return err
@@ -114,7 +121,7 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 	for _, op := range registryInstance.Spec.Operators {
 
 		service := instance.GetService(op.Name)
-		if service == nil {
+		if service == nil || !service.IsEnabled() {
 			continue
 		}
 
@@ -123,32 +130,14 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 			continue
 		}
 
-		// Looking for the CSV
-		namespace := r.GetOperatorNamespace(op.InstallMode, op.Namespace)
-		sub, err := r.GetSubscription(ctx, op.Name, namespace, op.PackageName)
-
-		if apierrors.IsNotFound(err) {
-			klog.V(3).Infof("There is no Subscription %s or %s in the namespace %s", op.Name, op.PackageName, namespace)
-			continue
-		}
-
-		if err != nil {
-			return errors.Wrapf(err, "failed to get Subscription %s or %s in the namespace %s", op.Name, op.PackageName, namespace)
-		}
-
-		if _, ok := sub.Labels[constant.OpreqLabel]; !ok {
-			// Subscription existing and not managed by OperandRequest controller
-			klog.V(1).Infof("Subscription %s in the namespace %s isn't created by ODLM", sub.Name, sub.Namespace)
-		}
-
-		csv, err := r.GetClusterServiceVersion(ctx, sub)
-
+		// Looking for the templates of the custom resources offered by the operator
+		templates, err := r.GetInstaller(registryInstance, &op).Templates(ctx, registryInstance, &op)
 		if err != nil {
-			return errors.Wrapf(err, "failed to get ClusterServiceVersion for the Subscription %s/%s", namespace, sub.Name)
+			return errors.Wrapf(err, "failed to get the templates of the custom resources of the operator %s", op.Name)
 		}
 
-		if csv == nil {
-			klog.Warningf("ClusterServiceVersion for the Subscription %s/%s doesn't exist, retry...", namespace, sub.Name)
+		if templates == nil {
+			klog.V(3).Infof("The templates of the custom resources of the operator %s are not available, retry...", op.Name)
 			continue
 		}
 
@@ -164,28 +153,14 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 			instance.Status.ServiceStatus[op.Name] = tmp
 		}
 
-		almExamples := csv.ObjectMeta.Annotations["alm-examples"]
-		if almExamples == "" {
-			klog.Warningf("Notfound alm-examples in the ClusterServiceVersion %s/%s", csv.Namespace, csv.Name)
-			continue
-		}
-		// Create a slice for crTemplates
-		var crTemplates []interface{}
-
-		// Convert CR template string to slice
-		err = json.Unmarshal([]byte(almExamples), &crTemplates)
-		if err != nil {
-			return errors.Wrapf(err, "failed to convert alm-examples in the Subscription %s/%s to slice", sub.Namespace, sub.Name)
-		}
-
 		merr := &util.MultiErr{}
 
-		// Merge OperandConfig and ClusterServiceVersion alm-examples
-		for _, crTemplate := range crTemplates {
+		// Merge OperandConfig and the templates of the operator
+		for _, crTemplate := range templates {
 
 			// Create an unstruct object for CR and request its value to CR template
 			var unstruct unstructured.Unstructured
-			unstruct.Object = crTemplate.(map[string]interface{})
+			unstruct.Object = crTemplate.Object
 
 			kind := unstruct.Object["kind"].(string)
 
@@ -212,7 +187,12 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 				instance.Status.ServiceStatus[op.Name].CrStatus[kind] = operatorv1alpha1.ServiceFailed
 			} else if apierrors.IsNotFound(getError) {
 			} else {
-				instance.Status.ServiceStatus[op.Name].CrStatus[kind] = operatorv1alpha1.ServiceRunning
+				// The phase comes from the status of the custom resource
+				phase, message := deploy.GetCustomResourcePhase(&unstruct, op.Readiness)
+				if phase != operatorv1alpha1.ServiceRunning {
+					klog.V(2).Infof("The custom resource %s %s/%s of the service %s is %s: %s", kind, op.Namespace, name, op.Name, phase, message)
+				}
+				instance.Status.ServiceStatus[op.Name].CrStatus[kind] = phase
 			}
 		}
 		if len(merr.Errors) != 0 {
@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/gomega"
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
@@ -104,55 +105,7 @@ var _ = Describe("OperandRegistry controller", func() {
 			By("Creating the OperandRequest")
 			Expect(k8sClient.Create(ctx, request)).Should(Succeed())
 
-			By("Setting status of the Subscriptions")
-			etcdSub := testutil.Subscription("etcd", operatorNamespaceName)
-			Eventually(func() error {
-				k8sClient.Get(ctx, types.NamespacedName{Name: "etcd", Namespace: operatorNamespaceName}, etcdSub)
-				etcdSub.Status = testutil.SubscriptionStatus("etcd", operatorNamespaceName, "0.0.1")
-				return k8sClient.Status().Update(ctx, etcdSub)
-			}, timeout, interval).Should(Succeed())
-
-			jenkinsSub := testutil.Subscription("jenkins", operatorNamespaceName)
-			Eventually(func() error {
-				k8sClient.Get(ctx, types.NamespacedName{Name: "jenkins", Namespace: operatorNamespaceName}, jenkinsSub)
-				jenkinsSub.Status = testutil.SubscriptionStatus("jenkins", operatorNamespaceName, "0.0.1")
-				return k8sClient.Status().Update(ctx, jenkinsSub)
-			}, timeout, interval).Should(Succeed())
-
-			By("Creating and Setting status of the ClusterServiceVersions")
-			etcdCSV := testutil.ClusterServiceVersion("etcd-csv.v0.0.1", operatorNamespaceName, testutil.EtcdExample)
-			Expect(k8sClient.Create(ctx, etcdCSV)).Should(Succeed())
-			Eventually(func() error {
-				k8sClient.Get(ctx, types.NamespacedName{Name: "etcd-csv.v0.0.1", Namespace: operatorNamespaceName}, etcdCSV)
-				etcdCSV.Status = testutil.ClusterServiceVersionStatus()
-				return k8sClient.Status().Update(ctx, etcdCSV)
-			}, timeout, interval).Should(Succeed())
-
-			jenkinsCSV := testutil.ClusterServiceVersion("jenkins-csv.v0.0.1", operatorNamespaceName, testutil.JenkinsExample)
-			Expect(k8sClient.Create(ctx, jenkinsCSV)).Should(Succeed())
-			Eventually(func() error {
-				k8sClient.Get(ctx, types.NamespacedName{Name: "jenkins-csv.v0.0.1", Namespace: operatorNamespaceName}, jenkinsCSV)
-				jenkinsCSV.Status = testutil.ClusterServiceVersionStatus()
-				return k8sClient.Status().Update(ctx, jenkinsCSV)
-			}, timeout, interval).Should(Succeed())
-
-			By("Creating and Setting status of the InstallPlan")
-			etcdIP := testutil.InstallPlan("etcd-install-plan", operatorNamespaceName)
-			Expect(k8sClient.Create(ctx, etcdIP)).Should(Succeed())
-			Eventually(func() error {
-				k8sClient.Get(ctx, types.NamespacedName{Name: "etcd-install-plan", Namespace: operatorNamespaceName}, etcdIP)
-				etcdIP.Status = testutil.InstallPlanStatus()
-				return k8sClient.Status().Update(ctx, etcdIP)
-			}, timeout, interval).Should(Succeed())
-
-			jenkinsIP := testutil.InstallPlan("jenkins-install-plan", operatorNamespaceName)
-			Expect(k8sClient.Create(ctx, jenkinsIP)).Should(Succeed())
-			Eventually(func() error {
-				k8sClient.Get(ctx, types.NamespacedName{Name: "jenkins-install-plan", Namespace: operatorNamespaceName}, jenkinsIP)
-				jenkinsIP.Status = testutil.InstallPlanStatus()
-				return k8sClient.Status().Update(ctx, jenkinsIP)
-			}, timeout, interval).Should(Succeed())
-
+			By("Waiting for the fake OLM to install the operators")
 			By("Checking status of the OperandRegistry")
 			Eventually(func() operatorv1alpha1.RegistryPhase {
 				registryInstance := &operatorv1alpha1.OperandRegistry{}
@@ -161,12 +114,9 @@ var _ = Describe("OperandRegistry controller", func() {
 			}, timeout, interval).Should(Equal(operatorv1alpha1.RegistryRunning))
 
 			By("Cleaning up olm resources")
-			Expect(k8sClient.Delete(ctx, etcdSub)).Should(Succeed())
-			Expect(k8sClient.Delete(ctx, jenkinsSub)).Should(Succeed())
-			Expect(k8sClient.Delete(ctx, etcdCSV)).Should(Succeed())
-			Expect(k8sClient.Delete(ctx, jenkinsCSV)).Should(Succeed())
-			Expect(k8sClient.Delete(ctx, etcdIP)).Should(Succeed())
-			Expect(k8sClient.Delete(ctx, jenkinsIP)).Should(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &olmv1alpha1.Subscription{}, client.InNamespace(operatorNamespaceName))).Should(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &olmv1alpha1.ClusterServiceVersion{}, client.InNamespace(operatorNamespaceName))).Should(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &olmv1alpha1.InstallPlan{}, client.InNamespace(operatorNamespaceName))).Should(Succeed())
 		})
 	})
 })
@@ -42,6 +42,7 @@ import (
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandconfig"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandrequest"
 	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -122,6 +123,10 @@ var _ = BeforeSuite(func(done Done) {
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
+	// Setup Manager with the fake OLM controller
+	err = testutil.NewFakeOLM(k8sManager.GetClient(), testutil.FakeOLMCatalog()...).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	go func() {
 		err = k8sManager.Start(ctrl.SetupSignalHandler())
 		Expect(err).ToNot(HaveOccurred())
@@ -0,0 +1,231 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandregistry
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/blang/semver"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	operatorv1beta1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1beta1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+)
+
+const (
+	// ValidatingWebhookPath is the path the OperandRegistry validating webhook is served on
+	ValidatingWebhookPath = "/validate-operator-ibm-com-v1alpha1-operandregistry"
+	// MutatingWebhookPath is the path the OperandRegistry mutating webhook is served on
+	MutatingWebhookPath = "/mutate-operator-ibm-com-v1alpha1-operandregistry"
+)
+
+// +kubebuilder:webhook:path=/mutate-operator-ibm-com-v1alpha1-operandregistry,mutating=true,failurePolicy=fail,groups=operator.ibm.com,resources=operandregistries,verbs=create;update,versions=v1alpha1;v1beta1,name=moperandregistry.operator.ibm.com
+// +kubebuilder:webhook:path=/validate-operator-ibm-com-v1alpha1-operandregistry,mutating=false,failurePolicy=fail,groups=operator.ibm.com,resources=operandregistries,verbs=create;update,versions=v1alpha1;v1beta1,name=voperandregistry.operator.ibm.com
+
+// Defaulter persists the default values of the OperandRegistry spec
+type Defaulter struct {
+	decoder *admission.Decoder
+}
+
+// Handle sets the default scope, install mode and install plan approval for every operator
+func (d *Defaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	registryInstance := &operatorv1alpha1.OperandRegistry{}
+	hub := &operatorv1beta1.OperandRegistry{}
+	isHub := req.Kind.Version == operatorv1beta1.GroupVersion.Version
+	if err := util.DecodeAdmissionObject(d.decoder, req.Object, isHub, registryInstance, hub); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	registryInstance.Default()
+
+	marshaled, err := util.EncodeAdmissionObject(registryInstance, isHub, hub)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// InjectDecoder injects the decoder into the Defaulter
+func (d *Defaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// SetupWebhookWithManager registers the mutating webhook of OperandRegistry
+func (d *Defaulter) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(MutatingWebhookPath, &webhook.Admission{Handler: d})
+	return nil
+}
+
+// Validator validates the operators in the OperandRegistry
+type Validator struct {
+	decoder *admission.Decoder
+}
+
+// Handle rejects the OperandRegistry if the dependencies of its operators are not a directed acyclic graph,
+// if the version constraint of an operator is invalid, or if an operator can't be installed by OLM, from a Helm chart or from manifests
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	registryInstance := &operatorv1alpha1.OperandRegistry{}
+	hub := &operatorv1beta1.OperandRegistry{}
+	isHub := req.Kind.Version == operatorv1beta1.GroupVersion.Version
+	if err := util.DecodeAdmissionObject(v.decoder, req.Object, isHub, registryInstance, hub); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// The finalizer of a deleting OperandRegistry must always be removable
+	if registryInstance.GetDeletionTimestamp() != nil {
+		return admission.Allowed("")
+	}
+	// The namespace may be not set in the object yet
+	if registryInstance.Namespace == "" {
+		registryInstance.Namespace = req.Namespace
+	}
+
+	allErrs := validateDependencies(registryInstance)
+	allErrs = append(allErrs, validateVersionConstraints(registryInstance)...)
+	allErrs = append(allErrs, validateInstallPlanAllowlists(registryInstance)...)
+	allErrs = append(allErrs, validateInstallers(registryInstance)...)
+	if len(allErrs) != 0 {
+		klog.V(2).Infof("Rejected OperandRegistry %s/%s: %v", registryInstance.Namespace, registryInstance.Name, allErrs.ToAggregate())
+		return admission.Denied(allErrs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+func validateDependencies(registryInstance *operatorv1alpha1.OperandRegistry) field.ErrorList {
+	var allErrs field.ErrorList
+	operatorsPath := field.NewPath("spec", "operators")
+	var names []string
+	for i, o := range registryInstance.Spec.Operators {
+		names = append(names, o.Name)
+		for j, dep := range o.Dependencies {
+			depPath := operatorsPath.Index(i).Child("dependencies").Index(j)
+			if dep == o.Name {
+				allErrs = append(allErrs, field.Invalid(depPath, dep, "an operator can't depend on itself"))
+			} else if registryInstance.GetOperator(dep) == nil {
+				allErrs = append(allErrs, field.NotFound(depPath, dep))
+			}
+		}
+	}
+	if len(allErrs) != 0 {
+		return allErrs
+	}
+	if _, err := registryInstance.ResolveDependencies(names); err != nil {
+		allErrs = append(allErrs, field.Invalid(operatorsPath, names, err.Error()))
+	}
+	return allErrs
+}
+
+func validateVersionConstraints(registryInstance *operatorv1alpha1.OperandRegistry) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, o := range registryInstance.Spec.Operators {
+		if o.VersionConstraint == "" {
+			continue
+		}
+		if _, err := semver.ParseRange(o.VersionConstraint); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "operators").Index(i).Child("versionConstraint"), o.VersionConstraint, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateInstallPlanAllowlists(registryInstance *operatorv1alpha1.OperandRegistry) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, o := range registryInstance.Spec.Operators {
+		for j, pattern := range o.InstallPlanAllowlist {
+			if _, err := path.Match(pattern, ""); err != nil {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "operators").Index(i).Child("installPlanAllowlist").Index(j), pattern, err.Error()))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateInstallers checks the operators installed by OLM v0 or v1 have the OLM fields,
+// and the operators installed from Helm charts or manifests have exactly one source.
+// The charts and manifests are installed with the permissions of ODLM, so they are only read
+// from a ConfigMap when the OperandRegistry is in the namespace of ODLM.
+func validateInstallers(registryInstance *operatorv1alpha1.OperandRegistry) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, o := range registryInstance.Spec.Operators {
+		operatorPath := field.NewPath("spec", "operators").Index(i)
+		if o.Helm != nil && o.Source != nil {
+			allErrs = append(allErrs, field.Forbidden(operatorPath.Child("source"), "helm and source can't be set together"))
+			continue
+		}
+		if o.Helm != nil {
+			helmPath := operatorPath.Child("helm")
+			if (o.Helm.ChartPath == "") == (o.Helm.ChartConfigMap == nil) {
+				allErrs = append(allErrs, field.Invalid(helmPath, o.Helm.ChartPath, "exactly one of chartPath and chartConfigMap must be set"))
+			} else if o.Helm.ChartConfigMap != nil && !util.IsOperatorNamespace(registryInstance.Namespace) {
+				allErrs = append(allErrs, field.Forbidden(helmPath.Child("chartConfigMap"), "it can only be set when the OperandRegistry is in the namespace of ODLM, use chartPath instead"))
+			}
+			continue
+		}
+		if o.Source != nil {
+			manifests := o.Source.Manifests
+			if manifests == nil {
+				allErrs = append(allErrs, field.Required(operatorPath.Child("source", "manifests"), ""))
+			} else if (manifests.Path == "") == (manifests.ConfigMap == "") {
+				allErrs = append(allErrs, field.Invalid(operatorPath.Child("source", "manifests"), manifests.Path, "exactly one of path and configMap must be set"))
+			} else if manifests.ConfigMap != "" && !util.IsOperatorNamespace(registryInstance.Namespace) {
+				allErrs = append(allErrs, field.Forbidden(operatorPath.Child("source", "manifests", "configMap"), "it can only be set when the OperandRegistry is in the namespace of ODLM, use path instead"))
+			}
+			continue
+		}
+		if registryInstance.Spec.InstallerGeneration == operatorv1alpha1.InstallerGenerationV1 {
+			if o.PackageName == "" {
+				allErrs = append(allErrs, field.Required(operatorPath.Child("packageName"), "it is required when the operator is installed by OLM v1"))
+			}
+			if o.ServiceAccountName == "" {
+				allErrs = append(allErrs, field.Required(operatorPath.Child("serviceAccountName"), "it is required when the operator is installed by OLM v1"))
+			}
+			continue
+		}
+		required := map[string]string{
+			"sourceName":      o.SourceName,
+			"sourceNamespace": o.SourceNamespace,
+			"packageName":     o.PackageName,
+			"channel":         o.Channel,
+		}
+		for _, name := range []string{"sourceName", "sourceNamespace", "packageName", "channel"} {
+			if required[name] == "" {
+				allErrs = append(allErrs, field.Required(operatorPath.Child(name), "it is required unless the operator is installed from a Helm chart or from manifests"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// InjectDecoder injects the decoder into the Validator
+func (v *Validator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+
+// SetupWebhookWithManager registers the validating webhook of OperandRegistry
+func (v *Validator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(ValidatingWebhookPath, &webhook.Admission{Handler: v})
+	return nil
+}
@@ -0,0 +1,289 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandregistry
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	operatorv1beta1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1beta1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
+)
+
+var _ = Describe("OperandRegistry mutating webhook", func() {
+	const (
+		registryName      = "common-service"
+		registryNamespace = "ibm-common-services"
+		operatorNamespace = "ibm-operators"
+	)
+
+	var (
+		ctx       context.Context
+		defaulter *Defaulter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(operatorv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		Expect(operatorv1beta1.AddToScheme(scheme)).Should(Succeed())
+		decoder, err := admission.NewDecoder(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		defaulter = &Defaulter{}
+		Expect(defaulter.InjectDecoder(decoder)).Should(Succeed())
+	})
+
+	Context("Defaulting an OperandRegistry", func() {
+		It("Should persist the defaults of the operators in the v1beta1 OperandRegistry", func() {
+			registry := testutil.OperandRegistryObj(registryName, registryNamespace, operatorNamespace)
+			registry.Spec.Operators[0].Scope = ""
+			registry.Spec.Operators[1].InstallMode = operatorv1alpha1.InstallModeCluster
+			registry.Spec.Operators[1].InstallPlanApproval = olmv1alpha1.ApprovalManual
+			hub := &operatorv1beta1.OperandRegistry{}
+			Expect(registry.ConvertTo(hub)).Should(Succeed())
+			raw, err := json.Marshal(hub)
+			Expect(err).NotTo(HaveOccurred())
+
+			resp := defaulter.Handle(ctx, admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Create,
+				Namespace: registryNamespace,
+				Kind:      metav1.GroupVersionKind{Group: operatorv1beta1.GroupVersion.Group, Version: operatorv1beta1.GroupVersion.Version, Kind: "OperandRegistry"},
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			Expect(resp.Allowed).Should(BeTrue())
+
+			patches := map[string]interface{}{}
+			for _, patch := range resp.Patches {
+				patches[patch.Path] = patch.Value
+			}
+			Expect(patches).Should(HaveKeyWithValue("/spec/operators/0/scope", "private"))
+			Expect(patches).Should(HaveKeyWithValue("/spec/operators/0/installMode", "namespace"))
+			Expect(patches).Should(HaveKeyWithValue("/spec/operators/0/installPlanApproval", "Automatic"))
+			Expect(patches).ShouldNot(HaveKey("/spec/operators/1/installMode"))
+			Expect(patches).ShouldNot(HaveKey("/spec/operators/1/installPlanApproval"))
+		})
+	})
+})
+
+var _ = Describe("OperandRegistry validating webhook", func() {
+	const (
+		registryName      = "common-service"
+		registryNamespace = "ibm-common-services"
+		operatorNamespace = "ibm-operators"
+	)
+
+	var (
+		ctx       context.Context
+		validator *Validator
+		registry  *operatorv1alpha1.OperandRegistry
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(operatorv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		Expect(operatorv1beta1.AddToScheme(scheme)).Should(Succeed())
+		decoder, err := admission.NewDecoder(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		validator = &Validator{}
+		Expect(validator.InjectDecoder(decoder)).Should(Succeed())
+		registry = testutil.OperandRegistryObj(registryName, registryNamespace, operatorNamespace)
+	})
+
+	validate := func() admission.Response {
+		raw, err := json.Marshal(registry)
+		Expect(err).NotTo(HaveOccurred())
+		return validator.Handle(ctx, admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Create,
+			Namespace: registryNamespace,
+			Kind:      metav1.GroupVersionKind{Group: operatorv1alpha1.GroupVersion.Group, Version: operatorv1alpha1.GroupVersion.Version, Kind: "OperandRegistry"},
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+	}
+
+	Context("Validating the dependencies of the operators", func() {
+		It("Should allow the dependencies without cycle", func() {
+			registry.Spec.Operators[0].Dependencies = []string{registry.Spec.Operators[1].Name}
+			Expect(validate().Allowed).Should(BeTrue())
+		})
+
+		It("Should reject the dependency which is not in the OperandRegistry", func() {
+			registry.Spec.Operators[0].Dependencies = []string{"mongodb"}
+			resp := validate()
+			Expect(resp.Allowed).Should(BeFalse())
+			Expect(string(resp.Result.Reason)).Should(ContainSubstring("spec.operators[0].dependencies[0]"))
+		})
+
+		It("Should reject the operator depending on itself", func() {
+			registry.Spec.Operators[0].Dependencies = []string{registry.Spec.Operators[0].Name}
+			resp := validate()
+			Expect(resp.Allowed).Should(BeFalse())
+			Expect(string(resp.Result.Reason)).Should(ContainSubstring("an operator can't depend on itself"))
+		})
+
+		It("Should reject the dependency cycle", func() {
+			registry.Spec.Operators[0].Dependencies = []string{registry.Spec.Operators[1].Name}
+			registry.Spec.Operators[1].Dependencies = []string{registry.Spec.Operators[0].Name}
+			resp := validate()
+			Expect(resp.Allowed).Should(BeFalse())
+			Expect(string(resp.Result.Reason)).Should(ContainSubstring("found dependency cycle etcd -> jenkins -> etcd"))
+		})
+	})
+
+	Context("Validating the version constraints of the operators", func() {
+		It("Should allow the valid version constraint", func() {
+			registry.Spec.Operators[0].VersionConstraint = ">=3.5.0 <3.7.0"
+			Expect(validate().Allowed).Should(BeTrue())
+		})
+
+		It("Should reject the invalid version constraint", func() {
+			registry.Spec.Operators[1].VersionConstraint = "~> 3.5"
+			resp := validate()
+			Expect(resp.Allowed).Should(BeFalse())
+			Expect(string(resp.Result.Reason)).Should(ContainSubstring("spec.operators[1].versionConstraint"))
+		})
+	})
+
+	Context("Validating the InstallPlan allowlists of the operators", func() {
+		It("Should allow the patterns of the ClusterServiceVersion names", func() {
+			registry.Spec.Operators[0].InstallPlanAllowlist = []string{"etcdoperator.v0.9.*", "etcdoperator.v1.[0-2].?"}
+			Expect(validate().Allowed).Should(BeTrue())
+		})
+
+		It("Should reject the malformed pattern", func() {
+			registry.Spec.Operators[1].InstallPlanAllowlist = []string{"jenkins-operator.v0.3.*", "jenkins-operator.v[0-"}
+			resp := validate()
+			Expect(resp.Allowed).Should(BeFalse())
+			Expect(string(resp.Result.Reason)).Should(ContainSubstring("spec.operators[1].installPlanAllowlist[1]"))
+		})
+	})
+
+	Context("Validating the installers of the operators", func() {
+		It("Should allow the operator installed from a Helm chart without the OLM fields", func() {
+			registry.Spec.Operators[1] = operatorv1alpha1.Operator{
+				Name:      "jenkins",
+				Namespace: operatorNamespace,
+				Helm:      &operatorv1alpha1.HelmChart{ChartPath: "/charts/jenkins-operator"},
+			}
+			Expect(validate().Allowed).Should(BeTrue())
+		})
+
+		It("Should reject the Helm chart without exactly one chart source", func() {
+			registry.Spec.Operators[1].Helm = &operatorv1alpha1.HelmChart{
+				ChartPath:      "/charts/jenkins-operator",
+				ChartConfigMap: &operatorv1alpha1.ChartConfigMapSelector{Name: "jenkins-operator-chart"},
+			}
+			resp := validate()
+			Expect(resp.Allowed).Should(BeFalse())
+			Expect(string(resp.Result.Reason)).Should(ContainSubstring("spec.operators[1].helm"))
+
+			registry.Spec.Operators[1].Helm = &operatorv1alpha1.HelmChart{}
+			Expect(validate().Allowed).Should(BeFalse())
+		})
+
+		It("Should allow the operator installed from manifests without the OLM fields", func() {
+			os.Setenv("OPERATOR_NAMESPACE", registryNamespace)
+			defer os.Unsetenv("OPERATOR_NAMESPACE")
+			registry.Spec.Operators[1] = operatorv1alpha1.Operator{
+				Name:      "jenkins",
+				Namespace: operatorNamespace,
+				Source: &operatorv1alpha1.OperatorSource{
+					Manifests: &operatorv1alpha1.ManifestsSource{ConfigMap: "jenkins-operator-manifests"},
+				},
+			}
+			Expect(validate().Allowed).Should(BeTrue())
+		})
+
+		It("Should reject the ConfigMaps of the charts and manifests outside the namespace of ODLM", func() {
+			os.Setenv("OPERATOR_NAMESPACE", "ibm-odlm")
+			defer os.Unsetenv("OPERATOR_NAMESPACE")
+			registry.Spec.Operators[0] = operatorv1alpha1.Operator{
+				Name:      "etcd",
+				Namespace: operatorNamespace,
+				Helm:      &operatorv1alpha1.HelmChart{ChartConfigMap: &operatorv1alpha1.ChartConfigMapSelector{Name: "etcd-operator-chart"}},
+			}
+			registry.Spec.Operators[1] = operatorv1alpha1.Operator{
+				Name:      "jenkins",
+				Namespace: operatorNamespace,
+				Source: &operatorv1alpha1.OperatorSource{
+					Manifests: &operatorv1alpha1.ManifestsSource{ConfigMap: "jenkins-operator-manifests"},
+				},
+			}
+			resp := validate()
+			Expect(resp.Allowed).Should(BeFalse())
+			Expect(string(resp.Result.Reason)).Should(ContainSubstring("spec.operators[0].helm.chartConfigMap: Forbidden"))
+			Expect(string(resp.Result.Reason)).Should(ContainSubstring("spec.operators[1].source.manifests.configMap: Forbidden"))
+
+			registry.Spec.Operators[1].Source.Manifests = &operatorv1alpha1.ManifestsSource{Path: "/manifests/jenkins-operator"}
+			resp = validate()
+			Expect(string(resp.Result.Reason)).ShouldNot(ContainSubstring("spec.operators[1]"))
+		})
+
+		It("Should reject the manifests without exactly one source", func() {
+			registry.Spec.Operators[1].Source = &operatorv1alpha1.OperatorSource{
+				Manifests: &operatorv1alpha1.ManifestsSource{Path: "/manifests/jenkins-operator", ConfigMap: "jenkins-operator-manifests"},
+			}
+			resp := validate()
+			Expect(resp.Allowed).Should(BeFalse())
+			Expect(string(resp.Result.Reason)).Should(ContainSubstring("spec.operators[1].source.manifests"))
+
+			registry.Spec.Operators[1].Source = &operatorv1alpha1.OperatorSource{}
+			Expect(validate().Allowed).Should(BeFalse())
+		})
+
+		It("Should reject the operator with both a Helm chart and manifests", func() {
+			registry.Spec.Operators[1].Helm = &operatorv1alpha1.HelmChart{ChartPath: "/charts/jenkins-operator"}
+			registry.Spec.Operators[1].Source = &operatorv1alpha1.OperatorSource{
+				Manifests: &operatorv1alpha1.ManifestsSource{Path: "/manifests/jenkins-operator"},
+			}
+			resp := validate()
+			Expect(resp.Allowed).Should(BeFalse())
+			Expect(string(resp.Result.Reason)).Should(ContainSubstring("spec.operators[1].source"))
+		})
+
+		It("Should reject the operator installed by OLM without the OLM fields", func() {
+			registry.Spec.Operators[0].Channel = ""
+			resp := validate()
+			Expect(resp.Allowed).Should(BeFalse())
+			Expect(string(resp.Result.Reason)).Should(ContainSubstring("spec.operators[0].channel: Required value: it is required unless the operator is installed from a Helm chart or from manifests"))
+		})
+
+		It("Should only require the package and the ServiceAccount of the operator installed by OLM v1", func() {
+			registry.Spec.InstallerGeneration = operatorv1alpha1.InstallerGenerationV1
+			registry.Spec.Operators[0].SourceNamespace = ""
+			registry.Spec.Operators[0].Channel = ""
+			registry.Spec.Operators[1].ServiceAccountName = "jenkins-installer"
+			resp := validate()
+			Expect(resp.Allowed).Should(BeFalse())
+			Expect(string(resp.Result.Reason)).Should(ContainSubstring("spec.operators[0].serviceAccountName: Required value"))
+			Expect(string(resp.Result.Reason)).ShouldNot(ContainSubstring("spec.operators[1]"))
+
+			registry.Spec.Operators[0].ServiceAccountName = "etcd-installer"
+			Expect(validate().Allowed).Should(BeTrue())
+		})
+	})
+})
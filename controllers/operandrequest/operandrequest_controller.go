@@ -20,22 +20,27 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	gset "github.com/deckarep/golang-set"
-	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
-	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/pkg/errors"
 	authorizationv1 "k8s.io/api/authorization/v1"
-	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/metadata"
 	"k8s.io/klog"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -52,12 +57,19 @@ import (
 // Reconciler reconciles a OperandRequest object
 type Reconciler struct {
 	*deploy.ODLMOperator
+	// controller watches the kinds of the custom resources created by ODLM to detect their drift
+	controller   controller.Controller
+	watchedKinds sync.Map
+	// references watches the Secrets and ConfigMaps referenced by the valueFrom
+	references *referenceWatcher
 }
-type clusterObjects struct {
-	namespace     *corev1.Namespace
-	operatorGroup *olmv1.OperatorGroup
-	subscription  *olmv1alpha1.Subscription
-}
+
+// +kubebuilder:rbac:groups=operator.ibm.com,resources=operandrequests;operandbindinfos;operandconfigs;operandregistries,verbs=get;list;watch
+// +kubebuilder:rbac:groups=operator.ibm.com,resources=certmanagers;ibmlicensings;meteringreportservers;auditloggings,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=clusterhealth.ibm.com,resources=clusterservicestatuses,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=certmanager.k8s.io,resources=clusterissuers,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+// +kubebuilder:rbac:groups=*,namespace=system,resources=*,verbs=create;delete;get;list;patch;update;watch
 
 // Reconcile reads that state of the cluster for a OperandRequest object and makes changes based on the state read
 // and what is in the OperandRequest.Spec
@@ -283,20 +295,12 @@ func (r *Reconciler) addFinalizer(ctx context.Context, cr *operatorv1alpha1.Oper
 
 func (r *Reconciler) checkFinalizer(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) error {
 	klog.V(1).Infof("Deleting OperandRequest %s in the namespace %s", requestInstance.Name, requestInstance.Namespace)
-	existingSub := &olmv1alpha1.SubscriptionList{}
-
-	opts := []client.ListOption{
-		client.MatchingLabels(map[string]string{constant.OpreqLabel: "true"}),
-	}
-
-	if err := r.Client.List(ctx, existingSub, opts...); err != nil {
-		return err
-	}
-	if len(existingSub.Items) == 0 {
+	// The operators to uninstall are the members of the request
+	if len(requestInstance.Status.Members) == 0 {
 		return nil
 	}
-	// Delete all the subscriptions that created by current request
-	if err := r.absentOperatorsAndOperands(ctx, requestInstance); err != nil {
+	// Uninstall all the operators that installed by current request
+	if _, err := r.absentOperatorsAndOperands(ctx, requestInstance); err != nil {
 		return err
 	}
 	return nil
@@ -332,23 +336,165 @@ func (r *Reconciler) getConfigToRequestMapper() handler.ToRequestsFunc {
 	}
 }
 
+// getCustomResourceToRequestMapper enqueues the OperandRequests of a custom resource created by ODLM.
+// The custom resource created from an OperandConfig is shared by the OperandRequests of its OperandRegistry,
+// which is in the label of the custom resource, and the one created from an OperandRequest is in its status.
+func (r *Reconciler) getCustomResourceToRequestMapper() handler.ToRequestsFunc {
+	ctx := context.Background()
+	return func(object handler.MapObject) []ctrl.Request {
+		requests := []ctrl.Request{}
+		requestList, err := r.ListOperandRequests(ctx, nil)
+		if err != nil {
+			klog.Errorf("failed to list OperandRequests for %s/%s: %v", object.Meta.GetNamespace(), object.Meta.GetName(), err)
+			return requests
+		}
+		gvk := object.Object.GetObjectKind().GroupVersionKind()
+		for _, request := range requestList.Items {
+			if ownsCustomResource(&request, gvk, object.Meta) {
+				namespaceName := types.NamespacedName{Name: request.Name, Namespace: request.Namespace}
+				requests = append(requests, ctrl.Request{NamespacedName: namespaceName})
+			}
+		}
+		return requests
+	}
+}
+
+// ownsCustomResource checks if the custom resource is created by ODLM for the OperandRequest
+func ownsCustomResource(request *operatorv1alpha1.OperandRequest, gvk schema.GroupVersionKind, cr metav1.Object) bool {
+	for label := range request.GetLabels() {
+		if strings.HasSuffix(label, "/registry") && cr.GetLabels()[label] == "true" {
+			return true
+		}
+	}
+	for _, m := range request.Status.Members {
+		for _, member := range m.OperandCRList {
+			gv, err := schema.ParseGroupVersion(member.APIVersion)
+			if err != nil {
+				continue
+			}
+			if member.Kind == gvk.Kind && gv.Group == gvk.Group && member.Name == cr.GetName() && request.GetMemberCRNamespace(member) == cr.GetNamespace() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// referenceIndexKey is the index of the OperandRequests by the Secrets and ConfigMaps referenced in their specs
+const referenceIndexKey = "referencedObjects"
+
+// indexReferences returns the Secrets and ConfigMaps recorded in the annotation of the OperandRequest
+func indexReferences(obj runtime.Object) []string {
+	request, ok := obj.(*operatorv1alpha1.OperandRequest)
+	if !ok {
+		return nil
+	}
+	value := request.GetAnnotations()[constant.OpreqReferencesAnnotation]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// getReferenceToRequestMapper enqueues the OperandRequests referencing the changed Secret or ConfigMap
+func (r *Reconciler) getReferenceToRequestMapper(kind string) handler.ToRequestsFunc {
+	ctx := context.Background()
+	return func(object handler.MapObject) []ctrl.Request {
+		requests := []ctrl.Request{}
+		key := referenceKey(kind, object.Meta.GetNamespace(), object.Meta.GetName())
+		requestList := &operatorv1alpha1.OperandRequestList{}
+		if err := r.Client.List(ctx, requestList, client.MatchingFields{referenceIndexKey: key}); err != nil {
+			klog.Errorf("failed to list OperandRequests referencing the %s %s/%s: %v", kind, object.Meta.GetNamespace(), object.Meta.GetName(), err)
+			return requests
+		}
+		for _, request := range requestList.Items {
+			namespaceName := types.NamespacedName{Name: request.Name, Namespace: request.Namespace}
+			requests = append(requests, ctrl.Request{NamespacedName: namespaceName})
+		}
+		return requests
+	}
+}
+
+// watchCustomResource watches the custom resources of the kind created by ODLM,
+// so the changes made outside ODLM are detected without waiting for the sync period
+func (r *Reconciler) watchCustomResource(gvk schema.GroupVersionKind) {
+	if r.controller == nil {
+		return
+	}
+	if _, watched := r.watchedKinds.LoadOrStore(gvk, true); watched {
+		return
+	}
+	cr := &unstructured.Unstructured{}
+	cr.SetGroupVersionKind(gvk)
+	err := r.controller.Watch(&source.Kind{Type: cr}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: r.getCustomResourceToRequestMapper(),
+	}, predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			// Only the changes of the spec in the custom resources created by ODLM
+			return e.MetaNew.GetLabels()[constant.OpreqLabel] == "true" && e.MetaOld.GetGeneration() != e.MetaNew.GetGeneration()
+		},
+	})
+	if err != nil {
+		r.watchedKinds.Delete(gvk)
+		klog.Warningf("failed to watch the custom resources of %s: %v", gvk.String(), err)
+		return
+	}
+	klog.V(2).Infof("Watching the custom resources of %s", gvk.String())
+}
+
+// approvalAnnotationChanged checks if the ClusterServiceVersions approved by the annotation are changed
+func approvalAnnotationChanged(oldObject, newObject metav1.Object) bool {
+	return oldObject.GetAnnotations()[constant.ApproveInstallPlansAnnotation] != newObject.GetAnnotations()[constant.ApproveInstallPlansAnnotation]
+}
+
 // SetupWithManager adds OperandRequest controller to the manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&operatorv1alpha1.OperandRequest{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &operatorv1alpha1.OperandRequest{}, referenceIndexKey, indexReferences); err != nil {
+		return errors.Wrap(err, "failed to index the OperandRequests by the referenced Secrets and ConfigMaps")
+	}
+	metadataClient, err := metadata.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return errors.Wrap(err, "failed to create the client for the referenced Secrets and ConfigMaps")
+	}
+	r.references = newReferenceWatcher(metadataClient)
+	// The manager stops the watcher
+	if err := mgr.Add(r.references); err != nil {
+		return errors.Wrap(err, "failed to add the watcher of the referenced Secrets and ConfigMaps")
+	}
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1alpha1.OperandRequest{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return approvalAnnotationChanged(e.MetaOld, e.MetaNew)
+			},
+		}))).
 		Watches(&source.Kind{Type: &operatorv1alpha1.OperandRegistry{}}, &handler.EnqueueRequestsFromMapFunc{
 			ToRequests: r.getRegistryToRequestMapper(),
 		}, builder.WithPredicates(predicate.Funcs{
 			UpdateFunc: func(e event.UpdateEvent) bool {
 				oldObject := e.ObjectOld.(*operatorv1alpha1.OperandRegistry)
 				newObject := e.ObjectNew.(*operatorv1alpha1.OperandRegistry)
-				return !reflect.DeepEqual(oldObject.Spec, newObject.Spec)
+				return !reflect.DeepEqual(oldObject.Spec, newObject.Spec) || approvalAnnotationChanged(oldObject, newObject)
 			},
 			DeleteFunc: func(e event.DeleteEvent) bool {
 				// Evaluates to false if the object has been confirmed deleted.
 				return !e.DeleteStateUnknown
 			},
 		})).
+		Watches(&source.Channel{Source: r.references.events["Secret"]}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: r.getReferenceToRequestMapper("Secret"),
+		}).
+		Watches(&source.Channel{Source: r.references.events["ConfigMap"]}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: r.getReferenceToRequestMapper("ConfigMap"),
+		}).
 		Watches(&source.Kind{Type: &operatorv1alpha1.OperandConfig{}}, &handler.EnqueueRequestsFromMapFunc{
 			ToRequests: r.getConfigToRequestMapper(),
 		}, builder.WithPredicates(predicate.Funcs{
@@ -361,5 +507,10 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 				newObject := e.ObjectNew.(*operatorv1alpha1.OperandConfig)
 				return !reflect.DeepEqual(oldObject.Spec, newObject.Spec)
 			},
-		})).Complete(r)
+		})).Build(r)
+	if err != nil {
+		return err
+	}
+	r.controller = c
+	return nil
 }
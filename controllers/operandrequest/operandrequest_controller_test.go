@@ -104,55 +104,7 @@ var _ = Describe("OperandRegistry controller", func() {
 				return requestInstance.Status.Phase
 			}, testutil.Timeout, testutil.Interval).Should(Equal(operatorv1alpha1.ClusterPhaseInstalling))
 
-			By("Setting status of the Subscriptions")
-			Eventually(func() error {
-				etcdSub := &olmv1alpha1.Subscription{}
-				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "etcd", Namespace: operatorNamespaceName}, etcdSub)).Should(Succeed())
-				etcdSub.Status = testutil.SubscriptionStatus("etcd", operatorNamespaceName, "0.0.1")
-				return k8sClient.Status().Update(ctx, etcdSub)
-			}, testutil.Timeout, testutil.Interval).Should(Succeed())
-
-			Eventually(func() error {
-				jenkinsSub := &olmv1alpha1.Subscription{}
-				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "jenkins", Namespace: operatorNamespaceName}, jenkinsSub)).Should(Succeed())
-				jenkinsSub.Status = testutil.SubscriptionStatus("jenkins", operatorNamespaceName, "0.0.1")
-				return k8sClient.Status().Update(ctx, jenkinsSub)
-			}, testutil.Timeout, testutil.Interval).Should(Succeed())
-
-			By("Creating and Setting status of the ClusterServiceVersions")
-			etcdCSV := testutil.ClusterServiceVersion("etcd-csv.v0.0.1", operatorNamespaceName, testutil.EtcdExample)
-			Expect(k8sClient.Create(ctx, etcdCSV)).Should(Succeed())
-			Eventually(func() error {
-				k8sClient.Get(ctx, types.NamespacedName{Name: "etcd-csv.v0.0.1", Namespace: operatorNamespaceName}, etcdCSV)
-				etcdCSV.Status = testutil.ClusterServiceVersionStatus()
-				return k8sClient.Status().Update(ctx, etcdCSV)
-			}, testutil.Timeout, testutil.Interval).Should(Succeed())
-
-			jenkinsCSV := testutil.ClusterServiceVersion("jenkins-csv.v0.0.1", operatorNamespaceName, testutil.JenkinsExample)
-			Expect(k8sClient.Create(ctx, jenkinsCSV)).Should(Succeed())
-			Eventually(func() error {
-				k8sClient.Get(ctx, types.NamespacedName{Name: "jenkins-csv.v0.0.1", Namespace: operatorNamespaceName}, jenkinsCSV)
-				jenkinsCSV.Status = testutil.ClusterServiceVersionStatus()
-				return k8sClient.Status().Update(ctx, jenkinsCSV)
-			}, testutil.Timeout, testutil.Interval).Should(Succeed())
-
-			By("Creating and Setting status of the InstallPlan")
-			etcdIP := testutil.InstallPlan("etcd-install-plan", operatorNamespaceName)
-			Expect(k8sClient.Create(ctx, etcdIP)).Should(Succeed())
-			Eventually(func() error {
-				k8sClient.Get(ctx, types.NamespacedName{Name: "etcd-install-plan", Namespace: operatorNamespaceName}, etcdIP)
-				etcdIP.Status = testutil.InstallPlanStatus()
-				return k8sClient.Status().Update(ctx, etcdIP)
-			}, testutil.Timeout, testutil.Interval).Should(Succeed())
-
-			jenkinsIP := testutil.InstallPlan("jenkins-install-plan", operatorNamespaceName)
-			Expect(k8sClient.Create(ctx, jenkinsIP)).Should(Succeed())
-			Eventually(func() error {
-				k8sClient.Get(ctx, types.NamespacedName{Name: "jenkins-install-plan", Namespace: operatorNamespaceName}, jenkinsIP)
-				jenkinsIP.Status = testutil.InstallPlanStatus()
-				return k8sClient.Status().Update(ctx, jenkinsIP)
-			}, testutil.Timeout, testutil.Interval).Should(Succeed())
-
+			By("Waiting for the fake OLM to install the operators")
 			By("Checking of the CR of the etcd operator")
 			Eventually(func() error {
 				etcdCluster := &v1beta2.EtcdCluster{}
@@ -177,13 +129,13 @@ var _ = Describe("OperandRegistry controller", func() {
 			By("Checking operators have been deleted")
 			Eventually(func() bool {
 				etcdCSV := &olmv1alpha1.ClusterServiceVersion{}
-				err := k8sClient.Get(ctx, types.NamespacedName{Name: "etcd-csv.v0.0.1", Namespace: operatorNamespaceName}, etcdCSV)
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "etcd.v0.0.1", Namespace: operatorNamespaceName}, etcdCSV)
 				return err != nil && errors.IsNotFound(err)
 			}, testutil.Timeout, testutil.Interval).Should(BeTrue())
 
 			Eventually(func() bool {
 				jenkinsCSV := &olmv1alpha1.ClusterServiceVersion{}
-				err := k8sClient.Get(ctx, types.NamespacedName{Name: "jenkins-csv.v0.0.1", Namespace: operatorNamespaceName}, jenkinsCSV)
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "jenkins-operator.v0.0.1", Namespace: operatorNamespaceName}, jenkinsCSV)
 				return err != nil && errors.IsNotFound(err)
 			}, testutil.Timeout, testutil.Interval).Should(BeTrue())
 		})
@@ -0,0 +1,454 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	operatorv1beta1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1beta1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+)
+
+const (
+	// ValidatingWebhookPath is the path the OperandRequest validating webhook is served on
+	ValidatingWebhookPath = "/validate-operator-ibm-com-v1alpha1-operandrequest"
+	// MutatingWebhookPath is the path the OperandRequest mutating webhook is served on
+	MutatingWebhookPath = "/mutate-operator-ibm-com-v1alpha1-operandrequest"
+)
+
+// +kubebuilder:webhook:path=/mutate-operator-ibm-com-v1alpha1-operandrequest,mutating=true,failurePolicy=fail,groups=operator.ibm.com,resources=operandrequests,verbs=create;update,versions=v1alpha1;v1beta1,name=moperandrequest.operator.ibm.com
+// +kubebuilder:webhook:path=/validate-operator-ibm-com-v1alpha1-operandrequest,mutating=false,failurePolicy=fail,groups=operator.ibm.com,resources=operandrequests,verbs=create;update,versions=v1alpha1;v1beta1,name=voperandrequest.operator.ibm.com
+
+// Defaulter persists the default values of the OperandRequest spec and records its requester
+type Defaulter struct {
+	decoder *admission.Decoder
+}
+
+// Handle sets the default registry namespace for every request, and records the requester of the operands in another namespace
+func (d *Defaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	requestInstance := &operatorv1alpha1.OperandRequest{}
+	hub := &operatorv1beta1.OperandRequest{}
+	isHub := req.Kind.Version == operatorv1beta1.GroupVersion.Version
+	if err := util.DecodeAdmissionObject(d.decoder, req.Object, isHub, requestInstance, hub); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	// The namespace may be not set in the object yet, default it with the namespace of the request
+	namespace := requestInstance.Namespace
+	if namespace == "" {
+		requestInstance.Namespace = req.Namespace
+	}
+	requestInstance.Default()
+
+	var oldInstance *operatorv1alpha1.OperandRequest
+	if req.Operation == admissionv1beta1.Update {
+		oldInstance = &operatorv1alpha1.OperandRequest{}
+		if err := util.DecodeAdmissionObject(d.decoder, req.OldObject, isHub, oldInstance, &operatorv1beta1.OperandRequest{}); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	if err := setRequester(requestInstance, oldInstance, req.UserInfo); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	requestInstance.Namespace = namespace
+
+	marshaled, err := util.EncodeAdmissionObject(requestInstance, isHub, hub)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// Validator validates the OperandRequest against the OperandRegistry it references
+type Validator struct {
+	*deploy.ODLMOperator
+	decoder *admission.Decoder
+}
+
+// Handle rejects the OperandRequest if it requests an operand that can't be installed
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	requestInstance, err := v.decodeOperandRequest(req.Object, req.Kind.Version)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// The finalizer of a deleting OperandRequest must always be removable
+	if requestInstance.GetDeletionTimestamp() != nil {
+		return admission.Allowed("")
+	}
+
+	// Metadata updates from the controllers don't change what is requested
+	if req.Operation == admissionv1beta1.Update {
+		oldInstance, err := v.decodeOperandRequest(req.OldObject, req.Kind.Version)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if reflect.DeepEqual(oldInstance.Spec, requestInstance.Spec) {
+			return admission.Allowed("")
+		}
+	}
+
+	allErrs, err := v.validate(ctx, requestInstance)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	namespaceErrs, err := v.validateNamespaces(ctx, requestInstance, req.UserInfo)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	allErrs = append(allErrs, namespaceErrs...)
+	if len(allErrs) != 0 {
+		klog.V(2).Infof("Rejected OperandRequest %s/%s: %v", requestInstance.Namespace, requestInstance.Name, allErrs.ToAggregate())
+		return admission.Denied(allErrs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder into the Defaulter
+func (d *Defaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// SetupWebhookWithManager registers the mutating webhook of OperandRequest
+func (d *Defaulter) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(MutatingWebhookPath, &webhook.Admission{Handler: d})
+	return nil
+}
+
+// InjectDecoder injects the decoder into the Validator
+func (v *Validator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// decodeOperandRequest decodes the OperandRequest in any served version into v1alpha1
+func (v *Validator) decodeOperandRequest(raw runtime.RawExtension, version string) (*operatorv1alpha1.OperandRequest, error) {
+	requestInstance := &operatorv1alpha1.OperandRequest{}
+	isHub := version == operatorv1beta1.GroupVersion.Version
+	if err := util.DecodeAdmissionObject(v.decoder, raw, isHub, requestInstance, &operatorv1beta1.OperandRequest{}); err != nil {
+		return nil, err
+	}
+	return requestInstance, nil
+}
+
+// validate checks every requested operand against the OperandRegistry and the CSV of its operator
+func (v *Validator) validate(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) (field.ErrorList, error) {
+	allErrs := field.ErrorList{}
+	operandSet := make(map[string]struct{})
+	requestsPath := field.NewPath("spec", "requests")
+
+	for i, req := range requestInstance.Spec.Requests {
+		reqPath := requestsPath.Index(i)
+		registryKey := requestInstance.GetRegistryKey(req)
+		registryInstance, err := v.GetOperandRegistry(ctx, registryKey)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				allErrs = append(allErrs, field.Invalid(reqPath.Child("registry"), req.Registry, fmt.Sprintf("OperandRegistry %s is not found", registryKey.String())))
+				continue
+			}
+			return nil, err
+		}
+
+		for j, operand := range req.Operands {
+			operandPath := reqPath.Child("operands").Index(j)
+
+			operandKey := registryKey.String() + "/" + operand.Name + "/" + operand.Kind + "/" + operand.InstanceName
+			if len(operand.Resources) != 0 {
+				operandKey = registryKey.String() + "/" + operand.Name + "/resources"
+			}
+			if _, ok := operandSet[operandKey]; ok {
+				allErrs = append(allErrs, field.Duplicate(operandPath, operand.Name))
+				continue
+			}
+			operandSet[operandKey] = struct{}{}
+
+			opt := registryInstance.GetOperator(operand.Name)
+			if opt == nil {
+				allErrs = append(allErrs, field.Invalid(operandPath.Child("name"), operand.Name, fmt.Sprintf("operand is not found in the OperandRegistry %s", registryKey.String())))
+				continue
+			}
+
+			if opt.Scope == operatorv1alpha1.ScopePrivate && requestInstance.Namespace != registryInstance.Namespace {
+				allErrs = append(allErrs, field.Forbidden(operandPath.Child("name"), fmt.Sprintf("operand %s is private in the OperandRegistry %s and can only be requested from the namespace %s", operand.Name, registryKey.String(), registryInstance.Namespace)))
+				continue
+			}
+
+			if len(operand.Resources) != 0 && (operand.Kind != "" || operand.InstanceName != "" || operand.Spec != nil) {
+				allErrs = append(allErrs, field.Invalid(operandPath.Child("resources"), len(operand.Resources), "resources can't be set with the kind, instanceName and spec of the operand"))
+				continue
+			}
+			if operand.Kind == "" && len(operand.Resources) == 0 {
+				continue
+			}
+			kinds, err := v.getExampleKinds(ctx, registryInstance, opt)
+			if err != nil {
+				return nil, err
+			}
+			if len(operand.Resources) == 0 {
+				// The kind can only be checked once the operator is installed
+				if kinds != nil && !containsString(kinds, operand.Kind) {
+					allErrs = append(allErrs, field.NotSupported(operandPath.Child("kind"), operand.Kind, kinds))
+				}
+				continue
+			}
+
+			resourceSet := make(map[string]struct{})
+			for k, resource := range requestInstance.GetOperandResources(operand) {
+				resourcePath := operandPath.Child("resources").Index(k)
+				resourceKey := resource.Kind + "/" + resource.Name
+				if _, ok := resourceSet[resourceKey]; ok {
+					allErrs = append(allErrs, field.Duplicate(resourcePath, resourceKey))
+					continue
+				}
+				resourceSet[resourceKey] = struct{}{}
+				if kinds != nil && !containsString(kinds, resource.Kind) {
+					allErrs = append(allErrs, field.NotSupported(resourcePath.Child("kind"), resource.Kind, kinds))
+				}
+			}
+		}
+	}
+	return allErrs, nil
+}
+
+// getExampleKinds returns the kinds in the templates of the custom resources offered by the operator,
+// or nil if the templates are not available yet
+func (v *Validator) getExampleKinds(ctx context.Context, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) ([]string, error) {
+	gvks, err := v.getExampleGVKs(ctx, registryInstance, opt)
+	if err != nil || gvks == nil {
+		return nil, err
+	}
+	kinds := []string{}
+	for _, gvk := range gvks {
+		if !containsString(kinds, gvk.Kind) {
+			kinds = append(kinds, gvk.Kind)
+		}
+	}
+	return kinds, nil
+}
+
+// getExampleGVKs returns the GroupVersionKinds in the templates of the custom resources offered by the operator,
+// like the alm-examples of its ClusterServiceVersion, or nil if the templates are not available yet
+func (v *Validator) getExampleGVKs(ctx context.Context, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) ([]schema.GroupVersionKind, error) {
+	templates, err := v.GetInstaller(registryInstance, opt).Templates(ctx, registryInstance, opt)
+	if err != nil {
+		klog.Warningf("failed to get the templates of the custom resources of the operator %s: %v", opt.Name, err)
+		return nil, nil
+	}
+	if templates == nil {
+		return nil, nil
+	}
+	gvks := []schema.GroupVersionKind{}
+	for _, template := range templates {
+		if template.GetKind() != "" {
+			gvks = append(gvks, template.GroupVersionKind())
+		}
+	}
+	return gvks, nil
+}
+
+// validateNamespaces checks the requester is allowed to create the custom resources of the operands in their namespaces
+func (v *Validator) validateNamespaces(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, userInfo authenticationv1.UserInfo) (field.ErrorList, error) {
+	allErrs := field.ErrorList{}
+	requestsPath := field.NewPath("spec", "requests")
+
+	for i, req := range requestInstance.Spec.Requests {
+		reqPath := requestsPath.Index(i)
+		for j, operand := range req.Operands {
+			namespace := requestInstance.GetOperandNamespace(operand)
+			if namespace == requestInstance.Namespace {
+				continue
+			}
+			namespacePath := reqPath.Child("operands").Index(j).Child("namespace")
+			resources := requestInstance.GetOperandResources(operand)
+			if len(resources) == 0 {
+				allErrs = append(allErrs, field.Invalid(namespacePath, operand.Namespace, "the namespace can only be set with the kind or the resources of the operand"))
+				continue
+			}
+
+			// The unknown OperandRegistry and operands are rejected by validate
+			registryInstance, err := v.GetOperandRegistry(ctx, requestInstance.GetRegistryKey(req))
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			opt := registryInstance.GetOperator(operand.Name)
+			if opt == nil {
+				continue
+			}
+
+			gvks, err := v.getExampleGVKs(ctx, registryInstance, opt)
+			if err != nil {
+				return nil, err
+			}
+			var kinds []string
+			for _, resource := range resources {
+				if containsString(kinds, resource.Kind) {
+					continue
+				}
+				kinds = append(kinds, resource.Kind)
+
+				var mapping *meta.RESTMapping
+				for _, gvk := range gvks {
+					if gvk.Kind != resource.Kind {
+						continue
+					}
+					if mapping, err = v.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil && !meta.IsNoMatchError(err) {
+						return nil, err
+					}
+					if mapping != nil {
+						break
+					}
+				}
+				// The CRDs of the operator aren't installed yet, the reconciler checks the permission
+				// of the recorded requester before it creates the custom resource
+				if mapping == nil {
+					continue
+				}
+
+				allowed, err := checkCreateAuth(ctx, v.Client, userInfo, namespace, mapping.Resource)
+				if err != nil {
+					return nil, err
+				}
+				if !allowed {
+					allErrs = append(allErrs, field.Forbidden(namespacePath, fmt.Sprintf("%s is not allowed to create %s in the namespace %s", userInfo.Username, mapping.Resource.GroupResource().String(), namespace)))
+				}
+			}
+		}
+	}
+	return allErrs, nil
+}
+
+// checkCreateAuth checks if the user is allowed to create the resource in the namespace
+func checkCreateAuth(ctx context.Context, c client.Client, userInfo authenticationv1.UserInfo, namespace string, resource schema.GroupVersionResource) (bool, error) {
+	extra := make(map[string]authorizationv1.ExtraValue)
+	for k, value := range userInfo.Extra {
+		extra[k] = authorizationv1.ExtraValue(value)
+	}
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "create",
+				Group:     resource.Group,
+				Version:   resource.Version,
+				Resource:  resource.Resource,
+			},
+			User:   userInfo.Username,
+			Groups: userInfo.Groups,
+			UID:    userInfo.UID,
+			Extra:  extra,
+		},
+	}
+	if err := c.Create(ctx, sar); err != nil {
+		return false, errors.Wrapf(err, "failed to check the permission of %s to create %s in the namespace %s", userInfo.Username, resource.GroupResource().String(), namespace)
+	}
+	klog.V(3).Infof("Permission of %s to create %s in the namespace %s, Allowed: %t, Reason: %s", userInfo.Username, resource.GroupResource().String(), namespace, sar.Status.Allowed, sar.Status.Reason)
+	return sar.Status.Allowed, nil
+}
+
+// setRequester records the user who creates or changes the spec of the OperandRequest in its annotation, if an operand
+// creates its custom resources in another namespace, so the reconciler can check the permission of the user there.
+// The annotation set by the user is replaced, and the one of an update which doesn't change the spec is kept.
+func setRequester(requestInstance, oldInstance *operatorv1alpha1.OperandRequest, userInfo authenticationv1.UserInfo) error {
+	annotations := requestInstance.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	delete(annotations, constant.OpreqRequesterAnnotation)
+
+	if oldInstance != nil && reflect.DeepEqual(oldInstance.Spec, requestInstance.Spec) {
+		if requester, ok := oldInstance.GetAnnotations()[constant.OpreqRequesterAnnotation]; ok {
+			annotations[constant.OpreqRequesterAnnotation] = requester
+		}
+	} else if hasOperandInOtherNamespace(requestInstance) {
+		requester, err := json.Marshal(userInfo)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the requester of the OperandRequest")
+		}
+		annotations[constant.OpreqRequesterAnnotation] = string(requester)
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	requestInstance.SetAnnotations(annotations)
+	return nil
+}
+
+// getRequester returns the user recorded by setRequester, or nil if it isn't recorded
+func getRequester(requestInstance *operatorv1alpha1.OperandRequest) (*authenticationv1.UserInfo, error) {
+	value, ok := requestInstance.GetAnnotations()[constant.OpreqRequesterAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	userInfo := &authenticationv1.UserInfo{}
+	if err := json.Unmarshal([]byte(value), userInfo); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the annotation %s of the OperandRequest %s/%s", constant.OpreqRequesterAnnotation, requestInstance.Namespace, requestInstance.Name)
+	}
+	return userInfo, nil
+}
+
+// hasOperandInOtherNamespace checks if an operand creates its custom resources outside the namespace of the OperandRequest
+func hasOperandInOtherNamespace(requestInstance *operatorv1alpha1.OperandRequest) bool {
+	for _, req := range requestInstance.Spec.Requests {
+		for _, operand := range req.Operands {
+			if requestInstance.GetOperandNamespace(operand) != requestInstance.Namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWebhookWithManager registers the validating webhook of OperandRequest
+func (v *Validator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(ValidatingWebhookPath, &webhook.Admission{Handler: v})
+	return nil
+}
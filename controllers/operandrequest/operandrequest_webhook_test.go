@@ -0,0 +1,344 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	operatorv1beta1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1beta1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
+)
+
+var _ = Describe("OperandRequest validating webhook", func() {
+	const (
+		name              = "ibm-cloudpak-name"
+		namespace         = "ibm-cloudpak"
+		registryName      = "common-service"
+		registryNamespace = "ibm-common-services"
+		operatorNamespace = "ibm-operators"
+	)
+
+	var (
+		ctx       context.Context
+		registry  *operatorv1alpha1.OperandRegistry
+		request   *operatorv1alpha1.OperandRequest
+		validator *Validator
+	)
+
+	newValidator := func(objs ...runtime.Object) *Validator {
+		scheme := runtime.NewScheme()
+		Expect(operatorv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		Expect(operatorv1beta1.AddToScheme(scheme)).Should(Succeed())
+		Expect(olmv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		decoder, err := admission.NewDecoder(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		v := &Validator{
+			ODLMOperator: &deploy.ODLMOperator{
+				Client: fake.NewFakeClientWithScheme(scheme, objs...),
+				Scheme: scheme,
+			},
+		}
+		Expect(v.InjectDecoder(decoder)).Should(Succeed())
+		return v
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		registry = testutil.OperandRegistryObj(registryName, registryNamespace, operatorNamespace)
+		request = testutil.OperandRequestObj(registryName, registryNamespace, name, namespace)
+
+		sub := testutil.Subscription("etcd", operatorNamespace)
+		sub.Status = testutil.SubscriptionStatus("etcd", operatorNamespace, "0.0.1")
+		ip := testutil.InstallPlan("etcd-install-plan", operatorNamespace)
+		ip.Status = testutil.InstallPlanStatus()
+		csv := testutil.ClusterServiceVersion("etcd-csv.v0.0.1", operatorNamespace, testutil.EtcdExample)
+
+		validator = newValidator(registry, sub, ip, csv)
+	})
+
+	Context("Validating an OperandRequest", func() {
+		It("Should allow the operands in the OperandRegistry", func() {
+			request.Spec.Requests[0].Operands[0].Kind = "EtcdCluster"
+			request.Spec.Requests[0].Operands[0].InstanceName = "example"
+			allErrs, err := validator.validate(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(BeEmpty())
+		})
+
+		It("Should reject the missing OperandRegistry", func() {
+			request.Spec.Requests[0].Registry = "common-services"
+			allErrs, err := validator.validate(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Type).Should(Equal(field.ErrorTypeInvalid))
+			Expect(allErrs[0].Field).Should(Equal("spec.requests[0].registry"))
+		})
+
+		It("Should reject the unknown operand", func() {
+			request.Spec.Requests[0].Operands[0].Name = "etcdd"
+			allErrs, err := validator.validate(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Field).Should(Equal("spec.requests[0].operands[0].name"))
+			Expect(allErrs[0].Detail).Should(ContainSubstring("not found in the OperandRegistry"))
+		})
+
+		It("Should reject the private operand requested from another namespace", func() {
+			registry.Spec.Operators[1].Scope = operatorv1alpha1.ScopePrivate
+			validator = newValidator(registry)
+			allErrs, err := validator.validate(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Type).Should(Equal(field.ErrorTypeForbidden))
+			Expect(allErrs[0].Field).Should(Equal("spec.requests[0].operands[1].name"))
+		})
+
+		It("Should reject the duplicate operand", func() {
+			request.Spec.Requests[0].Operands = append(request.Spec.Requests[0].Operands, operatorv1alpha1.Operand{Name: "etcd"})
+			allErrs, err := validator.validate(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Type).Should(Equal(field.ErrorTypeDuplicate))
+			Expect(allErrs[0].Field).Should(Equal("spec.requests[0].operands[2]"))
+		})
+
+		It("Should reject the kind missing from the alm-examples", func() {
+			request.Spec.Requests[0].Operands[0].Kind = "EtcdBackup"
+			allErrs, err := validator.validate(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Type).Should(Equal(field.ErrorTypeNotSupported))
+			Expect(allErrs[0].Detail).Should(ContainSubstring("EtcdCluster"))
+		})
+
+		It("Should skip the kind check before the operator is installed", func() {
+			request.Spec.Requests[0].Operands[1].Kind = "Jenkins"
+			allErrs, err := validator.validate(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(BeEmpty())
+		})
+	})
+
+	Context("Validating the resources of an operand", func() {
+		It("Should allow the resources in the alm-examples", func() {
+			request.Spec.Requests[0].Operands[0].Resources = []operatorv1alpha1.OperandResource{
+				{Kind: "EtcdCluster", Name: "orders"},
+				{Kind: "EtcdCluster", Name: "payments"},
+			}
+			allErrs, err := validator.validate(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(BeEmpty())
+		})
+
+		It("Should reject the resources with the kind of the operand", func() {
+			request.Spec.Requests[0].Operands[0].Kind = "EtcdCluster"
+			request.Spec.Requests[0].Operands[0].Resources = []operatorv1alpha1.OperandResource{{Kind: "EtcdCluster", Name: "orders"}}
+			allErrs, err := validator.validate(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Type).Should(Equal(field.ErrorTypeInvalid))
+			Expect(allErrs[0].Field).Should(Equal("spec.requests[0].operands[0].resources"))
+		})
+
+		It("Should reject the duplicate resource", func() {
+			request.Spec.Requests[0].Operands[0].Resources = []operatorv1alpha1.OperandResource{
+				{Kind: "EtcdCluster"},
+				{Kind: "EtcdCluster", Name: name},
+			}
+			allErrs, err := validator.validate(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Type).Should(Equal(field.ErrorTypeDuplicate))
+			Expect(allErrs[0].Field).Should(Equal("spec.requests[0].operands[0].resources[1]"))
+		})
+
+		It("Should reject the kind of a resource missing from the alm-examples", func() {
+			request.Spec.Requests[0].Operands[0].Resources = []operatorv1alpha1.OperandResource{
+				{Kind: "EtcdCluster", Name: "orders"},
+				{Kind: "EtcdBackup", Name: "orders"},
+			}
+			allErrs, err := validator.validate(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Type).Should(Equal(field.ErrorTypeNotSupported))
+			Expect(allErrs[0].Field).Should(Equal("spec.requests[0].operands[0].resources[1].kind"))
+		})
+	})
+
+	Context("Validating the namespace of the operands", func() {
+		var userInfo authenticationv1.UserInfo
+
+		BeforeEach(func() {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(schema.GroupVersionKind{Group: "etcd.database.coreos.com", Version: "v1beta2", Kind: "EtcdCluster"}, meta.RESTScopeNamespace)
+			validator.RESTMapper = mapper
+			validator.Client = &accessReviewClient{Client: validator.Client, allowedNamespaces: []string{"ibm-cloudpak-data"}}
+			userInfo = authenticationv1.UserInfo{Username: "tenant", Groups: []string{"system:authenticated"}}
+			request.Spec.Requests[0].Operands[0].Kind = "EtcdCluster"
+		})
+
+		It("Should allow the namespace in which the requester can create the kind", func() {
+			request.Spec.Requests[0].Operands[0].Namespace = "ibm-cloudpak-data"
+			allErrs, err := validator.validateNamespaces(ctx, request, userInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(BeEmpty())
+		})
+
+		It("Should reject the namespace in which the requester can't create the kind", func() {
+			request.Spec.Requests[0].Operands[0].Namespace = "kube-system"
+			allErrs, err := validator.validateNamespaces(ctx, request, userInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Type).Should(Equal(field.ErrorTypeForbidden))
+			Expect(allErrs[0].Field).Should(Equal("spec.requests[0].operands[0].namespace"))
+			Expect(allErrs[0].Detail).Should(ContainSubstring("tenant is not allowed to create etcdclusters.etcd.database.coreos.com in the namespace kube-system"))
+		})
+
+		It("Should reject the namespace without the kind", func() {
+			request.Spec.Requests[0].Operands[1].Namespace = "ibm-cloudpak-data"
+			allErrs, err := validator.validateNamespaces(ctx, request, userInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(HaveLen(1))
+			Expect(allErrs[0].Type).Should(Equal(field.ErrorTypeInvalid))
+		})
+
+		It("Should allow the namespace before the operator is installed", func() {
+			request.Spec.Requests[0].Operands[1].Kind = "Jenkins"
+			request.Spec.Requests[0].Operands[1].Namespace = "kube-system"
+			allErrs, err := validator.validateNamespaces(ctx, request, userInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allErrs).Should(BeEmpty())
+		})
+	})
+
+	Context("Handling the admission request", func() {
+		It("Should deny the v1beta1 OperandRequest with a clear message", func() {
+			request.Spec.Requests[0].Operands[0].Name = "etcdd"
+			hub := &operatorv1beta1.OperandRequest{}
+			Expect(request.ConvertTo(hub)).Should(Succeed())
+			raw, err := json.Marshal(hub)
+			Expect(err).NotTo(HaveOccurred())
+
+			resp := validator.Handle(ctx, admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Create,
+				Kind:      metav1.GroupVersionKind{Group: operatorv1beta1.GroupVersion.Group, Version: operatorv1beta1.GroupVersion.Version, Kind: "OperandRequest"},
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			Expect(resp.Allowed).Should(BeFalse())
+			Expect(string(resp.Result.Reason)).Should(ContainSubstring("spec.requests[0].operands[0].name"))
+		})
+
+		It("Should allow the update which doesn't change the spec", func() {
+			request.Spec.Requests[0].Operands[0].Name = "etcdd"
+			raw, err := json.Marshal(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			resp := validator.Handle(ctx, admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Update,
+				Kind:      metav1.GroupVersionKind{Group: operatorv1alpha1.GroupVersion.Group, Version: operatorv1alpha1.GroupVersion.Version, Kind: "OperandRequest"},
+				Object:    runtime.RawExtension{Raw: raw},
+				OldObject: runtime.RawExtension{Raw: raw},
+			}})
+			Expect(resp.Allowed).Should(BeTrue())
+		})
+	})
+
+	Context("Defaulting an OperandRequest", func() {
+		It("Should persist the namespace of the request as the registry namespace", func() {
+			request.Spec.Requests[0].RegistryNamespace = ""
+			request.Namespace = ""
+			raw, err := json.Marshal(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			defaulter := &Defaulter{}
+			Expect(defaulter.InjectDecoder(validator.decoder)).Should(Succeed())
+			resp := defaulter.Handle(ctx, admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Create,
+				Namespace: namespace,
+				Kind:      metav1.GroupVersionKind{Group: operatorv1alpha1.GroupVersion.Group, Version: operatorv1alpha1.GroupVersion.Version, Kind: "OperandRequest"},
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+			Expect(resp.Allowed).Should(BeTrue())
+
+			var registryNamespacePatch interface{}
+			for _, patch := range resp.Patches {
+				if patch.Path == "/spec/requests/0/registryNamespace" {
+					registryNamespacePatch = patch.Value
+				}
+			}
+			Expect(registryNamespacePatch).Should(Equal(namespace))
+		})
+
+		It("Should record the requester of the operands in another namespace", func() {
+			request.Spec.Requests[0].Operands[0].Kind = "EtcdCluster"
+			request.Spec.Requests[0].Operands[0].Namespace = "ibm-cloudpak-data"
+			request.Annotations = map[string]string{constant.OpreqRequesterAnnotation: `{"username":"cluster-admin"}`}
+			userInfo := authenticationv1.UserInfo{Username: "tenant"}
+
+			Expect(setRequester(request, nil, userInfo)).Should(Succeed())
+			requester, err := getRequester(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requester.Username).Should(Equal("tenant"))
+
+			// An update which doesn't change the spec keeps the requester
+			updated := request.DeepCopy()
+			updated.Annotations[constant.OpreqRequesterAnnotation] = `{"username":"cluster-admin"}`
+			Expect(setRequester(updated, request, authenticationv1.UserInfo{Username: "other"})).Should(Succeed())
+			requester, err = getRequester(updated)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requester.Username).Should(Equal("tenant"))
+
+			// The requester isn't recorded without an operand in another namespace
+			updated.Spec.Requests[0].Operands[0].Namespace = ""
+			Expect(setRequester(updated, request, userInfo)).Should(Succeed())
+			Expect(updated.Annotations).ShouldNot(HaveKey(constant.OpreqRequesterAnnotation))
+		})
+	})
+})
+
+// accessReviewClient allows the SubjectAccessReviews in the allowed namespaces, which the fake client can't review
+type accessReviewClient struct {
+	client.Client
+	allowedNamespaces []string
+}
+
+func (c *accessReviewClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	if sar, ok := obj.(*authorizationv1.SubjectAccessReview); ok {
+		sar.Status.Allowed = containsString(c.allowedNamespaces, sar.Spec.ResourceAttributes.Namespace)
+		return nil
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
@@ -20,20 +20,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"reflect"
+	"sort"
 	"strings"
 	"time"
 
-	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	gset "github.com/deckarep/golang-set"
 	"github.com/pkg/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
 	constant "github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
 	util "github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
 )
 
@@ -49,6 +56,18 @@ func (r *Reconciler) reconcileOperand(ctx context.Context, requestInstance *oper
 		merr.Add(err)
 		return merr
 	}
+	parameters, err := r.GetTemplateParameters(ctx)
+	if err != nil {
+		merr.Add(err)
+		return merr
+	}
+	// The Secrets and ConfigMaps referenced by the valueFrom in the specs, to watch their changes
+	references := gset.NewSet()
+	defer func() {
+		if err := r.updateReferences(ctx, requestInstance, references); err != nil {
+			merr.Add(err)
+		}
+	}()
 	for _, req := range requestInstance.Spec.Requests {
 		registryKey := requestInstance.GetRegistryKey(req)
 		configInstance, err := r.GetOperandConfig(ctx, registryKey)
@@ -61,7 +80,12 @@ func (r *Reconciler) reconcileOperand(ctx context.Context, requestInstance *oper
 			merr.Add(errors.Wrapf(err, "failed to get the OperandRegistry %s", registryKey.String()))
 			continue
 		}
-		for _, operand := range req.Operands {
+		operands, err := getOperandsInOrder(registryInstance, req.Operands)
+		if err != nil {
+			merr.Add(err)
+			continue
+		}
+		for _, operand := range operands {
 
 			opdRegistry := registryInstance.GetOperator(operand.Name)
 			if opdRegistry == nil {
@@ -70,217 +94,503 @@ func (r *Reconciler) reconcileOperand(ctx context.Context, requestInstance *oper
 			}
 
 			operatorName := opdRegistry.Name
+			klog.V(3).Info("Looking for the status of the operator: ", operatorName)
 
-			klog.V(3).Info("Looking for csv for the operator: ", operatorName)
-
-			// Looking for the CSV
 			namespace := r.GetOperatorNamespace(opdRegistry.InstallMode, opdRegistry.Namespace)
+			installer := r.GetInstaller(registryInstance, opdRegistry)
+			status, err := installer.Status(ctx, registryInstance, opdRegistry)
 
-			sub, err := r.GetSubscription(ctx, operatorName, namespace, opdRegistry.PackageName)
-
-			if apierrors.IsNotFound(err) {
-				klog.Warningf("There is no Subscription %s or %s in the namespace %s", operatorName, opdRegistry.PackageName, namespace)
+			// If can't get the status, requeue the request
+			if err != nil {
+				merr.Add(err)
+				requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorFailed, "")
 				continue
 			}
 
-			if _, ok := sub.Labels[constant.OpreqLabel]; !ok {
-				// Subscription existing and not managed by OperandRequest controller
-				klog.Warningf("Subscription %s in the namespace %s isn't created by ODLM", sub.Name, sub.Namespace)
+			if !status.Installed {
+				klog.Warningf("The operator %s isn't installed in the namespace %s", operatorName, namespace)
+				continue
 			}
 
-			csv, err := r.GetClusterServiceVersion(ctx, sub)
+			if !status.Managed {
+				// Operator existing and not managed by OperandRequest controller
+				klog.Warningf("The operator %s in the namespace %s isn't installed by ODLM", operatorName, namespace)
+			}
 
-			// If can't get CSV, requeue the request
-			if err != nil {
-				merr.Add(err)
-				requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorFailed, "")
+			requestInstance.SetMemberStatus(operand.Name, status.Phase, "")
+			if status.Phase == operatorv1alpha1.OperatorFailed {
+				merr.Add(fmt.Errorf("the operator %s in the namespace %s is Failed", operatorName, namespace))
 				continue
 			}
-
-			if csv == nil {
-				klog.Warningf("ClusterServiceVersion for the Subscription %s in the namespace %s is not ready yet, retry", operatorName, namespace)
-				requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorInstalling, "")
+			if status.Phase != operatorv1alpha1.OperatorRunning {
+				klog.V(2).Infof("The operator %s in the namespace %s is not Ready", operatorName, namespace)
 				continue
 			}
 
-			if csv.Status.Phase == olmv1alpha1.CSVPhaseFailed {
-				merr.Add(fmt.Errorf("the ClusterServiceVersion of Subscription %s/%s is Failed", namespace, operatorName))
+			// The custom resources are created from the templates offered by the operator,
+			// like the alm-examples of the ClusterServiceVersion
+			templates, err := installer.Templates(ctx, registryInstance, opdRegistry)
+			if err != nil {
+				merr.Add(err)
 				requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorFailed, "")
 				continue
 			}
-			if csv.Status.Phase != olmv1alpha1.CSVPhaseSucceeded {
-				klog.Errorf("the ClusterServiceVersion of Subscription %s/%s is not Ready", namespace, operatorName)
-				requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorInstalling, "")
+			if len(templates) == 0 {
+				klog.V(2).Infof("Operator %s doesn't offer any custom resource template, skip creating the custom resources for it", operatorName)
 				continue
 			}
 
-			klog.V(3).Info("Generating customresource base on ClusterServiceVersion: ", csv.ObjectMeta.Name)
-			requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorRunning, "")
+			klog.V(3).Info("Generating customresource base on the templates of the operator: ", operatorName)
+
+			values := util.TemplateValues{
+				RequestNamespace:  requestInstance.Namespace,
+				RegistryNamespace: registryInstance.Namespace,
+				OperatorNamespace: namespace,
+				Parameters:        parameters,
+			}
 
 			// Merge and Generate CR
-			if operand.Kind == "" {
+			if len(requestInstance.GetOperandResources(operand)) == 0 {
 				// Check the requested Service Config if exist in specific OperandConfig
 				opdConfig := configInstance.GetService(operand.Name)
 				if opdConfig == nil {
 					klog.V(2).Infof("There is no service: %s from the OperandConfig instance: %s/%s, Skip creating CR for it", operand.Name, req.RegistryNamespace, req.Registry)
 					continue
 				}
-				err = r.reconcileCRwithConfig(ctx, opdConfig, opdRegistry.Namespace, csv)
+				// Don't create or update any custom resource with a half-rendered spec
+				opdConfig, err = renderConfigService(opdConfig, values)
+				if err == nil {
+					opdConfig, err = r.resolveConfigService(ctx, opdConfig, configInstance.Namespace, references)
+				}
+				if err != nil {
+					merr.Add(err)
+					requestInstance.SetUnresolvedTemplateCondition(operand.Name, err.Error())
+					requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed)
+					continue
+				}
+				requestInstance.RemoveMemberCondition(operand.Name, operatorv1alpha1.ConditionUnresolved)
+				err = r.reconcileCRwithConfig(ctx, requestInstance, registryKey, opdConfig, opdRegistry.Namespace, templates)
+				if err == nil && !opdConfig.IsEnabled() {
+					requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceDisabled)
+					continue
+				}
 			} else {
-				err = r.reconcileCRwithRequest(ctx, requestInstance, operand, types.NamespacedName{Name: requestInstance.Name, Namespace: requestInstance.Namespace}, csv)
+				operand, err = renderOperand(operand, values)
+				if err == nil {
+					operand, err = r.resolveOperand(ctx, operand, requestInstance.Namespace, references)
+				}
+				if err != nil {
+					merr.Add(err)
+					requestInstance.SetUnresolvedTemplateCondition(operand.Name, err.Error())
+					requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed)
+					continue
+				}
+				requestInstance.RemoveMemberCondition(operand.Name, operatorv1alpha1.ConditionUnresolved)
+				err = r.reconcileCRwithRequest(ctx, requestInstance, operand, requestInstance.GetOperandNamespace(operand), templates)
 			}
 
 			if err != nil {
 				merr.Add(err)
 				requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed)
+				continue
 			}
-			requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceRunning)
+			// A custom resource whose JSON patch fails, or which the requester isn't allowed to create, isn't created or updated,
+			// the other operands are still reconciled
+			if requestInstance.HasMemberCondition(operand.Name, operatorv1alpha1.ConditionPatchFailed) || requestInstance.HasMemberCondition(operand.Name, operatorv1alpha1.ConditionForbidden) {
+				requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed)
+				continue
+			}
+
+			// The phase of the operand comes from the status of its custom resources
+			var crs []unstructured.Unstructured
+			resources := requestInstance.GetOperandResources(operand)
+			if len(resources) == 0 {
+				crs, err = r.getOperandCustomResources(ctx, templates, opdRegistry.Namespace, configInstance.GetService(operand.Name).Spec, "")
+			}
+			for _, resource := range resources {
+				var resourceCRs []unstructured.Unstructured
+				resourceCRs, err = r.getOperandCustomResources(ctx, templates, requestInstance.GetOperandNamespace(operand), map[string]runtime.RawExtension{resource.Kind: {}}, resource.Name)
+				if err != nil {
+					break
+				}
+				crs = append(crs, resourceCRs...)
+			}
+			if err != nil {
+				merr.Add(err)
+				requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed)
+				continue
+			}
+			checkOperandHealth(requestInstance, operand.Name, crs, opdRegistry.Readiness)
 		}
 	}
 	if len(merr.Errors) != 0 {
 		return merr
 	}
 	klog.V(1).Infof("Finished reconciling Operands for OperandRequest: %s/%s", requestInstance.GetNamespace(), requestInstance.GetName())
-	return &util.MultiErr{}
+	// The errors of the deferred functions are added to it
+	return merr
+}
+
+// getOperandCustomResources gets the custom resources created by ODLM from the templates of the operator,
+// whose kinds are in the spec. The name of the custom resources is the one in the template if it is not set.
+func (r *Reconciler) getOperandCustomResources(ctx context.Context, templates []unstructured.Unstructured, namespace string, spec map[string]runtime.RawExtension, name string) ([]unstructured.Unstructured, error) {
+	var crs []unstructured.Unstructured
+	for _, template := range templates {
+		var found bool
+		for kind := range spec {
+			if strings.EqualFold(kind, template.GetKind()) {
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+
+		crName := name
+		if crName == "" {
+			crName = template.GetName()
+		}
+		cr := unstructured.Unstructured{}
+		cr.SetGroupVersionKind(template.GroupVersionKind())
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: crName, Namespace: namespace}, &cr); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get the custom resource %s %s/%s", template.GetKind(), namespace, crName)
+		}
+		if !checkLabel(cr, map[string]string{constant.OpreqLabel: "true"}) {
+			continue
+		}
+		crs = append(crs, cr)
+	}
+	return crs, nil
 }
 
-// reconcileCRwithConfig merge and create custom resource base on OperandConfig and CSV alm-examples
-func (r *Reconciler) reconcileCRwithConfig(ctx context.Context, service *operatorv1alpha1.ConfigService, namespace string, csv *olmv1alpha1.ClusterServiceVersion) error {
-	almExamples := csv.ObjectMeta.Annotations["alm-examples"]
+// checkOperandHealth sets the phase of the operand from the status of its custom resources,
+// and reports the custom resources which are not ready in the member status
+func checkOperandHealth(requestInstance *operatorv1alpha1.OperandRequest, name string, crs []unstructured.Unstructured, readiness *operatorv1alpha1.ReadinessExpression) {
+	phase := operatorv1alpha1.ServiceRunning
+	var notReady []string
+	for i := range crs {
+		crPhase, message := deploy.GetCustomResourcePhase(&crs[i], readiness)
+		if crPhase == operatorv1alpha1.ServiceRunning {
+			continue
+		}
+		if phase != operatorv1alpha1.ServiceFailed {
+			phase = crPhase
+		}
+		notReady = append(notReady, fmt.Sprintf("%s %s/%s is %s: %s", crs[i].GetKind(), crs[i].GetNamespace(), crs[i].GetName(), crPhase, message))
+	}
+	if len(notReady) == 0 {
+		requestInstance.RemoveMemberCondition(name, operatorv1alpha1.ConditionOperandNotReady)
+	} else {
+		requestInstance.SetOperandNotReadyCondition(name, "Operand"+string(phase), strings.Join(notReady, "; "))
+	}
+	requestInstance.SetMemberStatus(name, "", phase)
+}
 
-	// Create a slice for crTemplates
-	var crTemplates []interface{}
+// renderConfigService returns a copy of the service with the templates in its spec resolved
+func renderConfigService(service *operatorv1alpha1.ConfigService, values util.TemplateValues) (*operatorv1alpha1.ConfigService, error) {
+	rendered := service.DeepCopy()
+	for crdName, crdConfig := range rendered.Spec {
+		raw, err := util.RenderTemplate(crdConfig.Raw, values)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render the %s spec of the service %s", crdName, service.Name)
+		}
+		crdConfig.Raw = raw
+		rendered.Spec[crdName] = crdConfig
+	}
+	return rendered, nil
+}
 
-	// Convert CR template string to slice
-	err := json.Unmarshal([]byte(almExamples), &crTemplates)
-	if err != nil {
-		return errors.Wrapf(err, "failed to convert alm-examples in the Subscription %s/%s to slice", namespace, service.Name)
+// renderOperand returns a copy of the operand with the templates in its spec and the spec of its resources resolved
+func renderOperand(operand operatorv1alpha1.Operand, values util.TemplateValues) (operatorv1alpha1.Operand, error) {
+	rendered := *operand.DeepCopy()
+	if rendered.Spec != nil {
+		raw, err := util.RenderTemplate(rendered.Spec.Raw, values)
+		if err != nil {
+			return operand, errors.Wrapf(err, "failed to render the %s spec of the operand %s", operand.Kind, operand.Name)
+		}
+		rendered.Spec.Raw = raw
 	}
+	for i, resource := range rendered.Resources {
+		if resource.Spec == nil {
+			continue
+		}
+		raw, err := util.RenderTemplate(resource.Spec.Raw, values)
+		if err != nil {
+			return operand, errors.Wrapf(err, "failed to render the spec of the %s %s of the operand %s", resource.Kind, resource.Name, operand.Name)
+		}
+		rendered.Resources[i].Spec.Raw = raw
+	}
+	return rendered, nil
+}
 
+// reconcileCRwithConfig merge and create custom resource base on OperandConfig and the templates of the operator.
+// The custom resources are labelled with the OperandRegistry, to find the OperandRequests sharing them.
+func (r *Reconciler) reconcileCRwithConfig(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryKey types.NamespacedName, service *operatorv1alpha1.ConfigService, namespace string, templates []unstructured.Unstructured) error {
 	merr := &util.MultiErr{}
+	var patchErrs []string
+	var drifts []string
 
-	// Merge OperandConfig and ClusterServiceVersion alm-examples
-	for _, crTemplate := range crTemplates {
+	// Merge OperandConfig and the templates of the operator
+	for _, crTemplate := range templates {
 
 		// Create an unstruct object for CR and request its value to CR template
 		var unstruct unstructured.Unstructured
-		unstruct.Object = crTemplate.(map[string]interface{})
+		unstruct.Object = runtime.DeepCopyJSON(crTemplate.Object)
 
 		name := unstruct.Object["metadata"].(map[string]interface{})["name"].(string)
+		ensureLabel(unstruct, map[string]string{registryKey.Namespace + "." + registryKey.Name + "/registry": "true"})
 
+		// Watch the custom resources of the kind to detect their drift
+		r.watchCustomResource(unstruct.GroupVersionKind())
+
+		// Keep the template, only the fields rendered from it are applied
+		existing := unstructured.Unstructured{}
+		existing.SetGroupVersionKind(unstruct.GroupVersionKind())
 		err := r.Client.Get(ctx, types.NamespacedName{
 			Name:      name,
 			Namespace: namespace,
-		}, &unstruct)
+		}, &existing)
 
 		if err != nil && !apierrors.IsNotFound(err) {
 			merr.Add(errors.Wrapf(err, "failed to get the custom resource %s/%s", namespace, name))
 			continue
 		} else if apierrors.IsNotFound(err) {
+			// Skip creating the custom resource of the disabled service
+			if !service.IsEnabled() {
+				continue
+			}
 			// Create Custom resource
 			if err := r.compareConfigandExample(ctx, unstruct, service, namespace); err != nil {
+				if util.IsPatchError(err) {
+					patchErrs = append(patchErrs, err.Error())
+					continue
+				}
 				merr.Add(err)
 				continue
 			}
 		} else {
-			if checkLabel(unstruct, map[string]string{constant.OpreqLabel: "true"}) {
+			if checkLabel(existing, map[string]string{constant.OpreqLabel: "true"}) {
+				// Delete the custom resource of the disabled service
+				if !service.IsEnabled() {
+					klog.V(2).Infof("Service %s is disabled, deleting its custom resource %s/%s", service.Name, namespace, name)
+					if err := r.deleteCustomResource(ctx, unstruct, namespace); err != nil {
+						merr.Add(err)
+					}
+					continue
+				}
 				// Update or Delete Custom resource
-				if err := r.existingCustomResource(ctx, unstruct, service, namespace); err != nil {
+				drift, err := r.existingCustomResource(ctx, requestInstance, unstruct, existing, service, namespace)
+				if err != nil {
+					if util.IsPatchError(err) {
+						patchErrs = append(patchErrs, err.Error())
+						continue
+					}
 					merr.Add(err)
 					continue
 				}
+				if drift != "" {
+					drifts = append(drifts, drift)
+				}
 			} else {
 				klog.V(2).Info("Skip the custom resource not created by ODLM")
 			}
 		}
 	}
+	setPatchFailedCondition(requestInstance, service.Name, patchErrs)
+	setDriftedCondition(requestInstance, service.Name, drifts)
 	if len(merr.Errors) != 0 {
 		return merr
 	}
 	return nil
 }
 
-// reconcileCRwithRequest merge and create custom resource base on OperandRequest and CSV alm-examples
-func (r *Reconciler) reconcileCRwithRequest(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, operand operatorv1alpha1.Operand, requestKey types.NamespacedName, csv *olmv1alpha1.ClusterServiceVersion) error {
-	almExamples := csv.ObjectMeta.Annotations["alm-examples"]
-
-	// Create a slice for crTemplates
-	var crTemplates []interface{}
+// setPatchFailedCondition reports the JSON patches failed to apply in the member status
+func setPatchFailedCondition(requestInstance *operatorv1alpha1.OperandRequest, name string, patchErrs []string) {
+	if len(patchErrs) == 0 {
+		requestInstance.RemoveMemberCondition(name, operatorv1alpha1.ConditionPatchFailed)
+		return
+	}
+	klog.Warningf("Failed to apply the JSON patch of the operand %s in the OperandRequest %s/%s: %s", name, requestInstance.Namespace, requestInstance.Name, strings.Join(patchErrs, "; "))
+	requestInstance.SetPatchFailedCondition(name, strings.Join(patchErrs, "; "))
+}
 
-	// Convert CR template string to slice
-	err := json.Unmarshal([]byte(almExamples), &crTemplates)
-	if err != nil {
-		return errors.Wrapf(err, "failed to convert alm-examples in the Subscription %s/%s to slice", requestKey.Namespace, operand.Name)
+// setDriftedCondition reports the custom resources changed outside ODLM in the member status
+func setDriftedCondition(requestInstance *operatorv1alpha1.OperandRequest, name string, drifts []string) {
+	if len(drifts) == 0 {
+		requestInstance.RemoveMemberCondition(name, operatorv1alpha1.ConditionDrifted)
+		return
 	}
+	requestInstance.SetDriftedCondition(name, strings.Join(drifts, "; "))
+}
 
+// reconcileCRwithRequest merge and create the custom resources of the operand base on OperandRequest and the templates of the operator
+func (r *Reconciler) reconcileCRwithRequest(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, operand operatorv1alpha1.Operand, namespace string, templates []unstructured.Unstructured) error {
 	merr := &util.MultiErr{}
 
-	// Merge OperandConfig and ClusterServiceVersion alm-examples
-	var found bool
-	for _, crTemplate := range crTemplates {
+	// Merge OperandRequest and the templates of the operator
+	var patchErrs []string
+	var drifts []string
+	var forbidden []string
+	for _, resource := range requestInstance.GetOperandResources(operand) {
+		var found bool
+		for _, crTemplate := range templates {
+
+			// Create an unstruct object for CR and request its value to CR template
+			var unstruct unstructured.Unstructured
+			unstruct.Object = runtime.DeepCopyJSON(crTemplate.Object)
+			if unstruct.Object["kind"].(string) != resource.Kind {
+				continue
+			}
 
-		// Create an unstruct object for CR and request its value to CR template
-		var unstruct unstructured.Unstructured
-		unstruct.Object = crTemplate.(map[string]interface{})
-		if unstruct.Object["kind"].(string) != operand.Kind {
-			continue
-		}
+			found = true
+			name := resource.Name
+			var crConfig []byte
+			if resource.Spec != nil {
+				crConfig = resource.Spec.Raw
+			}
 
-		found = true
-		var name string
-		if operand.InstanceName == "" {
-			name = requestKey.Name
-		} else {
-			name = operand.InstanceName
-		}
+			unstruct.Object["metadata"].(map[string]interface{})["name"] = name
+			unstruct.Object["metadata"].(map[string]interface{})["namespace"] = namespace
 
-		unstruct.Object["metadata"].(map[string]interface{})["name"] = name
-		unstruct.Object["metadata"].(map[string]interface{})["namespace"] = requestKey.Namespace
+			// The custom resources in the namespace of the OperandRequest are garbage collected with it
+			if namespace == requestInstance.Namespace {
+				unstruct.SetOwnerReferences([]metav1.OwnerReference{newOwnerReference(requestInstance)})
+			}
 
-		err := r.Client.Get(ctx, types.NamespacedName{
-			Name:      name,
-			Namespace: requestKey.Namespace,
-		}, &unstruct)
+			// Watch the custom resources of the kind to detect their drift
+			r.watchCustomResource(unstruct.GroupVersionKind())
 
-		if err != nil && !apierrors.IsNotFound(err) {
-			merr.Add(errors.Wrapf(err, "failed to get custom resource %s/%s", requestKey.Namespace, name))
-			continue
-		} else if apierrors.IsNotFound(err) {
-			// Create Custom resource
-			if err := r.createCustomResource(ctx, unstruct, requestKey.Namespace, operand.Kind, operand.Spec.Raw); err != nil {
-				merr.Add(err)
+			// Keep the template, only the fields rendered from it are applied
+			existing := unstructured.Unstructured{}
+			existing.SetGroupVersionKind(unstruct.GroupVersionKind())
+			err := r.Client.Get(ctx, types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			}, &existing)
+
+			if err != nil && !apierrors.IsNotFound(err) {
+				merr.Add(errors.Wrapf(err, "failed to get custom resource %s/%s", namespace, name))
 				continue
-			}
-			requestInstance.SetMemberCRStatus(operand.Name, name, operand.Kind, unstruct.Object["apiVersion"].(string))
-		} else {
-			if checkLabel(unstruct, map[string]string{constant.OpreqLabel: "true"}) {
-				// Update or Delete Custom resource
-				klog.V(3).Info("Found OperandConfig spec for custom resource: " + operand.Kind)
-				if err := r.updateCustomResource(ctx, unstruct, requestKey.Namespace, operand.Kind, operand.Spec.Raw); err != nil {
-					return err
+			} else if apierrors.IsNotFound(err) {
+				// The requester must be allowed to create the custom resource in another namespace
+				if namespace != requestInstance.Namespace {
+					denied, err := r.checkRequesterAuth(ctx, requestInstance, namespace, unstruct.GroupVersionKind())
+					if err != nil {
+						merr.Add(err)
+						continue
+					}
+					if denied != "" {
+						forbidden = append(forbidden, denied)
+						continue
+					}
+				}
+				// Create Custom resource
+				if err := r.createCustomResource(ctx, unstruct, namespace, resource.Kind, crConfig, operand.MergeStrategy, operand.Patches); err != nil {
+					if util.IsPatchError(err) {
+						patchErrs = append(patchErrs, err.Error())
+						continue
+					}
+					merr.Add(err)
+					continue
 				}
+				requestInstance.SetMemberCRStatus(operand.Name, name, resource.Kind, unstruct.Object["apiVersion"].(string), namespace)
 			} else {
-				klog.V(2).Info("Skip the custom resource not created by ODLM")
+				if checkLabel(existing, map[string]string{constant.OpreqLabel: "true"}) {
+					// Update or Delete Custom resource
+					klog.V(3).Info("Found OperandConfig spec for custom resource: " + resource.Kind)
+					drift, err := r.updateCustomResource(ctx, requestInstance, unstruct, existing, namespace, resource.Kind, crConfig, operand.MergeStrategy, operand.Patches)
+					if err != nil {
+						if util.IsPatchError(err) {
+							patchErrs = append(patchErrs, err.Error())
+							continue
+						}
+						// Keep updating the other resources of the operand
+						merr.Add(err)
+						continue
+					}
+					if drift != "" {
+						drifts = append(drifts, drift)
+					}
+				} else {
+					klog.V(2).Info("Skip the custom resource not created by ODLM")
+				}
 			}
 		}
+		if !found {
+			klog.Warningf("not found CRD with Kind %s in the alm-example", resource.Kind)
+		}
 	}
-	if !found {
-		klog.Warningf("not found CRD with Kind %s in the alm-example", operand.Kind)
-	}
+	setPatchFailedCondition(requestInstance, operand.Name, patchErrs)
+	setDriftedCondition(requestInstance, operand.Name, drifts)
+	setForbiddenCondition(requestInstance, operand.Name, forbidden)
 	if len(merr.Errors) != 0 {
 		return merr
 	}
 	return nil
 }
 
-// deleteAllCustomResource remove custom resource base on OperandConfig and CSV alm-examples
-func (r *Reconciler) deleteAllCustomResource(ctx context.Context, csv *olmv1alpha1.ClusterServiceVersion, requestInstance *operatorv1alpha1.OperandRequest, csc *operatorv1alpha1.OperandConfig, operandName, namespace string) error {
+// checkRequesterAuth checks if the requester recorded in the OperandRequest is allowed to create the custom resources
+// of the kind in the namespace, it returns why the requester isn't allowed. The requester is recorded by the mutating
+// webhook, so the annotation can't be trusted, and the requester is unknown, when the webhooks are disabled.
+func (r *Reconciler) checkRequesterAuth(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, namespace string, gvk schema.GroupVersionKind) (string, error) {
+	var userInfo *authenticationv1.UserInfo
+	if util.IsWebhookEnabled() {
+		var err error
+		if userInfo, err = getRequester(requestInstance); err != nil {
+			return "", err
+		}
+	}
+	if userInfo == nil {
+		return fmt.Sprintf("the requester of the OperandRequest is unknown, so the permission to create %s in the namespace %s can't be checked. The namespace of the operand is only supported when the webhooks are enabled", gvk.Kind, namespace), nil
+	}
+	mapping, err := r.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the resource of %s", gvk.String())
+	}
+	allowed, err := checkCreateAuth(ctx, r.Client, *userInfo, namespace, mapping.Resource)
+	if err != nil {
+		return "", err
+	}
+	if !allowed {
+		return fmt.Sprintf("%s is not allowed to create %s in the namespace %s", userInfo.Username, mapping.Resource.GroupResource().String(), namespace), nil
+	}
+	return "", nil
+}
+
+// setForbiddenCondition reports the custom resources the requester isn't allowed to create in the member status
+func setForbiddenCondition(requestInstance *operatorv1alpha1.OperandRequest, name string, forbidden []string) {
+	if len(forbidden) == 0 {
+		requestInstance.RemoveMemberCondition(name, operatorv1alpha1.ConditionForbidden)
+		return
+	}
+	klog.Warningf("The custom resources of the operand %s in the OperandRequest %s/%s are not created: %s", name, requestInstance.Namespace, requestInstance.Name, strings.Join(forbidden, "; "))
+	requestInstance.SetForbiddenCondition(name, strings.Join(forbidden, "; "))
+}
+
+// newOwnerReference returns the ownerReference of the OperandRequest for its custom resources.
+// The OperandRequest isn't the controller of the custom resources, their operator is.
+func newOwnerReference(requestInstance *operatorv1alpha1.OperandRequest) metav1.OwnerReference {
+	blockOwnerDeletion := requestInstance.Spec.ForegroundDeletion
+	return metav1.OwnerReference{
+		APIVersion:         operatorv1alpha1.GroupVersion.String(),
+		Kind:               "OperandRequest",
+		Name:               requestInstance.Name,
+		UID:                requestInstance.UID,
+		BlockOwnerDeletion: &blockOwnerDeletion,
+	}
+}
+
+// deleteAllCustomResource remove custom resource base on OperandConfig and the templates of the operator
+func (r *Reconciler) deleteAllCustomResource(ctx context.Context, templates []unstructured.Unstructured, requestInstance *operatorv1alpha1.OperandRequest, csc *operatorv1alpha1.OperandConfig, operandName, namespace string) error {
 
 	customeResourceMap := make(map[string]operatorv1alpha1.OperandCRMember)
 	for _, member := range requestInstance.Status.Members {
 		if len(member.OperandCRList) != 0 {
 			for _, cr := range member.OperandCRList {
-				customeResourceMap[member.Name+"/"+cr.Kind+"/"+cr.Name] = cr
+				customeResourceMap[member.Name+"/"+cr.Kind+"/"+requestInstance.GetMemberCRNamespace(cr)+"/"+cr.Name] = cr
 			}
 		}
 	}
@@ -296,11 +606,12 @@ func (r *Reconciler) deleteAllCustomResource(ctx context.Context, csv *olmv1alph
 				},
 			},
 		}
-		if err := r.deleteCustomResource(ctx, crShouldBeDeleted, requestInstance.Namespace); err != nil {
+		crNamespace := requestInstance.GetMemberCRNamespace(opdMember)
+		if err := r.deleteCustomResource(ctx, crShouldBeDeleted, crNamespace); err != nil {
 			merr.Add(err)
 		}
 		operatorName := strings.Split(index, "/")[0]
-		requestInstance.RemoveMemberCRStatus(operatorName, opdMember.Name, opdMember.Kind)
+		requestInstance.RemoveMemberCRStatus(operatorName, opdMember.Name, opdMember.Kind, crNamespace)
 	}
 	if len(merr.Errors) != 0 {
 		return merr
@@ -310,24 +621,14 @@ func (r *Reconciler) deleteAllCustomResource(ctx context.Context, csv *olmv1alph
 	if service == nil {
 		return nil
 	}
-	almExamples := csv.ObjectMeta.Annotations["alm-examples"]
-	klog.V(2).Info("Delete all the custom resource from Subscription ", service.Name)
-
-	// Create a slice for crTemplates
-	var crTemplates []interface{}
-
-	// Convert CR template string to slice
-	err := json.Unmarshal([]byte(almExamples), &crTemplates)
-	if err != nil {
-		return errors.Wrapf(err, "failed to convert alm-examples in the Subscription %s to slice", service.Name)
-	}
+	klog.V(2).Info("Delete all the custom resource of the service ", service.Name)
 
-	// Merge OperandConfig and ClusterServiceVersion alm-examples
-	for _, crTemplate := range crTemplates {
+	// Merge OperandConfig and the templates of the operator
+	for _, crTemplate := range templates {
 
-		// Get CR from the alm-example
+		// Get CR from the template
 		var unstruct unstructured.Unstructured
-		unstruct.Object = crTemplate.(map[string]interface{})
+		unstruct.Object = crTemplate.Object
 		unstruct.Object["metadata"].(map[string]interface{})["namespace"] = namespace
 		name := unstruct.Object["metadata"].(map[string]interface{})["name"].(string)
 		// Get the kind of CR
@@ -374,7 +675,7 @@ func (r *Reconciler) compareConfigandExample(ctx context.Context, unstruct unstr
 		// Compare the name of OperandConfig and CRD name
 		if strings.EqualFold(kind, crdName) {
 			klog.V(3).Info("Found OperandConfig spec for custom resource: " + kind)
-			err := r.createCustomResource(ctx, unstruct, namespace, crdName, crdConfig.Raw)
+			err := r.createCustomResource(ctx, unstruct, namespace, crdName, crdConfig.Raw, getMergeStrategy(service, crdName), getPatch(service, crdName))
 			if err != nil {
 				return errors.Wrapf(err, "failed to create custom resource -- Kind: %s", kind)
 			}
@@ -383,23 +684,59 @@ func (r *Reconciler) compareConfigandExample(ctx context.Context, unstruct unstr
 	return nil
 }
 
-func (r *Reconciler) createCustomResource(ctx context.Context, unstruct unstructured.Unstructured, namespace, crName string, crConfig []byte) error {
+// getMergeStrategy returns the list merge strategies of the custom resource in the service,
+// with the paths relative to its spec
+func getMergeStrategy(service *operatorv1alpha1.ConfigService, crdName string) map[string]string {
+	if len(service.MergeStrategy) == 0 {
+		return nil
+	}
+	mergeStrategy := make(map[string]string)
+	for path, strategy := range service.MergeStrategy {
+		segments := strings.SplitN(path, ".", 2)
+		if len(segments) == 2 && strings.EqualFold(segments[0], crdName) {
+			mergeStrategy[segments[1]] = strategy
+		}
+	}
+	return mergeStrategy
+}
 
+// getPatch returns the JSON patch of the custom resource in the service
+func getPatch(service *operatorv1alpha1.ConfigService, crdName string) operatorv1alpha1.JSONPatch {
+	for name, patch := range service.Patches {
+		if strings.EqualFold(name, crdName) {
+			return patch
+		}
+	}
+	return nil
+}
+
+// mergeSpec merges the spec of the custom resource with the config by the list merge strategies,
+// then applies the JSON patch to the merged spec
+func mergeSpec(spec interface{}, crConfig []byte, mergeStrategy map[string]string, patch operatorv1alpha1.JSONPatch) (map[string]interface{}, error) {
 	//Convert CR template spec to string
-	specJSONString, _ := json.Marshal(unstruct.Object["spec"])
+	specJSONString, _ := json.Marshal(spec)
 
-	// Merge CR template spec and OperandConfig spec
-	mergedCR := util.MergeCR(specJSONString, crConfig)
+	mergedCR := util.MergeCRWithStrategy(specJSONString, crConfig, mergeStrategy)
+	if len(patch) == 0 {
+		return mergedCR, nil
+	}
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the JSON patch")
+	}
+	return util.ApplyJSONPatch(mergedCR, patchJSON)
+}
 
-	unstruct.Object["spec"] = mergedCR
-	unstruct.Object["metadata"].(map[string]interface{})["namespace"] = namespace
+func (r *Reconciler) createCustomResource(ctx context.Context, unstruct unstructured.Unstructured, namespace, crName string, crConfig []byte, mergeStrategy map[string]string, patch operatorv1alpha1.JSONPatch) error {
 
-	ensureLabel(unstruct, map[string]string{constant.OpreqLabel: "true"})
+	cr, err := r.renderCustomResource(ctx, unstruct, namespace, crConfig, mergeStrategy, patch)
+	if err != nil {
+		return err
+	}
 
-	// Creat the CR
-	crerr := r.Create(ctx, &unstruct)
-	if crerr != nil && !apierrors.IsAlreadyExists(crerr) {
-		return errors.Wrap(crerr, "failed to create custom resource")
+	// Create the CR
+	if err := r.applyCustomResource(ctx, cr); err != nil {
+		return errors.Wrap(err, "failed to create custom resource")
 	}
 
 	klog.V(2).Info("Finish creating the Custom Resource: ", crName)
@@ -407,105 +744,278 @@ func (r *Reconciler) createCustomResource(ctx context.Context, unstruct unstruct
 	return nil
 }
 
-func (r *Reconciler) existingCustomResource(ctx context.Context, unstruct unstructured.Unstructured, service *operatorv1alpha1.ConfigService, namespace string) error {
+func (r *Reconciler) existingCustomResource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, unstruct, existing unstructured.Unstructured, service *operatorv1alpha1.ConfigService, namespace string) (string, error) {
 	kind := unstruct.Object["kind"].(string)
 
 	var found bool
+	var drift string
 	for crName, crdConfig := range service.Spec {
 		// Compare the name of OperandConfig and CRD name
 		if strings.EqualFold(kind, crName) {
 			found = true
 			klog.V(3).Info("Found OperandConfig spec for custom resource: " + kind)
-			err := r.updateCustomResource(ctx, unstruct, namespace, crName, crdConfig.Raw)
+			d, err := r.updateCustomResource(ctx, requestInstance, unstruct, existing, namespace, crName, crdConfig.Raw, getMergeStrategy(service, crName), getPatch(service, crName))
 			if err != nil {
-				return errors.Wrap(err, "failed to update custom resource")
+				return "", errors.Wrap(err, "failed to update custom resource")
 			}
+			drift = d
 		}
 	}
 	if !found {
 		err := r.deleteCustomResource(ctx, unstruct, namespace)
 		if err != nil {
-			return err
+			return "", err
 		}
 	}
-	return nil
+	return drift, nil
 }
 
-func (r *Reconciler) updateCustomResource(ctx context.Context, unstruct unstructured.Unstructured, namespace, crName string, crConfig []byte) error {
+// updateCustomResource updates the existing custom resource with the rendered one by the drift policy of the OperandRequest.
+// It returns the description of the drift if the existing custom resource is changed outside ODLM.
+func (r *Reconciler) updateCustomResource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, unstruct, existing unstructured.Unstructured, namespace, crName string, crConfig []byte, mergeStrategy map[string]string, patch operatorv1alpha1.JSONPatch) (string, error) {
 
 	kind := unstruct.Object["kind"].(string)
 	apiversion := unstruct.Object["apiVersion"].(string)
 	name := unstruct.Object["metadata"].(map[string]interface{})["name"].(string)
 
+	cr, err := r.renderCustomResource(ctx, unstruct, namespace, crConfig, mergeStrategy, patch)
+	if err != nil {
+		return "", err
+	}
+
+	drift, shouldApply, err := r.checkDrift(requestInstance, &existing, cr)
+	if err != nil {
+		return "", err
+	}
+	if !shouldApply {
+		return drift, nil
+	}
+
 	// Update the CR
-	err := wait.PollImmediate(time.Millisecond*250, time.Second*5, func() (bool, error) {
+	klog.V(3).Infof("updating custom resource with apiversion: %s, kind: %s, %s/%s", apiversion, kind, namespace, name)
+	if err := r.applyCustomResource(ctx, cr); err != nil {
+		return "", errors.Wrapf(err, "failed to update custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
+	}
 
-		existingCR := unstructured.Unstructured{
-			Object: map[string]interface{}{
-				"apiVersion": apiversion,
-				"kind":       kind,
-			},
-		}
+	klog.V(3).Info("Finish updating the Custom Resource: ", crName)
 
-		err := r.Client.Get(ctx, types.NamespacedName{
-			Name:      name,
-			Namespace: namespace,
-		}, &existingCR)
+	return drift, nil
+}
 
-		if err != nil {
-			return false, errors.Wrapf(err, "failed to get custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
-		}
+// checkDrift compares the existing custom resource with the rendered one.
+// The existing custom resource is drifted if the rendered spec is unchanged since it was last applied,
+// but the fields rendered by ODLM are changed. Otherwise, the differences come from the changes of the config.
+// It returns the description of the drift, and if the rendered custom resource should be applied.
+func (r *Reconciler) checkDrift(requestInstance *operatorv1alpha1.OperandRequest, existing, cr *unstructured.Unstructured) (string, bool, error) {
+	policy := requestInstance.GetDriftPolicy()
+	configChanged := existing.GetAnnotations()[constant.OpreqSpecHashAnnotation] != cr.GetAnnotations()[constant.OpreqSpecHashAnnotation]
+	if configChanged || policy == operatorv1alpha1.DriftPolicyIgnore {
+		return "", configChanged || policy == operatorv1alpha1.DriftPolicyRevert, nil
+	}
 
-		if checkLabel(existingCR, map[string]string{constant.OpreqLabel: "true"}) {
+	existingSpec, _ := existing.Object["spec"].(map[string]interface{})
+	renderedSpec, _ := cr.Object["spec"].(map[string]interface{})
+	diffs, err := util.DiffSpec(renderedSpec, existingSpec)
+	if err != nil {
+		return "", false, errors.Wrapf(err, "failed to compare the custom resource %s %s/%s with the rendered spec", existing.GetKind(), existing.GetNamespace(), existing.GetName())
+	}
+	// Nothing to revert, the custom resource isn't applied again
+	if len(diffs) == 0 {
+		return "", false, nil
+	}
 
-			specJSONString, _ := json.Marshal(unstruct.Object["spec"])
+	drift := fmt.Sprintf("%s %s/%s drifted: %s", existing.GetKind(), existing.GetNamespace(), existing.GetName(), strings.Join(diffs, ", "))
+	klog.Warningf("The custom resource %s, drift policy of the OperandRequest %s/%s: %s", drift, requestInstance.Namespace, requestInstance.Name, policy)
+	if policy == operatorv1alpha1.DriftPolicyRevert {
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, "Drifted", "Reverting the custom resource %s", drift)
+		return drift, true, nil
+	}
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, "Drifted", "The custom resource %s", drift)
+	return drift, false, nil
+}
 
-			// Merge CR template spec and OperandConfig spec
-			mergedCR := util.MergeCR(specJSONString, crConfig)
+// renderCustomResource renders the custom resource from the alm-examples template and the config.
+// It only contains the fields rendered by ODLM, the other fields of the existing custom resource are kept when it is applied.
+func (r *Reconciler) renderCustomResource(ctx context.Context, unstruct unstructured.Unstructured, namespace string, crConfig []byte, mergeStrategy map[string]string, patch operatorv1alpha1.JSONPatch) (*unstructured.Unstructured, error) {
 
-			CRgeneration := existingCR.Object["metadata"].(map[string]interface{})["generation"]
+	// Merge CR template spec and OperandConfig spec
+	mergedCR, err := mergeSpec(unstruct.Object["spec"], crConfig, mergeStrategy, patch)
+	if err != nil {
+		return nil, err
+	}
 
-			if reflect.DeepEqual(existingCR.Object["spec"], mergedCR) {
-				return true, nil
-			}
+	cr := unstruct.DeepCopy()
+	cr.Object["spec"] = mergedCR
+	cr.SetNamespace(namespace)
+	delete(cr.Object, "status")
 
-			klog.V(2).Infof("updating custom resource with apiversion: %s, kind: %s, %s/%s", apiversion, kind, namespace, name)
+	ensureLabel(*cr, map[string]string{constant.OpreqLabel: "true"})
 
-			existingCR.Object["spec"] = mergedCR
-			err := r.Update(ctx, &existingCR)
+	// Record the hash of the rendered spec to tell the drift from the config changes,
+	// the ownerReferences and labels are included, so they are applied to the existing custom resources
+	rendered := map[string]interface{}{"spec": mergedCR, "labels": cr.GetLabels()}
+	if ownerReferences := cr.GetOwnerReferences(); len(ownerReferences) != 0 {
+		rendered["ownerReferences"] = ownerReferences
+	}
+	hash, err := util.HashSpec(rendered)
+	if err != nil {
+		return nil, err
+	}
+	annotations := cr.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[constant.OpreqSpecHashAnnotation] = hash
+	cr.SetAnnotations(annotations)
 
-			if err != nil {
-				return false, errors.Wrapf(err, "failed to update custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
-			}
+	return cr, nil
+}
 
-			UpdatedCR := unstructured.Unstructured{
-				Object: map[string]interface{}{
-					"apiVersion": apiversion,
-					"kind":       kind,
-				},
-			}
+// applyCustomResource creates or updates the custom resource with server-side apply.
+// ODLM owns the fields it renders, and forces the ownership of the fields changed by the other managers.
+func (r *Reconciler) applyCustomResource(ctx context.Context, cr *unstructured.Unstructured) error {
+	return r.Patch(ctx, cr, client.Apply, client.FieldOwner(constant.FieldManager), client.ForceOwnership)
+}
 
-			err = r.Client.Get(ctx, types.NamespacedName{
-				Name:      name,
-				Namespace: namespace,
-			}, &UpdatedCR)
+// resolveConfigService returns a copy of the service with the valueFrom references in its spec resolved
+// with the Secrets and ConfigMaps in the namespace of the OperandConfig
+func (r *Reconciler) resolveConfigService(ctx context.Context, service *operatorv1alpha1.ConfigService, namespace string, references gset.Set) (*operatorv1alpha1.ConfigService, error) {
+	resolved := service.DeepCopy()
+	for crdName, crdConfig := range resolved.Spec {
+		raw, err := r.resolveValueFrom(ctx, crdConfig.Raw, namespace, references)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve the %s spec of the service %s", crdName, service.Name)
+		}
+		crdConfig.Raw = raw
+		resolved.Spec[crdName] = crdConfig
+	}
+	return resolved, nil
+}
 
-			if err != nil {
-				return false, errors.Wrapf(err, "failed to get custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
+// resolveOperand returns a copy of the operand with the valueFrom references in the spec of its resources
+// resolved with the Secrets and ConfigMaps in the namespace of the OperandRequest
+func (r *Reconciler) resolveOperand(ctx context.Context, operand operatorv1alpha1.Operand, namespace string, references gset.Set) (operatorv1alpha1.Operand, error) {
+	resolved := *operand.DeepCopy()
+	if resolved.Spec != nil {
+		raw, err := r.resolveValueFrom(ctx, resolved.Spec.Raw, namespace, references)
+		if err != nil {
+			return operand, errors.Wrapf(err, "failed to resolve the %s spec of the operand %s", operand.Kind, operand.Name)
+		}
+		resolved.Spec.Raw = raw
+	}
+	for i, resource := range resolved.Resources {
+		if resource.Spec == nil {
+			continue
+		}
+		raw, err := r.resolveValueFrom(ctx, resource.Spec.Raw, namespace, references)
+		if err != nil {
+			return operand, errors.Wrapf(err, "failed to resolve the spec of the %s %s of the operand %s", resource.Kind, resource.Name, operand.Name)
+		}
+		resolved.Resources[i].Spec.Raw = raw
+	}
+	return resolved, nil
+}
 
+// resolveValueFrom resolves the valueFrom references in the spec with the Secrets and ConfigMaps in the namespace.
+// Only the namespace of the OperandConfig or OperandRequest with the spec is used, so a requester can't read
+// the Secrets of the other namespaces, including the target namespace of its custom resources.
+// The referenced objects are added to the references, even if they are not found.
+func (r *Reconciler) resolveValueFrom(ctx context.Context, crConfig []byte, namespace string, references gset.Set) ([]byte, error) {
+	resolved, err := util.ResolveValueFrom(crConfig, func(source *util.ValueSource) (string, bool, error) {
+		if ref := source.SecretKeyRef; ref != nil {
+			references.Add(referenceKey("Secret", namespace, ref.Name))
+			secret := &corev1.Secret{}
+			found, err := r.getReferencedObject(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret, "Secret", ref.Optional)
+			if err != nil || !found {
+				return "", false, err
 			}
-
-			if UpdatedCR.Object["metadata"].(map[string]interface{})["generation"] != CRgeneration {
-				klog.V(2).Info("Finish updating the Custom Resource: ", crName)
+			if value, ok := secret.Data[ref.Key]; ok {
+				return string(value), true, nil
+			}
+			if ref.Optional != nil && *ref.Optional {
+				return "", false, nil
 			}
+			return "", false, fmt.Errorf("not found the key %s in the Secret %s/%s", ref.Key, namespace, ref.Name)
 		}
-		return true, nil
+		ref := source.ConfigMapKeyRef
+		references.Add(referenceKey("ConfigMap", namespace, ref.Name))
+		cm := &corev1.ConfigMap{}
+		found, err := r.getReferencedObject(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, cm, "ConfigMap", ref.Optional)
+		if err != nil || !found {
+			return "", false, err
+		}
+		if value, ok := cm.Data[ref.Key]; ok {
+			return value, true, nil
+		}
+		if ref.Optional != nil && *ref.Optional {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("not found the key %s in the ConfigMap %s/%s", ref.Key, namespace, ref.Name)
 	})
-
 	if err != nil {
-		return errors.Wrapf(err, "failed to update custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
+		return nil, errors.Wrapf(err, "failed to resolve the valueFrom references in the namespace %s", namespace)
+	}
+	return resolved, nil
+}
+
+// getReferencedObject gets the Secret or ConfigMap referenced by a valueFrom
+func (r *Reconciler) getReferencedObject(ctx context.Context, key types.NamespacedName, obj runtime.Object, kind string, optional *bool) (bool, error) {
+	// The Secrets and ConfigMaps in the cache are filtered by label, so the object is read from the API server
+	if err := r.Reader.Get(ctx, key, obj); err != nil {
+		if apierrors.IsNotFound(err) && optional != nil && *optional {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get the %s %s", kind, key.String())
 	}
+	return true, nil
+}
+
+// referenceKey is the key of a Secret or ConfigMap in the references annotation of the OperandRequest
+func referenceKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
 
+// updateReferences records the Secrets and ConfigMaps referenced by the specs of the OperandRequest in its annotation,
+// the OperandRequest is indexed by them to be reconciled when they change, and their namespaces are watched
+func (r *Reconciler) updateReferences(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, references gset.Set) error {
+	var keys []string
+	for reference := range references.Iter() {
+		key := fmt.Sprintf("%v", reference)
+		keys = append(keys, key)
+		if r.references != nil {
+			// The key is Kind/namespace/name
+			r.references.watchNamespace(strings.Split(key, "/")[1])
+		}
+	}
+	sort.Strings(keys)
+	value := strings.Join(keys, ",")
+	if requestInstance.GetAnnotations()[constant.OpreqReferencesAnnotation] == value {
+		return nil
+	}
+	var annotation interface{}
+	if value != "" {
+		annotation = value
+	}
+	mergePatch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				constant.OpreqReferencesAnnotation: annotation,
+			},
+		},
+	})
+	// Patch a copy, so the status changed in this reconciliation isn't overwritten
+	if err := r.Patch(ctx, requestInstance.DeepCopy(), client.RawPatch(types.MergePatchType, mergePatch)); err != nil {
+		return errors.Wrapf(err, "failed to update the annotation %s of the OperandRequest %s/%s", constant.OpreqReferencesAnnotation, requestInstance.Namespace, requestInstance.Name)
+	}
+	annotations := requestInstance.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[constant.OpreqReferencesAnnotation] = value
+	if value == "" {
+		delete(annotations, constant.OpreqReferencesAnnotation)
+	}
+	requestInstance.SetAnnotations(annotations)
 	return nil
 }
 
@@ -573,20 +1083,15 @@ func (r *Reconciler) checkCustomResource(ctx context.Context, requestInstance *o
 	for _, member := range members {
 		if len(member.OperandCRList) != 0 {
 			for _, cr := range member.OperandCRList {
-				customeResourceMap[member.Name+"/"+cr.Kind+"/"+cr.Name] = cr
+				customeResourceMap[member.Name+"/"+cr.Kind+"/"+requestInstance.GetMemberCRNamespace(cr)+"/"+cr.Name] = cr
 			}
 		}
 	}
 	for _, req := range requestInstance.Spec.Requests {
 		for _, opd := range req.Operands {
-			if opd.Kind != "" {
-				var name string
-				if opd.InstanceName == "" {
-					name = requestInstance.Name
-				} else {
-					name = opd.InstanceName
-				}
-				delete(customeResourceMap, opd.Name+"/"+opd.Kind+"/"+name)
+			// Only the custom resources removed from the operand are deleted
+			for _, resource := range requestInstance.GetOperandResources(opd) {
+				delete(customeResourceMap, opd.Name+"/"+resource.Kind+"/"+requestInstance.GetOperandNamespace(opd)+"/"+resource.Name)
 			}
 		}
 	}
@@ -602,11 +1107,12 @@ func (r *Reconciler) checkCustomResource(ctx context.Context, requestInstance *o
 				},
 			},
 		}
-		if err := r.deleteCustomResource(ctx, crShouldBeDeleted, requestInstance.Namespace); err != nil {
+		crNamespace := requestInstance.GetMemberCRNamespace(opdMember)
+		if err := r.deleteCustomResource(ctx, crShouldBeDeleted, crNamespace); err != nil {
 			merr.Add(err)
 		}
 		operatorName := strings.Split(index, "/")[0]
-		requestInstance.RemoveMemberCRStatus(operatorName, opdMember.Name, opdMember.Kind)
+		requestInstance.RemoveMemberCRStatus(operatorName, opdMember.Name, opdMember.Kind, crNamespace)
 	}
 
 	if len(merr.Errors) != 0 {
@@ -0,0 +1,613 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	gset "github.com/deckarep/golang-set"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+)
+
+var _ = Describe("Rendering the templated specs", func() {
+
+	values := util.TemplateValues{
+		RequestNamespace:  "cloudpak",
+		RegistryNamespace: "ibm-common-services",
+		OperatorNamespace: "ibm-operators",
+		Parameters:        map[string]string{"domain": "apps.example.com"},
+	}
+
+	It("Should render the service spec without changing the OperandConfig", func() {
+		service := &operatorv1alpha1.ConfigService{
+			Name: "jenkins",
+			Spec: map[string]runtime.RawExtension{
+				"jenkins": {Raw: []byte(`{"host":"jenkins.{{ .Parameters.domain }}","namespace":"{{ .OperatorNamespace }}"}`)},
+			},
+		}
+		rendered, err := renderConfigService(service, values)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(rendered.Spec["jenkins"].Raw)).Should(Equal(`{"host":"jenkins.apps.example.com","namespace":"ibm-operators"}`))
+		Expect(string(service.Spec["jenkins"].Raw)).Should(ContainSubstring("{{ .Parameters.domain }}"))
+	})
+
+	It("Should render the operand spec", func() {
+		operand := operatorv1alpha1.Operand{
+			Name: "etcd",
+			Kind: "EtcdCluster",
+			Spec: &runtime.RawExtension{Raw: []byte(`{"namespace":"{{ .RequestNamespace }}"}`)},
+		}
+		rendered, err := renderOperand(operand, values)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(rendered.Spec.Raw)).Should(Equal(`{"namespace":"cloudpak"}`))
+	})
+
+	It("Should report the unresolved variable", func() {
+		operand := operatorv1alpha1.Operand{
+			Name: "etcd",
+			Kind: "EtcdCluster",
+			Spec: &runtime.RawExtension{Raw: []byte(`{"storageClass":"{{ .Parameters.storageClass }}"}`)},
+		}
+		_, err := renderOperand(operand, values)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("failed to render the EtcdCluster spec of the operand etcd"))
+	})
+})
+
+var _ = Describe("Resolving the valueFrom references", func() {
+
+	const namespace = "ibm-common-services"
+
+	var (
+		ctx context.Context
+		r   *Reconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-credentials", Namespace: namespace},
+			Data:       map[string][]byte{"password": []byte("passw0rd")},
+		}
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: namespace, Labels: map[string]string{"app": "ca"}},
+			Data:       map[string]string{"ca.crt": "-----BEGIN CERTIFICATE-----"},
+		}
+		c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, secret, cm)
+		r = &Reconciler{ODLMOperator: &deploy.ODLMOperator{Client: c, Reader: c}}
+	})
+
+	It("Should resolve the references without labelling the referenced objects", func() {
+		references := gset.NewSet()
+		spec := `{"password":{"valueFrom":{"secretKeyRef":{"name":"db-credentials","key":"password"}}},"ca":{"valueFrom":{"configMapKeyRef":{"name":"ca","key":"ca.crt"}}}}`
+		resolved, err := r.resolveValueFrom(ctx, []byte(spec), namespace, references)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(resolved)).Should(Equal(`{"ca":"-----BEGIN CERTIFICATE-----","password":"passw0rd"}`))
+		Expect(references.Contains("Secret/ibm-common-services/db-credentials", "ConfigMap/ibm-common-services/ca")).Should(BeTrue())
+
+		secret := &corev1.Secret{}
+		Expect(r.Client.Get(ctx, types.NamespacedName{Name: "db-credentials", Namespace: namespace}, secret)).Should(Succeed())
+		Expect(secret.Labels).Should(BeEmpty())
+		cm := &corev1.ConfigMap{}
+		Expect(r.Client.Get(ctx, types.NamespacedName{Name: "ca", Namespace: namespace}, cm)).Should(Succeed())
+		Expect(cm.Labels).Should(Equal(map[string]string{"app": "ca"}))
+	})
+
+	It("Should skip the optional reference which is not found", func() {
+		references := gset.NewSet()
+		spec := `{"user":"admin","token":{"valueFrom":{"secretKeyRef":{"name":"db-token","key":"token","optional":true}}}}`
+		resolved, err := r.resolveValueFrom(ctx, []byte(spec), namespace, references)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(resolved)).Should(Equal(`{"user":"admin"}`))
+		// The optional reference is still watched, so the value is applied when it is created
+		Expect(references.Contains("Secret/ibm-common-services/db-token")).Should(BeTrue())
+	})
+
+	It("Should report the key which is not found", func() {
+		spec := `{"token":{"valueFrom":{"secretKeyRef":{"name":"db-credentials","key":"token"}}}}`
+		_, err := r.resolveValueFrom(ctx, []byte(spec), namespace, gset.NewSet())
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("not found the key token in the Secret ibm-common-services/db-credentials"))
+	})
+
+	It("Should only resolve the references of the operand in the namespace of the OperandRequest", func() {
+		target := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-credentials", Namespace: "target"},
+			Data:       map[string][]byte{"password": []byte("target")},
+		}
+		Expect(r.Client.Create(ctx, target)).Should(Succeed())
+		operand := operatorv1alpha1.Operand{
+			Name:      "jenkins",
+			Namespace: "target",
+			Resources: []operatorv1alpha1.OperandResource{
+				{Name: "example", Kind: "Jenkins", Spec: &runtime.RawExtension{Raw: []byte(`{"password":{"valueFrom":{"secretKeyRef":{"name":"db-credentials","key":"password"}}}}`)}},
+			},
+		}
+		references := gset.NewSet()
+		resolved, err := r.resolveOperand(ctx, operand, namespace, references)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(resolved.Resources[0].Spec.Raw)).Should(Equal(`{"password":"passw0rd"}`))
+		Expect(references.ToSlice()).Should(ConsistOf("Secret/ibm-common-services/db-credentials"))
+	})
+
+	It("Should record the references in the annotation of the OperandRequest", func() {
+		request := testutil.OperandRequestObj("common-service", namespace, "cloudpak", namespace)
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(operatorv1alpha1.AddToScheme(scheme)).To(Succeed())
+		r.Client = fake.NewFakeClientWithScheme(scheme, request)
+
+		Expect(r.updateReferences(ctx, request, gset.NewSet("Secret/ibm-common-services/db-credentials", "ConfigMap/ibm-common-services/ca"))).Should(Succeed())
+		Expect(request.Annotations).Should(HaveKeyWithValue(constant.OpreqReferencesAnnotation, "ConfigMap/ibm-common-services/ca,Secret/ibm-common-services/db-credentials"))
+		Expect(indexReferences(request)).Should(Equal([]string{"ConfigMap/ibm-common-services/ca", "Secret/ibm-common-services/db-credentials"}))
+		stored := &operatorv1alpha1.OperandRequest{}
+		Expect(r.Client.Get(ctx, types.NamespacedName{Name: "cloudpak", Namespace: namespace}, stored)).Should(Succeed())
+		Expect(stored.Annotations).Should(HaveKeyWithValue(constant.OpreqReferencesAnnotation, "ConfigMap/ibm-common-services/ca,Secret/ibm-common-services/db-credentials"))
+
+		Expect(r.updateReferences(ctx, request, gset.NewSet())).Should(Succeed())
+		Expect(request.Annotations).ShouldNot(HaveKey(constant.OpreqReferencesAnnotation))
+		stored = &operatorv1alpha1.OperandRequest{}
+		Expect(r.Client.Get(ctx, types.NamespacedName{Name: "cloudpak", Namespace: namespace}, stored)).Should(Succeed())
+		Expect(stored.Annotations).ShouldNot(HaveKey(constant.OpreqReferencesAnnotation))
+	})
+})
+
+var _ = Describe("Getting the merge strategy of a custom resource", func() {
+
+	It("Should only return the paths of the custom resource", func() {
+		service := &operatorv1alpha1.ConfigService{
+			Name: "jenkins",
+			MergeStrategy: map[string]string{
+				"jenkins.containers":          "merge:name",
+				"Jenkins.ingress.hosts":       "append",
+				"jenkinsAgent.containers":     "append",
+				"jenkinsagent.spec.resources": "append",
+			},
+		}
+		Expect(getMergeStrategy(service, "jenkins")).Should(Equal(map[string]string{"containers": "merge:name", "ingress.hosts": "append"}))
+	})
+})
+
+var _ = Describe("Applying the JSON patch to the custom resource", func() {
+
+	const namespace = "ibm-common-services"
+
+	var (
+		ctx      context.Context
+		r        *Reconciler
+		template unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme)
+		r = &Reconciler{ODLMOperator: &deploy.ODLMOperator{Client: c, Reader: c}}
+		template = unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "jenkins.io/v1alpha2",
+			"kind":       "Jenkins",
+			"metadata":   map[string]interface{}{"name": "example"},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "jenkins"}},
+			},
+		}}
+	})
+
+	It("Should render the custom resource with the patched spec", func() {
+		patch := operatorv1alpha1.JSONPatch{
+			{Op: "add", Path: "/containers/0", Value: &apiextensionsv1.JSON{Raw: []byte(`{"name":"init"}`)}},
+		}
+		cr, err := r.renderCustomResource(ctx, template, namespace, []byte(`{"port":8081}`), nil, patch)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cr.GetNamespace()).Should(Equal(namespace))
+		Expect(cr.GetLabels()).Should(HaveKeyWithValue(constant.OpreqLabel, "true"))
+		Expect(cr.Object["spec"]).Should(Equal(map[string]interface{}{
+			"containers": []interface{}{map[string]interface{}{"name": "init"}, map[string]interface{}{"name": "jenkins"}},
+			"port":       float64(8081),
+		}))
+	})
+
+	It("Should only render the fields from the template and the config", func() {
+		template.Object["status"] = map[string]interface{}{"phase": "Running"}
+		cr, err := r.renderCustomResource(ctx, template, namespace, nil, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cr.Object).ShouldNot(HaveKey("status"))
+		Expect(template.Object).Should(HaveKey("status"))
+		Expect(template.GetNamespace()).Should(BeEmpty())
+	})
+
+	It("Should not create the custom resource if the patch fails", func() {
+		patch := operatorv1alpha1.JSONPatch{
+			{Op: "remove", Path: "/ingress"},
+		}
+		err := r.createCustomResource(ctx, template, namespace, "jenkins", nil, nil, patch)
+		Expect(util.IsPatchError(err)).Should(BeTrue())
+
+		cr := &unstructured.Unstructured{}
+		cr.SetAPIVersion("jenkins.io/v1alpha2")
+		cr.SetKind("Jenkins")
+		err = r.Client.Get(ctx, types.NamespacedName{Name: "example", Namespace: namespace}, cr)
+		Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+	})
+
+	It("Should report the failed patch in the member status", func() {
+		request := &operatorv1alpha1.OperandRequest{}
+		setPatchFailedCondition(request, "jenkins", []string{"failed to apply the JSON patch: remove /ingress"})
+		Expect(request.HasMemberCondition("jenkins", operatorv1alpha1.ConditionPatchFailed)).Should(BeTrue())
+		setPatchFailedCondition(request, "jenkins", nil)
+		Expect(request.HasMemberCondition("jenkins", operatorv1alpha1.ConditionPatchFailed)).Should(BeFalse())
+	})
+})
+
+var _ = Describe("Creating the custom resources in another namespace", func() {
+
+	const (
+		namespace       = "ibm-cloudpak"
+		targetNamespace = "jenkins-instances"
+	)
+
+	var (
+		ctx       context.Context
+		r         *Reconciler
+		request   *operatorv1alpha1.OperandRequest
+		operand   operatorv1alpha1.Operand
+		templates []unstructured.Unstructured
+	)
+
+	getJenkins := func() error {
+		cr := &unstructured.Unstructured{}
+		cr.SetAPIVersion("jenkins.io/v1alpha2")
+		cr.SetKind("Jenkins")
+		return r.Client.Get(ctx, types.NamespacedName{Name: "cloudpak", Namespace: targetNamespace}, cr)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme)
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(schema.GroupVersionKind{Group: "jenkins.io", Version: "v1alpha2", Kind: "Jenkins"}, meta.RESTScopeNamespace)
+		r = &Reconciler{ODLMOperator: &deploy.ODLMOperator{
+			Client:     &accessReviewClient{Client: c, allowedNamespaces: []string{targetNamespace}},
+			Reader:     c,
+			RESTMapper: mapper,
+		}}
+		request = testutil.OperandRequestObj("common-service", "ibm-common-services", "cloudpak", namespace)
+		operand = operatorv1alpha1.Operand{Name: "jenkins", Kind: "Jenkins", Namespace: targetNamespace}
+		templates = []unstructured.Unstructured{{Object: map[string]interface{}{
+			"apiVersion": "jenkins.io/v1alpha2",
+			"kind":       "Jenkins",
+			"metadata":   map[string]interface{}{"name": "example"},
+			"spec":       map[string]interface{}{"replicas": int64(1)},
+		}}}
+	})
+
+	It("Should allow the requester who can create the kind in the namespace", func() {
+		request.Annotations = map[string]string{constant.OpreqRequesterAnnotation: `{"username":"tenant"}`}
+		denied, err := r.checkRequesterAuth(ctx, request, targetNamespace, templates[0].GroupVersionKind())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(denied).Should(BeEmpty())
+	})
+
+	It("Should report the requester who isn't allowed in the member status", func() {
+		request.Annotations = map[string]string{constant.OpreqRequesterAnnotation: `{"username":"tenant"}`}
+		r.Client.(*accessReviewClient).allowedNamespaces = nil
+		Expect(r.reconcileCRwithRequest(ctx, request, operand, targetNamespace, templates)).Should(Succeed())
+		Expect(request.HasMemberCondition("jenkins", operatorv1alpha1.ConditionForbidden)).Should(BeTrue())
+		Expect(request.Status.Members[0].Conditions[0].Message).Should(ContainSubstring("tenant is not allowed to create jenkinses.jenkins.io in the namespace jenkins-instances"))
+		Expect(apierrors.IsNotFound(getJenkins())).Should(BeTrue())
+	})
+
+	It("Should not create the custom resource when the requester is unknown", func() {
+		Expect(r.reconcileCRwithRequest(ctx, request, operand, targetNamespace, templates)).Should(Succeed())
+		Expect(request.HasMemberCondition("jenkins", operatorv1alpha1.ConditionForbidden)).Should(BeTrue())
+		Expect(request.Status.Members[0].Conditions[0].Message).Should(ContainSubstring("the requester of the OperandRequest is unknown"))
+		Expect(apierrors.IsNotFound(getJenkins())).Should(BeTrue())
+	})
+})
+
+var _ = Describe("Detecting the drift of the custom resource", func() {
+
+	const namespace = "ibm-common-services"
+
+	var (
+		r        *Reconciler
+		recorder *record.FakeRecorder
+		request  *operatorv1alpha1.OperandRequest
+		cr       *unstructured.Unstructured
+		existing *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme)
+		recorder = record.NewFakeRecorder(10)
+		r = &Reconciler{ODLMOperator: &deploy.ODLMOperator{Client: c, Reader: c, Recorder: recorder}}
+		request = &operatorv1alpha1.OperandRequest{}
+		template := unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "jenkins.io/v1alpha2",
+			"kind":       "Jenkins",
+			"metadata":   map[string]interface{}{"name": "example"},
+			"spec":       map[string]interface{}{"replicas": int64(1)},
+		}}
+		var err error
+		cr, err = r.renderCustomResource(context.Background(), template, namespace, []byte(`{"port":8081}`), nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		existing = cr.DeepCopy()
+		existing.Object["spec"] = map[string]interface{}{"replicas": int64(1), "port": int64(8081), "image": "jenkins:2.0"}
+	})
+
+	It("Should neither report nor apply the fields set by the other managers", func() {
+		drift, shouldApply, err := r.checkDrift(request, existing, cr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift).Should(BeEmpty())
+		Expect(shouldApply).Should(BeFalse())
+		Expect(recorder.Events).Should(BeEmpty())
+	})
+
+	It("Should report and revert the drift by default", func() {
+		existing.Object["spec"].(map[string]interface{})["port"] = int64(9090)
+		drift, shouldApply, err := r.checkDrift(request, existing, cr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift).Should(ContainSubstring("spec.port: changed"))
+		Expect(shouldApply).Should(BeTrue())
+		Expect(recorder.Events).Should(Receive(ContainSubstring("Reverting the custom resource Jenkins ibm-common-services/example drifted")))
+	})
+
+	It("Should only report the drift with the report policy", func() {
+		request.Spec.DriftPolicy = operatorv1alpha1.DriftPolicyReport
+		delete(existing.Object["spec"].(map[string]interface{}), "port")
+		drift, shouldApply, err := r.checkDrift(request, existing, cr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift).Should(ContainSubstring("spec.port: removed"))
+		Expect(shouldApply).Should(BeFalse())
+		Expect(recorder.Events).Should(Receive(ContainSubstring("Drifted")))
+
+		setDriftedCondition(request, "jenkins", []string{drift})
+		Expect(request.HasMemberCondition("jenkins", operatorv1alpha1.ConditionDrifted)).Should(BeTrue())
+		setDriftedCondition(request, "jenkins", nil)
+		Expect(request.HasMemberCondition("jenkins", operatorv1alpha1.ConditionDrifted)).Should(BeFalse())
+	})
+
+	It("Should neither report nor revert the drift with the ignore policy", func() {
+		request.Spec.DriftPolicy = operatorv1alpha1.DriftPolicyIgnore
+		existing.Object["spec"].(map[string]interface{})["port"] = int64(9090)
+		drift, shouldApply, err := r.checkDrift(request, existing, cr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift).Should(BeEmpty())
+		Expect(shouldApply).Should(BeFalse())
+		Expect(recorder.Events).Should(BeEmpty())
+	})
+
+	It("Should apply the changes of the config with any policy", func() {
+		request.Spec.DriftPolicy = operatorv1alpha1.DriftPolicyReport
+		existing.SetAnnotations(map[string]string{constant.OpreqSpecHashAnnotation: "outdated"})
+		existing.Object["spec"].(map[string]interface{})["port"] = int64(8080)
+		drift, shouldApply, err := r.checkDrift(request, existing, cr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift).Should(BeEmpty())
+		Expect(shouldApply).Should(BeTrue())
+		Expect(recorder.Events).Should(BeEmpty())
+	})
+})
+
+var _ = Describe("Mapping the custom resources to the OperandRequests", func() {
+
+	var (
+		request *operatorv1alpha1.OperandRequest
+		gvk     schema.GroupVersionKind
+		cr      *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		request = testutil.OperandRequestObj("common-service", "ibm-common-services", "cloudpak", "cloudpak")
+		gvk = schema.GroupVersionKind{Group: "jenkins.io", Version: "v1alpha2", Kind: "Jenkins"}
+		cr = &unstructured.Unstructured{}
+		cr.SetGroupVersionKind(gvk)
+		cr.SetName("example")
+		cr.SetNamespace("ibm-common-services")
+	})
+
+	It("Should map the custom resource created from the OperandConfig by the label of the OperandRegistry", func() {
+		Expect(ownsCustomResource(request, gvk, cr)).Should(BeFalse())
+		cr.SetLabels(map[string]string{"ibm-common-services.common-service/registry": "true"})
+		Expect(ownsCustomResource(request, gvk, cr)).Should(BeTrue())
+	})
+
+	It("Should map the custom resource created from the OperandRequest by its status", func() {
+		request.Labels = nil
+		request.SetMemberStatus("jenkins", operatorv1alpha1.OperatorRunning, operatorv1alpha1.ServiceRunning)
+		request.SetMemberCRStatus("jenkins", "example", "Jenkins", "jenkins.io/v1alpha2", "ibm-common-services")
+		Expect(ownsCustomResource(request, gvk, cr)).Should(BeTrue())
+
+		cr.SetNamespace("cloudpak")
+		Expect(ownsCustomResource(request, gvk, cr)).Should(BeFalse())
+	})
+})
+
+var _ = Describe("Checking the health of the operand", func() {
+
+	newCR := func(name, phase string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "jenkins.io/v1alpha2",
+			"kind":       "Jenkins",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "ibm-common-services"},
+			"status":     map[string]interface{}{"phase": phase},
+		}}
+	}
+
+	readiness := &operatorv1alpha1.ReadinessExpression{JSONPath: "{.status.phase}", ReadyValues: []string{"Running"}, FailedValues: []string{"Failed"}}
+
+	It("Should be running when all the custom resources are ready", func() {
+		request := &operatorv1alpha1.OperandRequest{}
+		checkOperandHealth(request, "jenkins", []unstructured.Unstructured{newCR("a", "Running"), newCR("b", "Running")}, readiness)
+		Expect(request.Status.Members[0].Phase.OperandPhase).Should(Equal(operatorv1alpha1.ServiceRunning))
+		Expect(request.HasMemberCondition("jenkins", operatorv1alpha1.ConditionOperandNotReady)).Should(BeFalse())
+	})
+
+	It("Should report the custom resources which are not ready", func() {
+		request := &operatorv1alpha1.OperandRequest{}
+		checkOperandHealth(request, "jenkins", []unstructured.Unstructured{newCR("a", "Failed"), newCR("b", "Installing")}, readiness)
+		Expect(request.Status.Members[0].Phase.OperandPhase).Should(Equal(operatorv1alpha1.ServiceFailed))
+		c := request.Status.Members[0].Conditions[0]
+		Expect(c.Type).Should(Equal(operatorv1alpha1.ConditionOperandNotReady))
+		Expect(c.Reason).Should(Equal("OperandFailed"))
+		Expect(c.Message).Should(ContainSubstring("Jenkins ibm-common-services/b is Creating"))
+
+		checkOperandHealth(request, "jenkins", []unstructured.Unstructured{newCR("a", "Running"), newCR("b", "Installing")}, readiness)
+		Expect(request.Status.Members[0].Phase.OperandPhase).Should(Equal(operatorv1alpha1.ServiceCreating))
+		checkOperandHealth(request, "jenkins", []unstructured.Unstructured{newCR("a", "Running"), newCR("b", "Running")}, readiness)
+		Expect(request.HasMemberCondition("jenkins", operatorv1alpha1.ConditionOperandNotReady)).Should(BeFalse())
+	})
+})
+
+var _ = Describe("Setting the OperandRequest as the owner of the custom resources", func() {
+
+	var (
+		r        *Reconciler
+		request  *operatorv1alpha1.OperandRequest
+		template unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme)
+		r = &Reconciler{ODLMOperator: &deploy.ODLMOperator{Client: c, Reader: c}}
+		request = &operatorv1alpha1.OperandRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloudpak", Namespace: "cloudpak", UID: "5a0b4a6e-6a3c-4a3b-9d4b-8c1f1d0a5e2f"},
+		}
+		template = unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "jenkins.io/v1alpha2",
+			"kind":       "Jenkins",
+			"metadata":   map[string]interface{}{"name": "cloudpak"},
+			"spec":       map[string]interface{}{"replicas": int64(1)},
+		}}
+	})
+
+	It("Should render the ownerReference of the OperandRequest", func() {
+		withoutOwner, err := r.renderCustomResource(context.Background(), template, "cloudpak", nil, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		template.SetOwnerReferences([]metav1.OwnerReference{newOwnerReference(request)})
+		cr, err := r.renderCustomResource(context.Background(), template, "cloudpak", nil, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cr.GetOwnerReferences()).Should(HaveLen(1))
+		owner := cr.GetOwnerReferences()[0]
+		Expect(owner.Kind).Should(Equal("OperandRequest"))
+		Expect(owner.APIVersion).Should(Equal("operator.ibm.com/v1alpha1"))
+		Expect(owner.UID).Should(Equal(request.UID))
+		Expect(owner.Controller).Should(BeNil())
+		Expect(*owner.BlockOwnerDeletion).Should(BeFalse())
+
+		// The existing custom resources without the ownerReference are updated
+		Expect(cr.GetAnnotations()[constant.OpreqSpecHashAnnotation]).ShouldNot(Equal(withoutOwner.GetAnnotations()[constant.OpreqSpecHashAnnotation]))
+	})
+
+	It("Should block the deletion of the OperandRequest with the foreground deletion", func() {
+		request.Spec.ForegroundDeletion = true
+		Expect(*newOwnerReference(request).BlockOwnerDeletion).Should(BeTrue())
+	})
+})
+
+var _ = Describe("Creating multiple custom resources for an operand", func() {
+
+	var (
+		r       *Reconciler
+		request *operatorv1alpha1.OperandRequest
+		ctx     context.Context
+	)
+
+	newTopic := func(name string) *unstructured.Unstructured {
+		topic := &unstructured.Unstructured{}
+		topic.SetAPIVersion("kafka.strimzi.io/v1beta1")
+		topic.SetKind("KafkaTopic")
+		topic.SetName(name)
+		topic.SetNamespace("cloudpak")
+		topic.SetLabels(map[string]string{constant.OpreqLabel: "true"})
+		return topic
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, newTopic("orders"), newTopic("payments"))
+		r = &Reconciler{ODLMOperator: &deploy.ODLMOperator{Client: c, Reader: c}}
+		request = &operatorv1alpha1.OperandRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloudpak", Namespace: "cloudpak"},
+			Spec: operatorv1alpha1.OperandRequestSpec{
+				Requests: []operatorv1alpha1.Request{{
+					Registry: "common-service",
+					Operands: []operatorv1alpha1.Operand{{
+						Name: "kafka",
+						Resources: []operatorv1alpha1.OperandResource{
+							{Kind: "KafkaTopic", Name: "orders"},
+							{Kind: "KafkaTopic", Name: "payments", Spec: &runtime.RawExtension{Raw: []byte(`{"namespace":"{{ .RequestNamespace }}"}`)}},
+						},
+					}},
+				}},
+			},
+		}
+		request.SetMemberStatus("kafka", operatorv1alpha1.OperatorRunning, operatorv1alpha1.ServiceRunning)
+		for _, name := range []string{"orders", "payments"} {
+			request.SetMemberCRStatus("kafka", name, "KafkaTopic", "kafka.strimzi.io/v1beta1", "cloudpak")
+		}
+	})
+
+	It("Should default the names of the custom resources", func() {
+		operand := operatorv1alpha1.Operand{Name: "kafka", Resources: []operatorv1alpha1.OperandResource{{Kind: "Kafka"}}}
+		Expect(request.GetOperandResources(operand)).Should(Equal([]operatorv1alpha1.OperandResource{{Kind: "Kafka", Name: "cloudpak"}}))
+
+		operand = operatorv1alpha1.Operand{Name: "kafka", Kind: "Kafka", InstanceName: "events"}
+		Expect(request.GetOperandResources(operand)).Should(Equal([]operatorv1alpha1.OperandResource{{Kind: "Kafka", Name: "events"}}))
+
+		Expect(request.GetOperandResources(operatorv1alpha1.Operand{Name: "kafka"})).Should(BeEmpty())
+	})
+
+	It("Should render the spec of each custom resource", func() {
+		rendered, err := renderOperand(request.Spec.Requests[0].Operands[0], util.TemplateValues{RequestNamespace: "cloudpak"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered.Resources[0].Spec).Should(BeNil())
+		Expect(string(rendered.Resources[1].Spec.Raw)).Should(Equal(`{"namespace":"cloudpak"}`))
+	})
+
+	It("Should only delete the custom resource removed from the operand", func() {
+		request.Spec.Requests[0].Operands[0].Resources = request.Spec.Requests[0].Operands[0].Resources[:1]
+		Expect(r.checkCustomResource(ctx, request)).To(Succeed())
+
+		Expect(r.Client.Get(ctx, types.NamespacedName{Name: "orders", Namespace: "cloudpak"}, newTopic("orders"))).To(Succeed())
+		err := r.Client.Get(ctx, types.NamespacedName{Name: "payments", Namespace: "cloudpak"}, newTopic("payments"))
+		Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+
+		crList := request.Status.Members[0].OperandCRList
+		Expect(crList).Should(HaveLen(1))
+		Expect(crList[0].Name).Should(Equal("orders"))
+	})
+})
@@ -20,15 +20,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	gset "github.com/deckarep/golang-set"
-	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
-	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,10 +39,18 @@ import (
 func (r *Reconciler) reconcileOperator(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) error {
 	klog.V(1).Infof("Reconciling Operators for OperandRequest: %s/%s", requestInstance.GetNamespace(), requestInstance.GetName())
 
+	// The OperandRegistry which is not found makes the request not ready
+	var notFoundRegistry string
+	// The operands which are pulled in as dependencies of the requested operands
+	var dependencies []string
+
 	// Update request status
 	defer func() {
-		requestInstance.FreshMemberStatus()
+		requestInstance.FreshMemberStatus(dependencies...)
 		requestInstance.UpdateClusterPhase()
+		if notFoundRegistry != "" {
+			requestInstance.SetNotFoundOperandRegistryCondition(notFoundRegistry, operatorv1alpha1.ResourceTypeOperandRegistry)
+		}
 	}()
 
 	for _, req := range requestInstance.Spec.Requests {
@@ -54,7 +60,7 @@ func (r *Reconciler) reconcileOperator(ctx context.Context, requestInstance *ope
 			if apierrors.IsNotFound(err) {
 				r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, "NotFound", "NotFound OperandRegistry NamespacedName %s", registryKey.String())
 				klog.Errorf("failed to fnd OperandRegistry %s : %v", registryKey.String(), err)
-				requestInstance.SetNotFoundOperatorFromRegistryCondition(registryKey.String(), operatorv1alpha1.ResourceTypeOperandRegistry, corev1.ConditionTrue)
+				notFoundRegistry = registryKey.String()
 				mergePatch, _ := json.Marshal(map[string]interface{}{
 					"metadata": map[string]interface{}{
 						"annotations": map[string]interface{}{
@@ -66,54 +72,29 @@ func (r *Reconciler) reconcileOperator(ctx context.Context, requestInstance *ope
 			}
 			return err
 		}
-		for _, operand := range req.Operands {
+		operands, err := getOperandsInOrder(registryInstance, req.Operands)
+		if err != nil {
+			return err
+		}
+		for _, operand := range operands {
+			// The dependencies are pulled in by the OperandRegistry, so the scope only applies to the requested operands
+			requested := isRequestedOperand(req.Operands, operand.Name)
+			if !requested {
+				dependencies = append(dependencies, operand.Name)
+			}
 			// Check the requested Operand if exist in specific OperandRegistry
 			opt := registryInstance.GetOperator(operand.Name)
 			if opt != nil {
-				if opt.Scope == operatorv1alpha1.ScopePrivate && requestInstance.Namespace != registryInstance.Namespace {
+				if requested && opt.Scope == operatorv1alpha1.ScopePrivate && requestInstance.Namespace != registryInstance.Namespace {
 					klog.Warningf("Operator %s is private. It can't be requested from namespace %s", operand.Name, requestInstance.Namespace)
 					requestInstance.SetOutofScopeCondition(operand.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionTrue)
 					continue
 				}
 
-				// Check subscription if exist
-				namespace := r.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
-				sub, err := r.GetSubscription(ctx, opt.Name, namespace, opt.PackageName)
-
-				if err != nil {
-					if apierrors.IsNotFound(err) {
-						// Subscription does not exist, create a new one
-						if err = r.createSubscription(ctx, requestInstance, opt); err != nil {
-							requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "")
-							return err
-						}
-						requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorInstalling, "")
-						continue
-					}
+				if err := r.installOperator(ctx, requestInstance, registryInstance, opt); err != nil {
 					return err
 				}
-
-				// Subscription existing and managed by OperandRequest controller
-				if _, ok := sub.Labels[constant.OpreqLabel]; ok {
-					// Subscription channel changed, update it.
-					if compareSub(sub.Spec, opt) {
-						sub.Spec.CatalogSource = opt.SourceName
-						sub.Spec.Channel = opt.Channel
-						sub.Spec.CatalogSourceNamespace = opt.SourceNamespace
-						sub.Spec.Package = opt.PackageName
-						if opt.InstallPlanApproval != "" && sub.Spec.InstallPlanApproval != opt.InstallPlanApproval {
-							sub.Spec.InstallPlanApproval = opt.InstallPlanApproval
-						}
-						if err = r.updateSubscription(ctx, requestInstance, sub); err != nil {
-							requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "")
-							return err
-						}
-						requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorUpdating, "")
-					}
-				} else {
-					// Subscription existing and not managed by OperandRequest controller
-					klog.V(1).Infof("Subscription %s in namespace %s isn't created by ODLM. Ignore update/delete it.", sub.Name, sub.Namespace)
-				}
+				requestInstance.SetMemberRegistry(operand.Name, registryKey)
 			} else {
 				klog.V(1).Infof("Operator %s not found in the OperandRegistry %s/%s", operand.Name, registryInstance.Namespace, registryInstance.Name)
 				requestInstance.SetNotFoundOperatorFromRegistryCondition(operand.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionTrue)
@@ -130,8 +111,10 @@ func (r *Reconciler) reconcileOperator(ctx context.Context, requestInstance *ope
 	})
 	_ = r.Patch(ctx, requestInstance, client.RawPatch(types.MergePatchType, mergePatch))
 
-	// Delete specific operators
-	if err := r.absentOperatorsAndOperands(ctx, requestInstance); err != nil {
+	// Delete specific operators, the members of the operators not uninstalled yet are kept
+	remaining, err := r.absentOperatorsAndOperands(ctx, requestInstance)
+	dependencies = append(dependencies, remaining...)
+	if err != nil {
 		return err
 	}
 	klog.V(1).Infof("Finished reconciling Operators for OperandRequest: %s/%s", requestInstance.GetNamespace(), requestInstance.GetName())
@@ -139,153 +122,196 @@ func (r *Reconciler) reconcileOperator(ctx context.Context, requestInstance *ope
 	return nil
 }
 
-func (r *Reconciler) createSubscription(ctx context.Context, cr *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator) error {
-	namespace := r.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
-	klog.V(3).Info("Subscription Namespace: ", namespace)
-
-	co := r.generateClusterObjects(opt)
-
-	// Create required namespace
-	ns := co.namespace
-	klog.V(3).Info("Creating the Namespace for Operator: " + opt.Name)
-
-	// Compare namespace and create namespace
-	oprNs := util.GetOperatorNamespace()
-	if ns.Name != oprNs && ns.Name != constant.ClusterOperatorNamespace {
-		if err := r.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
-			klog.Warningf("failed to create the namespace %s, please make sure it exists: %s", ns.Name, err)
-		}
+// installOperator installs the operator by its Installer, the operator which isn't installed yet waits for its dependencies
+func (r *Reconciler) installOperator(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) error {
+	installer := r.GetInstaller(registryInstance, opt)
+	status, err := installer.Status(ctx, registryInstance, opt)
+	if err != nil {
+		return err
 	}
-
-	if namespace != constant.ClusterOperatorNamespace {
-		// Create required operatorgroup
-		existOG := &olmv1.OperatorGroupList{}
-		if err := r.Client.List(ctx, existOG, &client.ListOptions{Namespace: co.operatorGroup.Namespace}); err != nil {
+	if !status.Installed {
+		// Wait for the dependencies to be installed
+		dep, err := r.getNotReadyDependency(ctx, registryInstance, opt)
+		if err != nil {
 			return err
 		}
-		if len(existOG.Items) == 0 {
-			og := co.operatorGroup
-			klog.V(3).Info("Creating the OperatorGroup for Subscription: " + opt.Name)
-			if err := r.Create(ctx, og); err != nil && !apierrors.IsAlreadyExists(err) {
-				return err
-			}
+		if dep != "" {
+			klog.V(2).Infof("Operator %s is waiting for its dependency %s to be installed", opt.Name, dep)
+			requestInstance.SetWaitingForDependencyCondition(opt.Name, dep)
+			requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorInstalling, "")
+			return nil
 		}
 	}
-
-	// Create subscription
-	klog.V(2).Info("Creating the Subscription: " + opt.Name)
-	sub := co.subscription
-	cr.SetCreatingCondition(sub.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionTrue)
-
-	if err := r.Create(ctx, sub); err != nil && !apierrors.IsAlreadyExists(err) {
-		cr.SetCreatingCondition(sub.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionFalse)
-		return err
-	}
-	return nil
+	return installer.Install(ctx, requestInstance, registryInstance, opt)
 }
 
-func (r *Reconciler) updateSubscription(ctx context.Context, cr *operatorv1alpha1.OperandRequest, sub *olmv1alpha1.Subscription) error {
-
-	klog.V(2).Infof("Updating Subscription %s/%s ...", sub.Namespace, sub.Name)
-	cr.SetUpdatingCondition(sub.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionTrue)
-
-	if err := r.Update(ctx, sub); err != nil {
-		cr.SetUpdatingCondition(sub.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionFalse)
-		return err
+// getNotReadyDependency returns the first dependency of the operator which isn't running.
+// It returns an empty string when all the dependencies are installed.
+func (r *Reconciler) getNotReadyDependency(ctx context.Context, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) (string, error) {
+	for _, dep := range opt.Dependencies {
+		depOpt := registryInstance.GetOperator(dep)
+		if depOpt == nil {
+			return dep, nil
+		}
+		status, err := r.GetInstaller(registryInstance, depOpt).Status(ctx, registryInstance, depOpt)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get the status of the dependency %s", dep)
+		}
+		if !status.Installed || status.Phase != operatorv1alpha1.OperatorRunning {
+			return dep, nil
+		}
 	}
-	return nil
+	return "", nil
 }
 
-func (r *Reconciler) deleteSubscription(ctx context.Context, operandName string, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig) error {
+// deleteOperator deletes the custom resources of the operator, and uninstalls the operator installed by ODLM.
+// It returns false when the operator is still being uninstalled.
+func (r *Reconciler) deleteOperator(ctx context.Context, operandName string, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig) (bool, error) {
 	op := registryInstance.GetOperator(operandName)
 	if op == nil {
 		klog.Warningf("Operand %s not found", operandName)
-		return nil
+		return true, nil
 	}
 
-	namespace := r.GetOperatorNamespace(op.InstallMode, op.Namespace)
-	sub, err := r.GetSubscription(ctx, operandName, namespace, op.PackageName)
-
-	if apierrors.IsNotFound(err) {
-		klog.V(3).Infof("There is no Subscription %s or %s in the namespace %s", operandName, op.PackageName, namespace)
-		return nil
+	installer := r.GetInstaller(registryInstance, op)
+	status, err := installer.Status(ctx, registryInstance, op)
+	if err != nil {
+		return false, err
 	}
-
-	if _, ok := sub.Labels[constant.OpreqLabel]; !ok {
-		// Subscription existing and not managed by OperandRequest controller
-		klog.V(2).Infof("Subscription %s in the namespace %s isn't created by ODLM", sub.Name, sub.Namespace)
-		return nil
+	if !status.Installed {
+		klog.V(3).Infof("Operator %s isn't installed", op.Name)
+		return true, nil
+	}
+	if !status.Managed {
+		klog.V(2).Infof("Operator %s isn't installed by ODLM", op.Name)
+		return true, nil
 	}
 
-	csv, err := r.GetClusterServiceVersion(ctx, sub)
-	// If can't get CSV, requeue the request
+	templates, err := installer.Templates(ctx, registryInstance, op)
+	// If can't get the templates, requeue the request
 	if err != nil {
-		return err
+		return false, err
 	}
-
-	if csv != nil {
-		klog.V(2).Infof("Deleting all the Custom Resources for CSV, Namespace: %s, Name: %s", csv.Namespace, csv.Name)
-		if err := r.deleteAllCustomResource(ctx, csv, requestInstance, configInstance, operandName, op.Namespace); err != nil {
-			return err
-		}
-
-		if r.checkUninstallLabel(ctx, op.Name, namespace) {
-			klog.V(1).Infof("Operator %s has label operator.ibm.com/opreq-do-not-uninstall. Skip the uninstall", op.Name)
-			return nil
-		}
-
-		klog.V(3).Info("Set Deleting Condition in the operandRequest")
-		requestInstance.SetDeletingCondition(csv.Name, operatorv1alpha1.ResourceTypeCsv, corev1.ConditionTrue)
-
-		klog.V(1).Infof("Deleting the ClusterServiceVersion, Namespace: %s, Name: %s", csv.Namespace, csv.Name)
-		if err := r.Delete(ctx, csv); err != nil {
-			requestInstance.SetDeletingCondition(csv.Name, operatorv1alpha1.ResourceTypeCsv, corev1.ConditionFalse)
-			return errors.Wrap(err, "failed to delete the ClusterServiceVersion")
+	if len(templates) != 0 {
+		klog.V(2).Infof("Deleting all the Custom Resources of the operator %s", op.Name)
+		if err := r.deleteAllCustomResource(ctx, templates, requestInstance, configInstance, operandName, op.Namespace); err != nil {
+			return false, err
 		}
 	}
 
-	klog.V(2).Infof("Deleting the Subscription, Namespace: %s, Name: %s", namespace, op.Name)
-	requestInstance.SetDeletingCondition(op.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionTrue)
-
-	if err := r.Delete(ctx, sub); err != nil {
-		if apierrors.IsNotFound(err) {
-			klog.Warningf("Subscription %s was not found in namespace %s", op.Name, namespace)
-		} else {
-			requestInstance.SetDeletingCondition(op.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionFalse)
-			return errors.Wrap(err, "failed to delete subscription")
-		}
+	if err := installer.Uninstall(ctx, requestInstance, registryInstance, op); err != nil {
+		return false, err
 	}
-
-	klog.V(1).Infof("Subscription %s/%s is deleted", namespace, op.Name)
-	return nil
+	status, err = installer.Status(ctx, registryInstance, op)
+	if err != nil {
+		return false, err
+	}
+	return !status.Installed, nil
 }
 
-func (r *Reconciler) absentOperatorsAndOperands(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) error {
+// absentOperatorsAndOperands uninstalls the operators which are no longer requested from the
+// OperandRegistry they are installed from. The dependents are uninstalled before their dependencies,
+// and a dependency is only uninstalled once its dependents are gone. It returns the operators which
+// are not uninstalled yet, and an error to requeue the request while any of them is waiting.
+func (r *Reconciler) absentOperatorsAndOperands(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) ([]string, error) {
 	needDeletedOperands, err := r.getNeedDeletedOperands(ctx, requestInstance)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	for _, req := range requestInstance.Spec.Requests {
-		registryKey := requestInstance.GetRegistryKey(req)
+	operandsByRegistry := groupOperandsByRegistry(requestInstance, needDeletedOperands)
+	var registryKeys []types.NamespacedName
+	for key := range operandsByRegistry {
+		registryKeys = append(registryKeys, key)
+	}
+	sort.Slice(registryKeys, func(i, j int) bool { return registryKeys[i].String() < registryKeys[j].String() })
+
+	merr := &util.MultiErr{}
+	var remaining, waiting []string
+	for _, registryKey := range registryKeys {
+		names := operandsByRegistry[registryKey]
 		registryInstance, err := r.GetOperandRegistry(ctx, registryKey)
 		if err != nil {
-			return err
+			if apierrors.IsNotFound(err) {
+				klog.Warningf("OperandRegistry %s is not found, skip uninstalling the operators %v", registryKey.String(), names)
+				continue
+			}
+			merr.Add(err)
+			remaining = append(remaining, names...)
+			continue
 		}
 		configInstance, err := r.GetOperandConfig(ctx, registryKey)
 		if err != nil {
-			return err
+			merr.Add(err)
+			remaining = append(remaining, names...)
+			continue
+		}
+		order, err := registryInstance.ResolveDependencies(names)
+		if err != nil {
+			merr.Add(err)
+			remaining = append(remaining, names...)
+			continue
 		}
-		merr := &util.MultiErr{}
-		for o := range needDeletedOperands.Iter() {
-			if err := r.deleteSubscription(ctx, fmt.Sprintf("%v", o), requestInstance, registryInstance, configInstance); err != nil {
+		// Delete the dependents before their dependencies
+		notDeleted := gset.NewSet()
+		for i := len(order) - 1; i >= 0; i-- {
+			name := order[i]
+			if !containsString(names, name) {
+				continue
+			}
+			if hasDependent(registryInstance, notDeleted, name) {
+				klog.V(2).Infof("Skip deleting operator %s, because the operators depending on it are not uninstalled", name)
+				notDeleted.Add(name)
+				waiting = append(waiting, name)
+				continue
+			}
+			uninstalled, err := r.deleteOperator(ctx, name, requestInstance, registryInstance, configInstance)
+			if err != nil {
 				merr.Add(err)
+				notDeleted.Add(name)
+				continue
+			}
+			if !uninstalled {
+				klog.V(2).Infof("Waiting for operator %s to be uninstalled", name)
+				notDeleted.Add(name)
 			}
 		}
-		if len(merr.Errors) != 0 {
-			return merr
+		for o := range notDeleted.Iter() {
+			remaining = append(remaining, fmt.Sprintf("%v", o))
 		}
 	}
-	return nil
+	sort.Strings(remaining)
+	if len(merr.Errors) != 0 {
+		return remaining, merr
+	}
+	if len(waiting) != 0 {
+		sort.Strings(waiting)
+		return remaining, errors.Errorf("operators %s are waiting for the operators depending on them to be uninstalled", strings.Join(waiting, ", "))
+	}
+	return remaining, nil
+}
+
+// groupOperandsByRegistry groups the operands by the OperandRegistry they are installed from.
+// The operands whose OperandRegistry isn't recorded in their members are looked up in every
+// OperandRegistry of the request.
+func groupOperandsByRegistry(requestInstance *operatorv1alpha1.OperandRequest, operands gset.Set) map[types.NamespacedName][]string {
+	groups := make(map[types.NamespacedName][]string)
+	for o := range operands.Iter() {
+		name := fmt.Sprintf("%v", o)
+		if registryKey, ok := requestInstance.GetMemberRegistryKey(name); ok {
+			groups[registryKey] = append(groups[registryKey], name)
+			continue
+		}
+		for _, req := range requestInstance.Spec.Requests {
+			registryKey := requestInstance.GetRegistryKey(req)
+			if !containsString(groups[registryKey], name) {
+				groups[registryKey] = append(groups[registryKey], name)
+			}
+		}
+	}
+	for key := range groups {
+		sort.Strings(groups[key])
+	}
+	return groups
 }
 
 func (r *Reconciler) getNeedDeletedOperands(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) (gset.Set, error) {
@@ -312,6 +338,10 @@ func (r *Reconciler) getCurrentOperands(ctx context.Context, requestInstance *op
 		if err != nil {
 			return nil, err
 		}
+		registryInstance, err := r.GetOperandRegistry(ctx, registryKey)
+		if err != nil {
+			return nil, err
+		}
 		for _, item := range requestList {
 			if !item.DeletionTimestamp.IsZero() {
 				continue
@@ -321,7 +351,12 @@ func (r *Reconciler) getCurrentOperands(ctx context.Context, requestInstance *op
 				if registryKey.String() != existRegistryKey.String() {
 					continue
 				}
-				for _, operand := range existingReq.Operands {
+				// The dependencies of the operands are still in use
+				operands, err := getOperandsInOrder(registryInstance, existingReq.Operands)
+				if err != nil {
+					return nil, err
+				}
+				for _, operand := range operands {
 					deployedOperands.Add(operand.Name)
 				}
 			}
@@ -330,89 +365,54 @@ func (r *Reconciler) getCurrentOperands(ctx context.Context, requestInstance *op
 	return deployedOperands, nil
 }
 
-func (r *Reconciler) generateClusterObjects(o *operatorv1alpha1.Operator) *clusterObjects {
-	klog.V(3).Info("Generating Cluster Objects")
-	co := &clusterObjects{}
-	labels := map[string]string{
-		constant.OpreqLabel: "true",
+// getOperandsInOrder returns the operands and the operands they depend on.
+// Every operand comes after its dependencies, and the dependencies which are
+// not requested are added without kind and spec.
+func getOperandsInOrder(registryInstance *operatorv1alpha1.OperandRegistry, operands []operatorv1alpha1.Operand) ([]operatorv1alpha1.Operand, error) {
+	var names []string
+	for _, operand := range operands {
+		names = append(names, operand.Name)
 	}
-
-	klog.V(3).Info("Generating Namespace: ", o.Namespace)
-	// Namespace Object
-	co.namespace = &corev1.Namespace{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "Namespace",
-			APIVersion: "v1",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:   o.Namespace,
-			Labels: labels,
-		},
+	order, err := registryInstance.ResolveDependencies(names)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve the dependencies of the operands")
 	}
-
-	// Operator Group Object
-	klog.V(3).Info("Generating Operator Group in the Namespace: ", o.Namespace, " with target namespace: ", o.TargetNamespaces)
-	og := generateOperatorGroup(o.Namespace, o.TargetNamespaces)
-	co.operatorGroup = og
-
-	// The namespace is 'openshift-operators' when installMode is cluster
-	namespace := r.GetOperatorNamespace(o.InstallMode, o.Namespace)
-
-	// Subscription Object
-	sub := &olmv1alpha1.Subscription{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      o.Name,
-			Namespace: namespace,
-			Labels:    labels,
-		},
-		Spec: &olmv1alpha1.SubscriptionSpec{
-			Channel:                o.Channel,
-			Package:                o.PackageName,
-			CatalogSource:          o.SourceName,
-			CatalogSourceNamespace: o.SourceNamespace,
-			InstallPlanApproval:    o.InstallPlanApproval,
-		},
+	var result []operatorv1alpha1.Operand
+	for _, name := range order {
+		if !isRequestedOperand(operands, name) {
+			result = append(result, operatorv1alpha1.Operand{Name: name})
+			continue
+		}
+		for _, operand := range operands {
+			if operand.Name == name {
+				result = append(result, operand)
+			}
+		}
 	}
-	sub.SetGroupVersionKind(schema.GroupVersionKind{Group: olmv1alpha1.SchemeGroupVersion.Group, Kind: "Subscription", Version: olmv1alpha1.SchemeGroupVersion.Version})
-	klog.V(3).Info("Generating Subscription:  ", o.Name, " in the Namespace: ", namespace)
-	co.subscription = sub
-	return co
+	return result, nil
 }
 
-func generateOperatorGroup(namespace string, targetNamespaces []string) *olmv1.OperatorGroup {
-	labels := map[string]string{
-		constant.OpreqLabel: "true",
-	}
-	if targetNamespaces == nil {
-		targetNamespaces = append(targetNamespaces, namespace)
-	}
-	// Operator Group Object
-	og := &olmv1.OperatorGroup{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "operand-deployment-lifecycle-manager-operatorgroup",
-			Namespace: namespace,
-			Labels:    labels,
-		},
-		Spec: olmv1.OperatorGroupSpec{
-			TargetNamespaces: targetNamespaces,
-		},
+func isRequestedOperand(operands []operatorv1alpha1.Operand, name string) bool {
+	for _, operand := range operands {
+		if operand.Name == name {
+			return true
+		}
 	}
-	og.SetGroupVersionKind(schema.GroupVersionKind{Group: olmv1.SchemeGroupVersion.Group, Kind: "OperatorGroup", Version: olmv1.SchemeGroupVersion.Version})
-
-	return og
+	return false
 }
 
-func (r *Reconciler) checkUninstallLabel(ctx context.Context, name, namespace string) bool {
-	sub := &olmv1alpha1.Subscription{}
-	subKey := types.NamespacedName{Name: name, Namespace: namespace}
-	if err := r.Client.Get(ctx, subKey, sub); err != nil {
-		klog.Warning("failed to get subscription: ", err)
-		return true
+// hasDependent checks if any operator in the set depends on the operator with the name
+func hasDependent(registryInstance *operatorv1alpha1.OperandRegistry, operators gset.Set, name string) bool {
+	for o := range operators.Iter() {
+		opt := registryInstance.GetOperator(fmt.Sprintf("%v", o))
+		if opt == nil {
+			continue
+		}
+		for _, dep := range opt.Dependencies {
+			if dep == name {
+				return true
+			}
+		}
 	}
-	subLabels := sub.GetLabels()
-	return subLabels[constant.NotUninstallLabel] == "true"
-}
-
-func compareSub(spec *olmv1alpha1.SubscriptionSpec, template *operatorv1alpha1.Operator) (needUpdate bool) {
-	return spec.CatalogSource != template.SourceName || spec.Channel != template.Channel || spec.CatalogSourceNamespace != template.SourceNamespace || spec.Package != template.PackageName || spec.InstallPlanApproval != template.InstallPlanApproval
+	return false
 }
@@ -0,0 +1,152 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
+)
+
+// fakeInstaller records the operators installed and uninstalled, and reports the status set in the tests
+type fakeInstaller struct {
+	status      map[string]*deploy.InstallStatus
+	installed   []string
+	uninstalled []string
+	// uninstalling are the operators which are still installed after Uninstall
+	uninstalling map[string]bool
+}
+
+func (f *fakeInstaller) Install(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) error {
+	f.installed = append(f.installed, opt.Name)
+	return nil
+}
+
+func (f *fakeInstaller) Uninstall(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) error {
+	f.uninstalled = append(f.uninstalled, opt.Name)
+	if !f.uninstalling[opt.Name] {
+		delete(f.status, opt.Name)
+	}
+	return nil
+}
+
+func (f *fakeInstaller) Status(ctx context.Context, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) (*deploy.InstallStatus, error) {
+	if status, ok := f.status[opt.Name]; ok {
+		return status, nil
+	}
+	return &deploy.InstallStatus{}, nil
+}
+
+func (f *fakeInstaller) Templates(ctx context.Context, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) ([]unstructured.Unstructured, error) {
+	return []unstructured.Unstructured{}, nil
+}
+
+var _ = Describe("Reconciling the operators with the Installer", func() {
+	var (
+		ctx       context.Context
+		r         *Reconciler
+		installer *fakeInstaller
+		registry  *operatorv1alpha1.OperandRegistry
+		request   *operatorv1alpha1.OperandRequest
+		opt       *operatorv1alpha1.Operator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		installer = &fakeInstaller{status: map[string]*deploy.InstallStatus{}, uninstalling: map[string]bool{}}
+		r = &Reconciler{ODLMOperator: &deploy.ODLMOperator{
+			Installers: func(*operatorv1alpha1.OperandRegistry, *operatorv1alpha1.Operator) deploy.Installer { return installer },
+		}}
+		registry = testutil.OperandRegistryObj("common-service", "ibm-common-services", "ibm-operators")
+		request = testutil.OperandRequestObj("common-service", "ibm-common-services", "cloudpak", "cloudpak")
+		opt = registry.GetOperator("jenkins")
+		opt.Dependencies = []string{"etcd"}
+	})
+
+	It("Should wait for the dependencies to be running", func() {
+		installer.status["etcd"] = &deploy.InstallStatus{Installed: true, Managed: true, Phase: operatorv1alpha1.OperatorInstalling}
+		Expect(r.installOperator(ctx, request, registry, opt)).To(Succeed())
+		Expect(installer.installed).Should(BeEmpty())
+		Expect(request.Status.Members).Should(HaveLen(1))
+		Expect(request.Status.Members[0].Phase.OperatorPhase).Should(Equal(operatorv1alpha1.OperatorInstalling))
+
+		installer.status["etcd"].Phase = operatorv1alpha1.OperatorRunning
+		Expect(r.installOperator(ctx, request, registry, opt)).To(Succeed())
+		Expect(installer.installed).Should(Equal([]string{"jenkins"}))
+	})
+
+	It("Should keep updating the installed operator without waiting for the dependencies", func() {
+		installer.status["jenkins"] = &deploy.InstallStatus{Installed: true, Managed: true, Phase: operatorv1alpha1.OperatorRunning}
+		Expect(r.installOperator(ctx, request, registry, opt)).To(Succeed())
+		Expect(installer.installed).Should(Equal([]string{"jenkins"}))
+	})
+
+	It("Should only uninstall the operators installed by ODLM", func() {
+		Expect(r.deleteOperator(ctx, "jenkins", request, registry, nil)).Should(BeTrue())
+		installer.status["jenkins"] = &deploy.InstallStatus{Installed: true, Phase: operatorv1alpha1.OperatorRunning}
+		Expect(r.deleteOperator(ctx, "jenkins", request, registry, nil)).Should(BeTrue())
+		Expect(installer.uninstalled).Should(BeEmpty())
+
+		installer.status["jenkins"].Managed = true
+		Expect(r.deleteOperator(ctx, "jenkins", request, registry, nil)).Should(BeTrue())
+		Expect(installer.uninstalled).Should(Equal([]string{"jenkins"}))
+	})
+
+	It("Should uninstall a dependency after its dependents are uninstalled from their OperandRegistry", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(operatorv1alpha1.AddToScheme(scheme)).To(Succeed())
+		config := testutil.OperandConfigObj("common-service", "ibm-common-services")
+		for i := range registry.Spec.Operators {
+			if registry.Spec.Operators[i].Name == "jenkins" {
+				registry.Spec.Operators[i].Dependencies = []string{"etcd"}
+			}
+		}
+		r.Client = fake.NewFakeClientWithScheme(scheme, registry, config)
+		r.Reader = r.Client
+
+		// The request now uses another OperandRegistry, the members are installed from the first one
+		registryKey := types.NamespacedName{Namespace: registry.Namespace, Name: registry.Name}
+		request.Spec.Requests = nil
+		request.SetMemberRegistry("etcd", registryKey)
+		request.SetMemberRegistry("jenkins", registryKey)
+		installer.status["etcd"] = &deploy.InstallStatus{Installed: true, Managed: true, Phase: operatorv1alpha1.OperatorRunning}
+		installer.status["jenkins"] = &deploy.InstallStatus{Installed: true, Managed: true, Phase: operatorv1alpha1.OperatorRunning}
+		installer.uninstalling["jenkins"] = true
+
+		remaining, err := r.absentOperatorsAndOperands(ctx, request)
+		Expect(err).Should(HaveOccurred())
+		Expect(remaining).Should(Equal([]string{"etcd", "jenkins"}))
+		Expect(installer.uninstalled).Should(Equal([]string{"jenkins"}))
+
+		installer.status["jenkins"].Installed = false
+		remaining, err = r.absentOperatorsAndOperands(ctx, request)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(remaining).Should(BeEmpty())
+		Expect(installer.uninstalled).Should(Equal([]string{"jenkins", "etcd"}))
+	})
+})
@@ -0,0 +1,162 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// +kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch
+
+// referenceKinds are the kinds of the objects referenced by the valueFrom
+var referenceKinds = map[string]schema.GroupVersionResource{
+	"Secret":    {Version: "v1", Resource: "secrets"},
+	"ConfigMap": {Version: "v1", Resource: "configmaps"},
+}
+
+// referenceWatcher watches the Secrets and ConfigMaps referenced by the valueFrom of the OperandRequests.
+// The referenced objects belong to the users, so they aren't labelled for the cache of the manager.
+// Only the metadata is watched, without the annotations, and only in the namespaces with a referenced
+// object, so neither the values of the Secrets nor the other objects of the cluster are kept in memory.
+// The referenced objects themselves are read from the API server.
+type referenceWatcher struct {
+	client metadata.Interface
+	// events receives the changed objects of each kind, a controller source reads them
+	events map[string]chan event.GenericEvent
+
+	mutex      sync.Mutex
+	namespaces map[string]chan struct{}
+	stopped    bool
+}
+
+// newReferenceWatcher creates a watcher with the metadata client
+func newReferenceWatcher(client metadata.Interface) *referenceWatcher {
+	w := &referenceWatcher{
+		client:     client,
+		events:     make(map[string]chan event.GenericEvent),
+		namespaces: make(map[string]chan struct{}),
+	}
+	for kind := range referenceKinds {
+		w.events[kind] = make(chan event.GenericEvent, 100)
+	}
+	return w
+}
+
+// watchNamespace starts watching the referenced kinds in the namespace, if they aren't watched yet
+func (w *referenceWatcher) watchNamespace(namespace string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.stopped {
+		return
+	}
+	if _, ok := w.namespaces[namespace]; ok {
+		return
+	}
+	stop := make(chan struct{})
+	w.namespaces[namespace] = stop
+	for kind, gvr := range referenceKinds {
+		go w.newInformer(kind, gvr, namespace).Run(stop)
+	}
+	klog.V(2).Infof("Watching the referenced Secrets and ConfigMaps in the namespace %s", namespace)
+}
+
+// newInformer creates the informer of the metadata of a kind in the namespace
+func (w *referenceWatcher) newInformer(kind string, gvr schema.GroupVersionResource, namespace string) cache.SharedIndexInformer {
+	ctx := context.Background()
+	resource := w.client.Resource(gvr).Namespace(namespace)
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			list, err := resource.List(ctx, options)
+			if err != nil {
+				return nil, err
+			}
+			for i := range list.Items {
+				stripMetadata(&list.Items[i])
+			}
+			return list, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			watcher, err := resource.Watch(ctx, options)
+			if err != nil {
+				return nil, err
+			}
+			return watch.Filter(watcher, func(in watch.Event) (watch.Event, bool) {
+				if obj, ok := in.Object.(*metav1.PartialObjectMetadata); ok {
+					stripMetadata(obj)
+				}
+				return in, true
+			}), nil
+		},
+	}, &metav1.PartialObjectMetadata{}, 0, cache.Indexers{})
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			w.send(kind, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if oldObj.(*metav1.PartialObjectMetadata).ResourceVersion != newObj.(*metav1.PartialObjectMetadata).ResourceVersion {
+				w.send(kind, newObj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			w.send(kind, obj)
+		},
+	})
+	return informer
+}
+
+// send passes the changed object to the controller
+func (w *referenceWatcher) send(kind string, obj interface{}) {
+	object, ok := obj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		return
+	}
+	w.events[kind] <- event.GenericEvent{Meta: object, Object: object}
+}
+
+// Start implements manager.Runnable, the informers are stopped with the manager
+func (w *referenceWatcher) Start(stop <-chan struct{}) error {
+	<-stop
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.stopped = true
+	for namespace, stopNamespace := range w.namespaces {
+		close(stopNamespace)
+		delete(w.namespaces, namespace)
+	}
+	return nil
+}
+
+// stripMetadata removes the annotations and managed fields, which can contain the data of the object,
+// like the last applied configuration of kubectl
+func stripMetadata(obj *metav1.PartialObjectMetadata) {
+	obj.Annotations = nil
+	obj.ManagedFields = nil
+}
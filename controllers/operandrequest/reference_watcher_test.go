@@ -0,0 +1,82 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("Watching the referenced Secrets and ConfigMaps", func() {
+
+	var (
+		client *metadatafake.FakeMetadataClient
+		w      *referenceWatcher
+		stop   chan struct{}
+	)
+
+	newSecret := func(namespace, name string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Annotations: map[string]string{"kubectl.kubernetes.io/last-applied-configuration": `{"data":{"password":"cGFzc3cwcmQ="}}`},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(metav1.AddMetaToScheme(scheme)).To(Succeed())
+		client = metadatafake.NewSimpleMetadataClient(scheme, newSecret("ibm-common-services", "db-credentials"), newSecret("other", "db-credentials"))
+		w = newReferenceWatcher(client)
+		stop = make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			Expect(w.Start(stop)).To(Succeed())
+		}()
+	})
+
+	AfterEach(func() {
+		close(stop)
+	})
+
+	It("Should only watch the metadata in the namespaces with a referenced object", func() {
+		w.watchNamespace("ibm-common-services")
+		w.watchNamespace("ibm-common-services")
+
+		var e event.GenericEvent
+		Eventually(w.events["Secret"], 5*time.Second).Should(Receive(&e))
+		Expect(e.Meta.GetNamespace()).Should(Equal("ibm-common-services"))
+		Expect(e.Meta.GetName()).Should(Equal("db-credentials"))
+		Expect(e.Meta.GetAnnotations()).Should(BeEmpty())
+		Consistently(w.events["Secret"], time.Second).ShouldNot(Receive())
+
+		secrets := client.Resource(referenceKinds["Secret"]).Namespace("ibm-common-services")
+		Expect(secrets.Delete(context.Background(), "db-credentials", metav1.DeleteOptions{})).To(Succeed())
+		Eventually(w.events["Secret"], 5*time.Second).Should(Receive(&e))
+		Expect(e.Meta.GetName()).Should(Equal("db-credentials"))
+	})
+})
@@ -0,0 +1,461 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	constant "github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	util "github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+)
+
+// HelmConfigurationGetter returns the configuration of the Helm actions on the releases in the namespace
+type HelmConfigurationGetter func(namespace string) (*action.Configuration, error)
+
+// NewHelmConfigurationGetter returns the HelmConfigurationGetter which stores the releases in Secrets, like the Helm CLI
+func NewHelmConfigurationGetter(config *rest.Config) HelmConfigurationGetter {
+	return func(namespace string) (*action.Configuration, error) {
+		cfg := &action.Configuration{}
+		getter := &restClientGetter{config: config, namespace: namespace}
+		if err := cfg.Init(getter, namespace, "secret", klog.V(4).Infof); err != nil {
+			return nil, errors.Wrapf(err, "failed to initialize the Helm configuration in the namespace %s", namespace)
+		}
+		return cfg, nil
+	}
+}
+
+// restClientGetter provides the Helm actions with the rest config of ODLM instead of a kubeconfig file
+type restClientGetter struct {
+	config    *rest.Config
+	namespace string
+}
+
+func (g *restClientGetter) ToRESTConfig() (*rest.Config, error) {
+	return rest.CopyConfig(g.config), nil
+}
+
+func (g *restClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(g.config)
+	if err != nil {
+		return nil, err
+	}
+	return memory.NewMemCacheClient(dc), nil
+}
+
+func (g *restClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	dc, err := g.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(dc)
+	return restmapper.NewShortcutExpander(mapper, dc), nil
+}
+
+func (g *restClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	overrides := &clientcmd.ConfigOverrides{}
+	overrides.Context.Namespace = g.namespace
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(&clientcmd.ClientConfigLoadingRules{}, overrides)
+}
+
+var _ genericclioptions.RESTClientGetter = &restClientGetter{}
+
+// GetHelmReleaseName returns the name of the Helm release of the operator
+func GetHelmReleaseName(opt *apiv1alpha1.Operator) string {
+	if opt.Helm.ReleaseName != "" {
+		return opt.Helm.ReleaseName
+	}
+	return opt.Name
+}
+
+// GetHelmChart loads the chart of the operator from its path or from the ConfigMap in the namespace of the OperandRegistry
+func (m *ODLMOperator) GetHelmChart(ctx context.Context, opt *apiv1alpha1.Operator, registryNamespace string) (*chart.Chart, error) {
+	if opt.Helm.ChartPath != "" {
+		chrt, err := loader.Load(opt.Helm.ChartPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load the chart %s of the operator %s", opt.Helm.ChartPath, opt.Name)
+		}
+		return chrt, nil
+	}
+	if opt.Helm.ChartConfigMap == nil {
+		return nil, fmt.Errorf("neither the chartPath nor the chartConfigMap of the operator %s is set", opt.Name)
+	}
+	// The chart is installed with the permissions of ODLM, so only the cluster administrators can provide it
+	if !util.IsOperatorNamespace(registryNamespace) {
+		return nil, fmt.Errorf("the chart of the operator %s can only be read from a ConfigMap when the OperandRegistry is in the namespace of ODLM", opt.Name)
+	}
+
+	key := opt.Helm.ChartConfigMap.Key
+	if key == "" {
+		key = constant.DefaultHelmChartKey
+	}
+	cm := &corev1.ConfigMap{}
+	cmKey := types.NamespacedName{Name: opt.Helm.ChartConfigMap.Name, Namespace: registryNamespace}
+	// The ConfigMaps in the cache are filtered by label, so the ConfigMap is read from the API server
+	if err := m.Reader.Get(ctx, cmKey, cm); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the chart ConfigMap %s of the operator %s", cmKey.String(), opt.Name)
+	}
+	data, ok := cm.BinaryData[key]
+	if !ok {
+		return nil, fmt.Errorf("the key %s is not found in the binaryData of the chart ConfigMap %s", key, cmKey.String())
+	}
+	chrt, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the chart in the ConfigMap %s of the operator %s", cmKey.String(), opt.Name)
+	}
+	return chrt, nil
+}
+
+// GetHelmValues returns the values of the Helm release of the operator
+func GetHelmValues(opt *apiv1alpha1.Operator) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if opt.Helm.Values == nil || len(opt.Helm.Values.Raw) == 0 {
+		return values, nil
+	}
+	if err := json.Unmarshal(opt.Helm.Values.Raw, &values); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the Helm values of the operator %s", opt.Name)
+	}
+	return values, nil
+}
+
+// GetHelmRelease gets the latest revision of the Helm release, it returns nil if the release doesn't exist
+func (m *ODLMOperator) GetHelmRelease(namespace, name string) (*release.Release, error) {
+	cfg, err := m.HelmConfiguration(namespace)
+	if err != nil {
+		return nil, err
+	}
+	history, err := cfg.Releases.History(name)
+	if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil, errors.Wrapf(err, "failed to get the Helm release %s/%s", namespace, name)
+	}
+	if len(history) == 0 {
+		return nil, nil
+	}
+	releaseutil.Reverse(history, releaseutil.SortByRevision)
+	return history[0], nil
+}
+
+// InstallHelmRelease installs the chart as the Helm release
+func (m *ODLMOperator) InstallHelmRelease(namespace, name string, chrt *chart.Chart, values map[string]interface{}) (*release.Release, error) {
+	cfg, err := m.HelmConfiguration(namespace)
+	if err != nil {
+		return nil, err
+	}
+	install := action.NewInstall(cfg)
+	install.ReleaseName = name
+	install.Namespace = namespace
+	install.Timeout = constant.DefaultHelmTimeout
+	// The failed release is installed again
+	install.Replace = true
+	install.PostRenderer = &labelPostRenderer{labels: map[string]string{constant.OpreqLabel: "true"}}
+	rel, err := install.Run(chrt, values)
+	if err != nil {
+		return rel, errors.Wrapf(err, "failed to install the Helm release %s/%s", namespace, name)
+	}
+	return rel, nil
+}
+
+// UpgradeHelmRelease upgrades the Helm release to the chart and the values
+func (m *ODLMOperator) UpgradeHelmRelease(namespace, name string, chrt *chart.Chart, values map[string]interface{}) (*release.Release, error) {
+	cfg, err := m.HelmConfiguration(namespace)
+	if err != nil {
+		return nil, err
+	}
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.Namespace = namespace
+	upgrade.Timeout = constant.DefaultHelmTimeout
+	upgrade.PostRenderer = &labelPostRenderer{labels: map[string]string{constant.OpreqLabel: "true"}}
+	rel, err := upgrade.Run(name, chrt, values)
+	if err != nil {
+		return rel, errors.Wrapf(err, "failed to upgrade the Helm release %s/%s", namespace, name)
+	}
+	return rel, nil
+}
+
+// UninstallHelmRelease uninstalls the Helm release, the release which doesn't exist is ignored
+func (m *ODLMOperator) UninstallHelmRelease(namespace, name string) error {
+	cfg, err := m.HelmConfiguration(namespace)
+	if err != nil {
+		return err
+	}
+	uninstall := action.NewUninstall(cfg)
+	uninstall.Timeout = constant.DefaultHelmTimeout
+	if _, err := uninstall.Run(name); err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		return errors.Wrapf(err, "failed to uninstall the Helm release %s/%s", namespace, name)
+	}
+	return nil
+}
+
+// IsHelmReleaseOutdated checks if the release has to be upgraded to the chart and the values.
+// The failed release is upgraded to retry it.
+func IsHelmReleaseOutdated(rel *release.Release, chrt *chart.Chart, values map[string]interface{}) bool {
+	if rel.Info != nil && rel.Info.Status == release.StatusFailed {
+		return true
+	}
+	if rel.Chart == nil || rel.Chart.Metadata == nil {
+		return true
+	}
+	if rel.Chart.Metadata.Name != chrt.Metadata.Name || rel.Chart.Metadata.Version != chrt.Metadata.Version {
+		return true
+	}
+	if len(rel.Config) == 0 && len(values) == 0 {
+		return false
+	}
+	return !reflect.DeepEqual(rel.Config, values)
+}
+
+// IsManagedHelmRelease checks if the resources of the release are labeled by ODLM
+func IsManagedHelmRelease(rel *release.Release) bool {
+	for _, manifest := range releaseutil.SplitManifests(rel.Manifest) {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil || len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetLabels()[constant.OpreqLabel] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetHelmReleasePhase returns the phase of the operator installed by the release
+func GetHelmReleasePhase(rel *release.Release) apiv1alpha1.OperatorPhase {
+	if rel == nil || rel.Info == nil {
+		return apiv1alpha1.OperatorInstalling
+	}
+	switch rel.Info.Status {
+	case release.StatusDeployed:
+		return apiv1alpha1.OperatorRunning
+	case release.StatusFailed:
+		return apiv1alpha1.OperatorFailed
+	case release.StatusPendingUpgrade, release.StatusPendingRollback:
+		return apiv1alpha1.OperatorUpdating
+	default:
+		return apiv1alpha1.OperatorInstalling
+	}
+}
+
+// labelPostRenderer sets the labels on the resources rendered from the chart
+type labelPostRenderer struct {
+	labels map[string]string
+}
+
+func (p *labelPostRenderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	manifests := releaseutil.SplitManifests(renderedManifests.String())
+	keys := make([]string, 0, len(manifests))
+	for k := range manifests {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	out := &bytes.Buffer{}
+	for _, k := range keys {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(manifests[k]), &obj.Object); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the rendered manifest")
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for key, value := range p.labels {
+			labels[key] = value
+		}
+		obj.SetLabels(labels)
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal the rendered manifest")
+		}
+		out.WriteString("---\n")
+		out.Write(data)
+	}
+	return out, nil
+}
+
+// helmInstaller installs the operators as Helm releases
+type helmInstaller struct {
+	*ODLMOperator
+}
+
+// Install installs the operator as a Helm release, and upgrades the release when the chart version or the values change
+func (i *helmInstaller) Install(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) error {
+	namespace := i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	releaseName := GetHelmReleaseName(opt)
+	rel, err := i.GetHelmRelease(namespace, releaseName)
+	if err != nil {
+		return err
+	}
+	if rel != nil && !IsManagedHelmRelease(rel) {
+		// Helm release existing and not managed by OperandRequest controller
+		klog.V(1).Infof("Helm release %s in namespace %s isn't installed by ODLM. Ignore update/delete it.", releaseName, namespace)
+		return nil
+	}
+
+	chrt, err := i.GetHelmChart(ctx, opt, registryInstance.Namespace)
+	if err != nil {
+		requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorFailed, "")
+		return err
+	}
+	values, err := GetHelmValues(opt)
+	if err != nil {
+		requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorFailed, "")
+		return err
+	}
+
+	if rel == nil {
+		klog.V(2).Infof("Installing the Helm release %s/%s of the chart %s-%s", namespace, releaseName, chrt.Metadata.Name, chrt.Metadata.Version)
+		requestInstance.SetCreatingCondition(opt.Name, apiv1alpha1.ResourceTypeHelmRelease, corev1.ConditionTrue)
+		if rel, err = i.InstallHelmRelease(namespace, releaseName, chrt, values); err != nil {
+			requestInstance.SetCreatingCondition(opt.Name, apiv1alpha1.ResourceTypeHelmRelease, corev1.ConditionFalse)
+			requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorFailed, "")
+			return err
+		}
+	} else if IsHelmReleaseOutdated(rel, chrt, values) {
+		klog.V(2).Infof("Upgrading the Helm release %s/%s to the chart %s-%s", namespace, releaseName, chrt.Metadata.Name, chrt.Metadata.Version)
+		requestInstance.SetUpdatingCondition(opt.Name, apiv1alpha1.ResourceTypeHelmRelease, corev1.ConditionTrue)
+		if rel, err = i.UpgradeHelmRelease(namespace, releaseName, chrt, values); err != nil {
+			requestInstance.SetUpdatingCondition(opt.Name, apiv1alpha1.ResourceTypeHelmRelease, corev1.ConditionFalse)
+			requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorFailed, "")
+			return err
+		}
+	}
+	requestInstance.SetMemberStatus(opt.Name, GetHelmReleasePhase(rel), "")
+	return nil
+}
+
+// Uninstall uninstalls the Helm release of the operator, if it is installed by ODLM
+func (i *helmInstaller) Uninstall(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) error {
+	namespace := i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	releaseName := GetHelmReleaseName(opt)
+	rel, err := i.GetHelmRelease(namespace, releaseName)
+	if err != nil {
+		return err
+	}
+	if rel == nil {
+		klog.V(3).Infof("There is no Helm release %s in the namespace %s", releaseName, namespace)
+		return nil
+	}
+	if !IsManagedHelmRelease(rel) {
+		klog.V(2).Infof("Helm release %s in the namespace %s isn't installed by ODLM", releaseName, namespace)
+		return nil
+	}
+	notUninstall, err := i.hasNotUninstallLabel(ctx, rel)
+	if err != nil {
+		return err
+	}
+	if notUninstall {
+		klog.V(1).Infof("Helm release %s in the namespace %s has a resource with label %s. Skip the uninstall", releaseName, namespace, constant.NotUninstallLabel)
+		return nil
+	}
+
+	klog.V(2).Infof("Uninstalling the Helm release, Namespace: %s, Name: %s", namespace, releaseName)
+	requestInstance.SetDeletingCondition(opt.Name, apiv1alpha1.ResourceTypeHelmRelease, corev1.ConditionTrue)
+	if err := i.UninstallHelmRelease(namespace, releaseName); err != nil {
+		requestInstance.SetDeletingCondition(opt.Name, apiv1alpha1.ResourceTypeHelmRelease, corev1.ConditionFalse)
+		return err
+	}
+
+	klog.V(1).Infof("Helm release %s/%s is uninstalled", namespace, releaseName)
+	return nil
+}
+
+// hasNotUninstallLabel checks if any resource of the Helm release has the label which prevents the uninstall
+func (i *helmInstaller) hasNotUninstallLabel(ctx context.Context, rel *release.Release) (bool, error) {
+	for _, manifest := range releaseutil.SplitManifests(rel.Manifest) {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil || len(obj.Object) == 0 {
+			continue
+		}
+		namespace := obj.GetNamespace()
+		if namespace == "" {
+			namespace = rel.Namespace
+		}
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(obj.GroupVersionKind())
+		// The resources of the release are not in the cache of the Client, so they are read from the API server
+		if err := i.Reader.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: namespace}, existing); err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return false, errors.Wrapf(err, "failed to get the %s %s/%s of the Helm release %s", obj.GetKind(), namespace, obj.GetName(), rel.Name)
+		}
+		if existing.GetLabels()[constant.NotUninstallLabel] == "true" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Status returns the phase of the operator from the status of its Helm release
+func (i *helmInstaller) Status(ctx context.Context, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) (*InstallStatus, error) {
+	rel, err := i.GetHelmRelease(i.GetOperatorNamespace(opt.InstallMode, opt.Namespace), GetHelmReleaseName(opt))
+	if err != nil {
+		return nil, err
+	}
+	if rel == nil {
+		return &InstallStatus{}, nil
+	}
+	return &InstallStatus{Installed: true, Managed: IsManagedHelmRelease(rel), Phase: GetHelmReleasePhase(rel)}, nil
+}
+
+// Templates returns the alm-examples annotation of the chart in the Helm release of the operator,
+// in the same format as the alm-examples of a ClusterServiceVersion, or nil if the release is not installed yet
+func (i *helmInstaller) Templates(ctx context.Context, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) ([]unstructured.Unstructured, error) {
+	rel, err := i.GetHelmRelease(i.GetOperatorNamespace(opt.InstallMode, opt.Namespace), GetHelmReleaseName(opt))
+	if err != nil || rel == nil || rel.Chart == nil || rel.Chart.Metadata == nil {
+		return nil, err
+	}
+	almExamples := rel.Chart.Metadata.Annotations["alm-examples"]
+	if almExamples == "" {
+		klog.Warningf("Notfound alm-examples in the annotations of the chart %s in the Helm release %s/%s", rel.Chart.Metadata.Name, rel.Namespace, rel.Name)
+		return []unstructured.Unstructured{}, nil
+	}
+	templates, err := ParseTemplates(almExamples)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the alm-examples of the chart %s in the Helm release %s/%s", rel.Chart.Metadata.Name, rel.Namespace, rel.Name)
+	}
+	return templates, nil
+}
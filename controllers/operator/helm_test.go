@@ -0,0 +1,213 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
+)
+
+var _ = Describe("Installing the operators from Helm charts", func() {
+	const (
+		registryNamespace = "ibm-common-services"
+		operatorNamespace = "ibm-operators"
+	)
+
+	var (
+		ctx      context.Context
+		r        *helmInstaller
+		memory   *driver.Memory
+		registry *operatorv1alpha1.OperandRegistry
+		request  *operatorv1alpha1.OperandRequest
+		opt      *operatorv1alpha1.Operator
+	)
+
+	newInstaller := func(objs ...runtime.Object) *helmInstaller {
+		c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, objs...)
+		return &helmInstaller{&ODLMOperator{
+			Client: c,
+			Reader: c,
+			// The releases are stored in memory, and their resources are only printed
+			HelmConfiguration: func(namespace string) (*action.Configuration, error) {
+				memory.SetNamespace(namespace)
+				return &action.Configuration{
+					Releases:     storage.Init(memory),
+					KubeClient:   &kubefake.PrintingKubeClient{Out: ioutil.Discard},
+					Capabilities: chartutil.DefaultCapabilities,
+					Log:          func(string, ...interface{}) {},
+				}, nil
+			},
+		}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		memory = driver.NewMemory()
+		r = newInstaller()
+		registry = testutil.OperandRegistryObj("common-service", registryNamespace, operatorNamespace)
+		registry.Spec.Operators[1] = operatorv1alpha1.Operator{
+			Name:      "example",
+			Namespace: operatorNamespace,
+			Helm: &operatorv1alpha1.HelmChart{
+				ChartPath: testutil.HelmChartPath(),
+				Values:    &runtime.RawExtension{Raw: []byte(`{"replicaCount":2}`)},
+			},
+		}
+		registry.Default()
+		opt = registry.GetOperator("example")
+		request = testutil.OperandRequestObj("common-service", registryNamespace, "cloudpak", "cloudpak")
+	})
+
+	getRelease := func() *release.Release {
+		rel, err := r.GetHelmRelease(operatorNamespace, "example")
+		Expect(err).NotTo(HaveOccurred())
+		return rel
+	}
+
+	It("Should install the release with the values of the registry", func() {
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+
+		rel := getRelease()
+		Expect(rel).NotTo(BeNil())
+		Expect(rel.Info.Status).Should(Equal(release.StatusDeployed))
+		Expect(rel.Config).Should(Equal(map[string]interface{}{"replicaCount": float64(2)}))
+		Expect(rel.Manifest).Should(ContainSubstring("replicas: 2"))
+		Expect(rel.Manifest).Should(ContainSubstring(constant.OpreqLabel + `: "true"`))
+		Expect(IsManagedHelmRelease(rel)).Should(BeTrue())
+
+		Expect(request.Status.Members).Should(HaveLen(1))
+		Expect(request.Status.Members[0].Phase.OperatorPhase).Should(Equal(operatorv1alpha1.OperatorRunning))
+	})
+
+	It("Should only upgrade the release when the values change", func() {
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(getRelease().Version).Should(Equal(1))
+
+		opt.Helm.Values = &runtime.RawExtension{Raw: []byte(`{"replicaCount":3}`)}
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		rel := getRelease()
+		Expect(rel.Version).Should(Equal(2))
+		Expect(rel.Manifest).Should(ContainSubstring("replicas: 3"))
+	})
+
+	It("Should install the chart stored in a ConfigMap in the namespace of ODLM", func() {
+		os.Setenv("OPERATOR_NAMESPACE", registryNamespace)
+		defer os.Unsetenv("OPERATOR_NAMESPACE")
+		cm, err := testutil.HelmChartConfigMap("example-operator-chart", registryNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		r = newInstaller(cm)
+		opt.Helm.ChartPath = ""
+		opt.Helm.ChartConfigMap = &operatorv1alpha1.ChartConfigMapSelector{Name: "example-operator-chart"}
+
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		rel := getRelease()
+		Expect(rel).NotTo(BeNil())
+		Expect(rel.Chart.Metadata.Name).Should(Equal("example-operator"))
+	})
+
+	It("Should report the status of the release", func() {
+		status, err := r.Status(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Installed).Should(BeFalse())
+
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		status, err = r.Status(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Installed).Should(BeTrue())
+		Expect(status.Managed).Should(BeTrue())
+		Expect(status.Phase).Should(Equal(operatorv1alpha1.OperatorRunning))
+	})
+
+	It("Should return the alm-examples of the chart in the release", func() {
+		templates, err := r.Templates(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templates).Should(BeNil())
+
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		templates, err = r.Templates(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templates).Should(HaveLen(1))
+		Expect(templates[0].GetKind()).Should(Equal("Example"))
+		Expect(templates[0].GetAPIVersion()).Should(Equal("example.io/v1alpha1"))
+	})
+
+	It("Should uninstall the release installed by ODLM", func() {
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(r.Uninstall(ctx, request, registry, opt)).To(Succeed())
+		Expect(getRelease()).To(BeNil())
+	})
+
+	It("Should not uninstall the release whose resource has the label to prevent the uninstall", func() {
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		deploy := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: operatorNamespace,
+				Labels:    map[string]string{constant.OpreqLabel: "true", constant.NotUninstallLabel: "true"},
+			},
+		}
+		Expect(r.Client.Create(ctx, deploy)).To(Succeed())
+		Expect(r.Uninstall(ctx, request, registry, opt)).To(Succeed())
+		Expect(getRelease()).NotTo(BeNil())
+
+		deploy.Labels = map[string]string{constant.OpreqLabel: "true"}
+		Expect(r.Client.Update(ctx, deploy)).To(Succeed())
+		Expect(r.Uninstall(ctx, request, registry, opt)).To(Succeed())
+		Expect(getRelease()).To(BeNil())
+	})
+
+	It("Should neither upgrade nor uninstall the release not installed by ODLM", func() {
+		chrt, err := loader.Load(testutil.HelmChartPath())
+		Expect(err).NotTo(HaveOccurred())
+		cfg, err := r.HelmConfiguration(operatorNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		install := action.NewInstall(cfg)
+		install.ReleaseName = "example"
+		install.Namespace = operatorNamespace
+		_, err = install.Run(chrt, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(getRelease().Version).Should(Equal(1))
+		status, err := r.Status(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Managed).Should(BeFalse())
+		Expect(r.Uninstall(ctx, request, registry, opt)).To(Succeed())
+		Expect(getRelease()).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,70 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// Installer installs the operators of an OperandRegistry by one means, like OLM Subscriptions or Helm charts
+type Installer interface {
+	// Install installs the operator, or updates the operator installed by ODLM to the OperandRegistry.
+	// The progress is reported in the member status of the OperandRequest.
+	Install(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) error
+	// Uninstall uninstalls the operator if it is installed by ODLM
+	Uninstall(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) error
+	// Status returns the status of the operator
+	Status(ctx context.Context, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) (*InstallStatus, error)
+	// Templates returns the templates of the custom resources the operator offers,
+	// it returns nil if the templates are not available, like when the operator isn't installed
+	Templates(ctx context.Context, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) ([]unstructured.Unstructured, error)
+}
+
+// InstallStatus is the status of an operator
+type InstallStatus struct {
+	// Installed is true when the operator is installed, by ODLM or not
+	Installed bool
+	// Managed is true when the operator is installed by ODLM
+	Managed bool
+	// Phase is the phase of the operator
+	Phase apiv1alpha1.OperatorPhase
+}
+
+// InstallerGetter returns the Installer of the operator in the OperandRegistry
+type InstallerGetter func(registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) Installer
+
+// GetInstaller returns the Installer of the operator in the OperandRegistry. The operators with a Helm chart or
+// manifests are installed from them, and the other operators are installed by the OLM generation of the OperandRegistry.
+func (m *ODLMOperator) GetInstaller(registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) Installer {
+	if m.Installers != nil {
+		return m.Installers(registryInstance, opt)
+	}
+	switch {
+	case opt.Helm != nil:
+		return &helmInstaller{m}
+	case opt.GetManifests() != nil:
+		return &manifestsInstaller{m}
+	case registryInstance.Spec.InstallerGeneration == apiv1alpha1.InstallerGenerationV1:
+		return &olmv1Installer{m}
+	default:
+		return &olmInstaller{m}
+	}
+}
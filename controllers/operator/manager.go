@@ -18,10 +18,14 @@ package operator
 
 import (
 	"context"
+	"net/http"
 
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
@@ -32,23 +36,42 @@ import (
 
 	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
 	constant "github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	util "github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
 )
 
 // ODLMOperator is the struct for ODLM controllers
 type ODLMOperator struct {
 	client.Client
+	// Reader reads the objects which are not in the cache of the Client
+	Reader client.Reader
 	*rest.Config
-	Recorder record.EventRecorder
-	Scheme   *runtime.Scheme
+	Recorder   record.EventRecorder
+	Scheme     *runtime.Scheme
+	RESTMapper meta.RESTMapper
+	// HelmConfiguration returns the configuration of the Helm actions for the operators installed from Helm charts
+	HelmConfiguration HelmConfigurationGetter
+	// CatalogClient fetches the file-based catalogs served by the ClusterCatalogs of OLM v1
+	CatalogClient *http.Client
+	// Installers overrides the Installers of the operators, like with a fake Installer in the tests
+	Installers InstallerGetter
 }
 
 // NewODLMOperator is the method to initialize an Operator struct
 func NewODLMOperator(mgr manager.Manager, name string) *ODLMOperator {
+	catalogClient, err := NewCatalogClient(util.GetCatalogCAFile())
+	if err != nil {
+		klog.Errorf("failed to load the CA of the ClusterCatalogs, only the system roots are trusted: %v", err)
+		catalogClient, _ = NewCatalogClient("")
+	}
 	return &ODLMOperator{
-		Client:   mgr.GetClient(),
-		Config:   mgr.GetConfig(),
-		Recorder: mgr.GetEventRecorderFor(name),
-		Scheme:   mgr.GetScheme(),
+		Client:            mgr.GetClient(),
+		Reader:            mgr.GetAPIReader(),
+		Config:            mgr.GetConfig(),
+		Recorder:          mgr.GetEventRecorderFor(name),
+		Scheme:            mgr.GetScheme(),
+		RESTMapper:        mgr.GetRESTMapper(),
+		HelmConfiguration: NewHelmConfigurationGetter(mgr.GetConfig()),
+		CatalogClient:     catalogClient,
 	}
 }
 
@@ -58,17 +81,9 @@ func (m *ODLMOperator) GetOperandRegistry(ctx context.Context, key types.Namespa
 	if err := m.Client.Get(ctx, key, reg); err != nil {
 		return nil, err
 	}
-	for i, o := range reg.Spec.Operators {
-		if o.Scope == "" {
-			reg.Spec.Operators[i].Scope = apiv1alpha1.ScopePrivate
-		}
-		if o.InstallMode == "" {
-			reg.Spec.Operators[i].InstallMode = apiv1alpha1.InstallModeNamespace
-		}
-		if o.InstallPlanApproval == "" {
-			reg.Spec.Operators[i].InstallPlanApproval = olmv1alpha1.ApprovalAutomatic
-		}
-	}
+	// The defaults are persisted by the mutating webhook,
+	// they are still set here for the instances created before it.
+	reg.Default()
 	return reg, nil
 }
 
@@ -81,6 +96,20 @@ func (m *ODLMOperator) GetOperandConfig(ctx context.Context, key types.Namespace
 	return config, nil
 }
 
+// GetTemplateParameters gets the data of the template parameters ConfigMap in the ODLM namespace
+func (m *ODLMOperator) GetTemplateParameters(ctx context.Context) (map[string]string, error) {
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: constant.TemplateParametersConfigMap, Namespace: util.GetOperatorNamespace()}
+	// The ConfigMaps in the cache are filtered by label, so the ConfigMap is read from the API server
+	if err := m.Reader.Get(ctx, key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return map[string]string{}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the template parameters ConfigMap %s", key.String())
+	}
+	return cm.Data, nil
+}
+
 // GetOperandRequest gets OperandRequest
 func (m *ODLMOperator) GetOperandRequest(ctx context.Context, key types.NamespacedName) (*apiv1alpha1.OperandRequest, error) {
 	req := &apiv1alpha1.OperandRequest{}
@@ -88,11 +117,7 @@ func (m *ODLMOperator) GetOperandRequest(ctx context.Context, key types.Namespac
 		return nil, err
 	}
 	// Set default value for the OperandRequest
-	for i, r := range req.Spec.Requests {
-		if r.RegistryNamespace == "" {
-			req.Spec.Requests[i].RegistryNamespace = req.GetNamespace()
-		}
-	}
+	req.Default()
 	return req, nil
 }
 
@@ -110,12 +135,8 @@ func (m *ODLMOperator) ListOperandRequests(ctx context.Context, label map[string
 		return nil, err
 	}
 	// Set default value for all the OperandRequest
-	for i, item := range requestList.Items {
-		for j, r := range item.Spec.Requests {
-			if r.RegistryNamespace == "" {
-				requestList.Items[i].Spec.Requests[j].RegistryNamespace = item.GetNamespace()
-			}
-		}
+	for i := range requestList.Items {
+		requestList.Items[i].Default()
 	}
 	return requestList, nil
 }
@@ -129,10 +150,8 @@ func (m *ODLMOperator) ListOperandRequestsByRegistry(ctx context.Context, key ty
 	}
 	// Set default value for all the OperandRequest
 	for _, item := range requestCandidates.Items {
+		item.Default()
 		for _, r := range item.Spec.Requests {
-			if r.RegistryNamespace == "" {
-				r.RegistryNamespace = item.GetNamespace()
-			}
 			if r.Registry == key.Name && r.RegistryNamespace == key.Namespace {
 				requestList = append(requestList, item)
 			}
@@ -150,10 +169,8 @@ func (m *ODLMOperator) ListOperandRequestsByConfig(ctx context.Context, key type
 	}
 	// Set default value for all the OperandRequest
 	for _, item := range requestCandidates.Items {
+		item.Default()
 		for _, r := range item.Spec.Requests {
-			if r.RegistryNamespace == "" {
-				r.RegistryNamespace = item.GetNamespace()
-			}
 			if r.Registry == key.Name && r.RegistryNamespace == key.Namespace {
 				requestList = append(requestList, item)
 			}
@@ -246,3 +263,28 @@ func (m *ODLMOperator) GetOperatorNamespace(installMode, namespace string) strin
 	}
 	return namespace
 }
+
+// GetCustomResourcePhase computes the phase of the custom resource from its status,
+// by the readiness expression of the operator if it is set, otherwise by the conditions in the status.
+// It returns the phase and a message describing why the custom resource isn't running.
+func GetCustomResourcePhase(cr *unstructured.Unstructured, readiness *apiv1alpha1.ReadinessExpression) (apiv1alpha1.ServicePhase, string) {
+	var health util.Health
+	var message string
+	if readiness != nil {
+		var err error
+		health, message, err = util.CheckHealthByExpression(cr, readiness.JSONPath, readiness.ReadyValues, readiness.FailedValues)
+		if err != nil {
+			return apiv1alpha1.ServiceFailed, err.Error()
+		}
+	} else {
+		health, message = util.CheckHealth(cr)
+	}
+	switch health {
+	case util.HealthReady:
+		return apiv1alpha1.ServiceRunning, ""
+	case util.HealthFailed:
+		return apiv1alpha1.ServiceFailed, message
+	default:
+		return apiv1alpha1.ServiceCreating, message
+	}
+}
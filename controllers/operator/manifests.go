@@ -0,0 +1,445 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	constant "github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	util "github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+)
+
+// GetManifestObjects loads the manifests of the operator from its path or from the ConfigMap in the namespace of the OperandRegistry.
+// The namespaced objects are moved into the namespace of the operator, and the objects are sorted in the install order of their kinds.
+func (m *ODLMOperator) GetManifestObjects(ctx context.Context, opt *apiv1alpha1.Operator, registryNamespace, namespace string) ([]*unstructured.Unstructured, error) {
+	manifests, err := m.getManifests(ctx, opt, registryNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []*unstructured.Unstructured
+	for _, manifest := range manifests {
+		decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest.data), 4096)
+		for {
+			obj := &unstructured.Unstructured{}
+			if err := decoder.Decode(&obj.Object); err != nil {
+				if err == io.EOF {
+					break
+				}
+				return nil, errors.Wrapf(err, "failed to parse the manifest %s of the operator %s", manifest.name, opt.Name)
+			}
+			if len(obj.Object) == 0 {
+				continue
+			}
+			if obj.GetKind() == "" || obj.GetName() == "" {
+				return nil, errors.Errorf("the object in the manifest %s of the operator %s has no kind or name", manifest.name, opt.Name)
+			}
+			if err := m.setManifestNamespace(obj, namespace); err != nil {
+				return nil, err
+			}
+			ensureManifestLabel(obj)
+			objs = append(objs, obj)
+		}
+	}
+	SortManifestObjects(objs, releaseutil.InstallOrder)
+	return objs, nil
+}
+
+type manifest struct {
+	name string
+	data string
+}
+
+// getManifests reads the manifest files in the path in the order of their names, or the data of the ConfigMap in the order of their keys
+func (m *ODLMOperator) getManifests(ctx context.Context, opt *apiv1alpha1.Operator, registryNamespace string) ([]manifest, error) {
+	source := opt.GetManifests()
+	if source == nil {
+		return nil, errors.Errorf("the operator %s isn't installed from manifests", opt.Name)
+	}
+
+	var manifests []manifest
+	if source.Path != "" {
+		files, err := ioutil.ReadDir(source.Path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the manifests directory %s of the operator %s", source.Path, opt.Name)
+		}
+		for _, file := range files {
+			ext := filepath.Ext(file.Name())
+			if file.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+				continue
+			}
+			data, err := ioutil.ReadFile(filepath.Join(source.Path, file.Name()))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read the manifest %s of the operator %s", file.Name(), opt.Name)
+			}
+			manifests = append(manifests, manifest{name: file.Name(), data: string(data)})
+		}
+		return manifests, nil
+	}
+	if source.ConfigMap == "" {
+		return nil, errors.Errorf("neither the path nor the configMap of the manifests of the operator %s is set", opt.Name)
+	}
+	// The manifests are applied with the permissions of ODLM, so only the cluster administrators can provide them
+	if !util.IsOperatorNamespace(registryNamespace) {
+		return nil, errors.Errorf("the manifests of the operator %s can only be read from a ConfigMap when the OperandRegistry is in the namespace of ODLM", opt.Name)
+	}
+
+	cm := &corev1.ConfigMap{}
+	cmKey := types.NamespacedName{Name: source.ConfigMap, Namespace: registryNamespace}
+	// The ConfigMaps in the cache are filtered by label, so the ConfigMap is read from the API server
+	if err := m.Reader.Get(ctx, cmKey, cm); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the manifests ConfigMap %s of the operator %s", cmKey.String(), opt.Name)
+	}
+	keys := make([]string, 0, len(cm.Data))
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		manifests = append(manifests, manifest{name: cmKey.String() + "/" + key, data: cm.Data[key]})
+	}
+	return manifests, nil
+}
+
+// setManifestNamespace moves the namespaced object into the namespace, and clears the namespace of the cluster scoped object.
+// The ServiceAccount subjects of the bindings without a namespace are set in the namespace as well.
+func (m *ODLMOperator) setManifestNamespace(obj *unstructured.Unstructured, namespace string) error {
+	gvk := obj.GroupVersionKind()
+	mapping, err := m.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the REST mapping of %s", gvk.String())
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		obj.SetNamespace("")
+	} else {
+		obj.SetNamespace(namespace)
+	}
+
+	if gvk.Group != "rbac.authorization.k8s.io" || (gvk.Kind != "RoleBinding" && gvk.Kind != "ClusterRoleBinding") {
+		return nil
+	}
+	subjects, _, err := unstructured.NestedSlice(obj.Object, "subjects")
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the subjects of the %s %s", gvk.Kind, obj.GetName())
+	}
+	for _, s := range subjects {
+		subject, ok := s.(map[string]interface{})
+		if !ok || subject["kind"] != "ServiceAccount" {
+			continue
+		}
+		if ns, _ := subject["namespace"].(string); ns == "" {
+			subject["namespace"] = namespace
+		}
+	}
+	if subjects != nil {
+		return unstructured.SetNestedSlice(obj.Object, subjects, "subjects")
+	}
+	return nil
+}
+
+func ensureManifestLabel(obj *unstructured.Unstructured) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[constant.OpreqLabel] = "true"
+	obj.SetLabels(labels)
+}
+
+// SortManifestObjects sorts the objects by their kinds in the order, the objects of the other kinds are at the end
+func SortManifestObjects(objs []*unstructured.Unstructured, order releaseutil.KindSortOrder) {
+	rank := make(map[string]int, len(order))
+	for i, kind := range order {
+		rank[kind] = i
+	}
+	kindRank := func(kind string) int {
+		if r, ok := rank[kind]; ok {
+			return r
+		}
+		return len(order)
+	}
+	sort.SliceStable(objs, func(i, j int) bool {
+		return kindRank(objs[i].GetKind()) < kindRank(objs[j].GetKind())
+	})
+}
+
+// getManifestObject gets the existing object of the manifest, it returns nil if the object doesn't exist
+func (m *ODLMOperator) getManifestObject(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := m.Client.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, existing); err != nil {
+		// The object whose kind isn't served, like a custom resource before its CRD, doesn't exist
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the %s %s", obj.GetKind(), manifestObjectKey(obj))
+	}
+	return existing, nil
+}
+
+// ApplyManifestObject creates or updates the object with server-side apply.
+// The existing object which isn't labeled by ODLM is left as it is, and false is returned.
+func (m *ODLMOperator) ApplyManifestObject(ctx context.Context, obj *unstructured.Unstructured) (bool, error) {
+	existing, err := m.getManifestObject(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil && existing.GetLabels()[constant.OpreqLabel] != "true" {
+		klog.V(1).Infof("%s %s isn't created by ODLM. Ignore update/delete it.", obj.GetKind(), manifestObjectKey(obj))
+		return false, nil
+	}
+	if err := m.Client.Patch(ctx, obj.DeepCopy(), client.Apply, client.FieldOwner(constant.FieldManager), client.ForceOwnership); err != nil {
+		return false, errors.Wrapf(err, "failed to apply the %s %s", obj.GetKind(), manifestObjectKey(obj))
+	}
+	return true, nil
+}
+
+// DeleteManifestObject deletes the object labeled by ODLM.
+// The object which doesn't exist or isn't labeled by ODLM is ignored, and false is returned.
+func (m *ODLMOperator) DeleteManifestObject(ctx context.Context, obj *unstructured.Unstructured) (bool, error) {
+	existing, err := m.getManifestObject(ctx, obj)
+	if err != nil || existing == nil {
+		return false, err
+	}
+	if existing.GetLabels()[constant.OpreqLabel] != "true" {
+		klog.V(2).Infof("%s %s isn't created by ODLM", obj.GetKind(), manifestObjectKey(obj))
+		return false, nil
+	}
+	if err := m.Client.Delete(ctx, existing); err != nil && !apierrors.IsNotFound(err) {
+		return false, errors.Wrapf(err, "failed to delete the %s %s", obj.GetKind(), manifestObjectKey(obj))
+	}
+	return true, nil
+}
+
+// GetManifestsPhase returns the phase of the operator from the Deployments in the manifests, and whether any object applied by ODLM exists.
+// The operator is running when all the Deployments are available, and failed when any of them exceeds its progress deadline.
+func (m *ODLMOperator) GetManifestsPhase(ctx context.Context, objs []*unstructured.Unstructured) (apiv1alpha1.OperatorPhase, bool, error) {
+	phase := apiv1alpha1.OperatorRunning
+	installed := false
+	for _, obj := range objs {
+		if obj.GroupVersionKind().GroupKind() != appsv1.SchemeGroupVersion.WithKind("Deployment").GroupKind() {
+			existing, err := m.getManifestObject(ctx, obj)
+			if err != nil {
+				return "", false, err
+			}
+			if existing != nil && existing.GetLabels()[constant.OpreqLabel] == "true" {
+				installed = true
+			}
+			continue
+		}
+		deploy := &appsv1.Deployment{}
+		// The Deployments are not in the cache of the Client, so they are read from the API server
+		if err := m.Reader.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, deploy); err != nil {
+			if apierrors.IsNotFound(err) {
+				if phase != apiv1alpha1.OperatorFailed {
+					phase = apiv1alpha1.OperatorInstalling
+				}
+				continue
+			}
+			return "", false, errors.Wrapf(err, "failed to get the Deployment %s", manifestObjectKey(obj))
+		}
+		if deploy.Labels[constant.OpreqLabel] == "true" {
+			installed = true
+		}
+		switch getDeploymentPhase(deploy) {
+		case apiv1alpha1.OperatorFailed:
+			phase = apiv1alpha1.OperatorFailed
+		case apiv1alpha1.OperatorInstalling:
+			if phase != apiv1alpha1.OperatorFailed {
+				phase = apiv1alpha1.OperatorInstalling
+			}
+		}
+	}
+	return phase, installed, nil
+}
+
+func getDeploymentPhase(deploy *appsv1.Deployment) apiv1alpha1.OperatorPhase {
+	if deploy.Status.ObservedGeneration < deploy.Generation {
+		return apiv1alpha1.OperatorInstalling
+	}
+	available := false
+	for _, c := range deploy.Status.Conditions {
+		switch {
+		case c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse && c.Reason == "ProgressDeadlineExceeded":
+			return apiv1alpha1.OperatorFailed
+		case c.Type == appsv1.DeploymentAvailable && c.Status == corev1.ConditionTrue:
+			available = true
+		}
+	}
+	if available {
+		return apiv1alpha1.OperatorRunning
+	}
+	return apiv1alpha1.OperatorInstalling
+}
+
+func manifestObjectKey(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// The manifests and the Helm charts of the operators usually carry their CRDs and cluster-wide RBAC,
+// so ODLM needs to manage them, and to escalate and bind the roles it grants to the operators.
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;roles,verbs=create;delete;get;list;patch;update;watch;escalate;bind
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings;rolebindings,verbs=create;delete;get;list;patch;update;watch
+
+// manifestsInstaller installs the operators by applying their manifests
+type manifestsInstaller struct {
+	*ODLMOperator
+}
+
+// Install applies the manifests of the operator into its namespace, and sets the member phase from its Deployments
+func (i *manifestsInstaller) Install(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) error {
+	namespace := i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	objs, err := i.GetManifestObjects(ctx, opt, registryInstance.Namespace, namespace)
+	if err != nil {
+		requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorFailed, "")
+		return err
+	}
+
+	klog.V(2).Infof("Applying the manifests of the operator %s in the namespace %s", opt.Name, namespace)
+	for _, obj := range objs {
+		if _, err := i.ApplyManifestObject(ctx, obj); err != nil {
+			requestInstance.SetUpdatingCondition(opt.Name, apiv1alpha1.ResourceTypeManifests, corev1.ConditionFalse)
+			requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorFailed, "")
+			return err
+		}
+	}
+
+	phase, _, err := i.GetManifestsPhase(ctx, objs)
+	if err != nil {
+		return err
+	}
+	requestInstance.SetMemberStatus(opt.Name, phase, "")
+	return nil
+}
+
+// Uninstall deletes the objects in the manifests of the operator which are created by ODLM, in the uninstall order of their kinds
+func (i *manifestsInstaller) Uninstall(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) error {
+	namespace := i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	objs, err := i.GetManifestObjects(ctx, opt, registryInstance.Namespace, namespace)
+	if err != nil {
+		return err
+	}
+	SortManifestObjects(objs, releaseutil.UninstallOrder)
+
+	notUninstall, err := i.hasNotUninstallLabel(ctx, objs)
+	if err != nil {
+		return err
+	}
+	if notUninstall {
+		klog.V(1).Infof("Operator %s has an object with label %s in its manifests. Skip the uninstall", opt.Name, constant.NotUninstallLabel)
+		return nil
+	}
+
+	klog.V(2).Infof("Deleting the manifests of the operator %s in the namespace %s", opt.Name, namespace)
+	requestInstance.SetDeletingCondition(opt.Name, apiv1alpha1.ResourceTypeManifests, corev1.ConditionTrue)
+	merr := &util.MultiErr{}
+	for _, obj := range objs {
+		if _, err := i.DeleteManifestObject(ctx, obj); err != nil {
+			merr.Add(err)
+		}
+	}
+	if len(merr.Errors) != 0 {
+		requestInstance.SetDeletingCondition(opt.Name, apiv1alpha1.ResourceTypeManifests, corev1.ConditionFalse)
+		return merr
+	}
+
+	klog.V(1).Infof("The manifests of the operator %s are deleted", opt.Name)
+	return nil
+}
+
+// hasNotUninstallLabel checks if any existing object in the manifests has the label which prevents the uninstall
+func (i *manifestsInstaller) hasNotUninstallLabel(ctx context.Context, objs []*unstructured.Unstructured) (bool, error) {
+	for _, obj := range objs {
+		existing, err := i.getManifestObject(ctx, obj)
+		if err != nil {
+			return false, err
+		}
+		if existing != nil && existing.GetLabels()[constant.NotUninstallLabel] == "true" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Status returns the phase of the operator from the Deployments in its manifests.
+// The operator is installed when any object applied by ODLM exists, and it is always managed by ODLM,
+// since the objects which aren't created by ODLM are left as they are.
+func (i *manifestsInstaller) Status(ctx context.Context, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) (*InstallStatus, error) {
+	objs, err := i.GetManifestObjects(ctx, opt, registryInstance.Namespace, i.GetOperatorNamespace(opt.InstallMode, opt.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	phase, installed, err := i.GetManifestsPhase(ctx, objs)
+	if err != nil {
+		return nil, err
+	}
+	if !installed {
+		return &InstallStatus{}, nil
+	}
+	return &InstallStatus{Installed: true, Managed: true, Phase: phase}, nil
+}
+
+// Templates returns the alm-examples annotations of the CustomResourceDefinitions in the manifests of the operator,
+// each one in the same format as the alm-examples of a ClusterServiceVersion
+func (i *manifestsInstaller) Templates(ctx context.Context, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) ([]unstructured.Unstructured, error) {
+	objs, err := i.GetManifestObjects(ctx, opt, registryInstance.Namespace, i.GetOperatorNamespace(opt.InstallMode, opt.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	templates := []unstructured.Unstructured{}
+	for _, obj := range objs {
+		if obj.GroupVersionKind().GroupKind() != (schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}) {
+			continue
+		}
+		almExamples := obj.GetAnnotations()["alm-examples"]
+		if almExamples == "" {
+			continue
+		}
+		crdTemplates, err := ParseTemplates(almExamples)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the alm-examples of the CustomResourceDefinition %s of the operator %s", obj.GetName(), opt.Name)
+		}
+		templates = append(templates, crdTemplates...)
+	}
+	if len(templates) == 0 {
+		klog.Warningf("Notfound alm-examples in the CustomResourceDefinitions of the manifests of the operator %s", opt.Name)
+	}
+	return templates, nil
+}
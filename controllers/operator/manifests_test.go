@@ -0,0 +1,260 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
+)
+
+var _ = Describe("Installing the operators from manifests", func() {
+	const (
+		registryNamespace = "ibm-common-services"
+		operatorNamespace = "ibm-operators"
+	)
+
+	var (
+		ctx      context.Context
+		r        *manifestsInstaller
+		registry *operatorv1alpha1.OperandRegistry
+		request  *operatorv1alpha1.OperandRequest
+		opt      *operatorv1alpha1.Operator
+	)
+
+	newInstaller := func(objs ...runtime.Object) *manifestsInstaller {
+		c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, objs...)
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ServiceAccount"}, meta.RESTScopeNamespace)
+		mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+		mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+		mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"}, meta.RESTScopeRoot)
+		mapper.Add(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}, meta.RESTScopeRoot)
+		return &manifestsInstaller{&ODLMOperator{
+			Client:     &applyClient{Client: c},
+			Reader:     c,
+			RESTMapper: mapper,
+		}}
+	}
+
+	getDeployment := func() *appsv1.Deployment {
+		deployment := &appsv1.Deployment{}
+		Expect(r.Reader.Get(ctx, types.NamespacedName{Name: "example-operator", Namespace: operatorNamespace}, deployment)).To(Succeed())
+		return deployment
+	}
+
+	setDeploymentCondition := func(condition appsv1.DeploymentCondition) {
+		deployment := getDeployment()
+		deployment.Status.Conditions = []appsv1.DeploymentCondition{condition}
+		Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		r = newInstaller()
+		registry = testutil.OperandRegistryObj("common-service", registryNamespace, operatorNamespace)
+		registry.Spec.Operators[1] = operatorv1alpha1.Operator{
+			Name:      "example",
+			Namespace: operatorNamespace,
+			Source: &operatorv1alpha1.OperatorSource{
+				Manifests: &operatorv1alpha1.ManifestsSource{Path: testutil.ManifestsPath()},
+			},
+		}
+		registry.Default()
+		opt = registry.GetOperator("example")
+		request = testutil.OperandRequestObj("common-service", registryNamespace, "cloudpak", "cloudpak")
+	})
+
+	It("Should load the manifests in the install order into the operator namespace", func() {
+		objs, err := r.GetManifestObjects(ctx, opt, registryNamespace, operatorNamespace)
+		Expect(err).NotTo(HaveOccurred())
+
+		var kinds []string
+		for _, obj := range objs {
+			kinds = append(kinds, obj.GetKind())
+			Expect(obj.GetLabels()).Should(HaveKeyWithValue(constant.OpreqLabel, "true"))
+			switch obj.GetKind() {
+			case "ServiceAccount", "Deployment":
+				Expect(obj.GetNamespace()).Should(Equal(operatorNamespace))
+			default:
+				Expect(obj.GetNamespace()).Should(BeEmpty())
+			}
+		}
+		Expect(kinds).Should(Equal([]string{"ServiceAccount", "CustomResourceDefinition", "ClusterRole", "ClusterRoleBinding", "Deployment"}))
+
+		subjects, _, err := unstructured.NestedSlice(objs[3].Object, "subjects")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subjects[0]).Should(HaveKeyWithValue("namespace", operatorNamespace))
+	})
+
+	It("Should apply the manifests and follow the Available condition of the Deployment", func() {
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(getDeployment().Labels).Should(HaveKeyWithValue(constant.OpreqLabel, "true"))
+		binding := &rbacv1.ClusterRoleBinding{}
+		Expect(r.Reader.Get(ctx, types.NamespacedName{Name: "example-operator"}, binding)).To(Succeed())
+		Expect(request.Status.Members[0].Phase.OperatorPhase).Should(Equal(operatorv1alpha1.OperatorInstalling))
+
+		setDeploymentCondition(appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue})
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(request.Status.Members[0].Phase.OperatorPhase).Should(Equal(operatorv1alpha1.OperatorRunning))
+
+		setDeploymentCondition(appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"})
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(request.Status.Members[0].Phase.OperatorPhase).Should(Equal(operatorv1alpha1.OperatorFailed))
+	})
+
+	It("Should apply the manifests stored in a ConfigMap in the namespace of ODLM", func() {
+		os.Setenv("OPERATOR_NAMESPACE", registryNamespace)
+		defer os.Unsetenv("OPERATOR_NAMESPACE")
+		cm, err := testutil.ManifestsConfigMap("example-operator-manifests", registryNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		r = newInstaller(cm)
+		opt.Source.Manifests = &operatorv1alpha1.ManifestsSource{ConfigMap: "example-operator-manifests"}
+
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		getDeployment()
+	})
+
+	It("Should not read the manifests from a ConfigMap outside the namespace of ODLM", func() {
+		os.Setenv("OPERATOR_NAMESPACE", "ibm-odlm")
+		defer os.Unsetenv("OPERATOR_NAMESPACE")
+		cm, err := testutil.ManifestsConfigMap("example-operator-manifests", registryNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		r = newInstaller(cm)
+		opt.Source.Manifests = &operatorv1alpha1.ManifestsSource{ConfigMap: "example-operator-manifests"}
+
+		_, err = r.GetManifestObjects(ctx, opt, registryNamespace, operatorNamespace)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("can only be read from a ConfigMap when the OperandRegistry is in the namespace of ODLM"))
+	})
+
+	It("Should return the alm-examples of the CustomResourceDefinitions", func() {
+		templates, err := r.Templates(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templates).Should(HaveLen(1))
+		Expect(templates[0].GetKind()).Should(Equal("Example"))
+		Expect(templates[0].GetAPIVersion()).Should(Equal("example.com/v1"))
+	})
+
+	It("Should report the status of the Deployment", func() {
+		status, err := r.Status(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Installed).Should(BeFalse())
+
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		setDeploymentCondition(appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue})
+		status, err = r.Status(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Installed).Should(BeTrue())
+		Expect(status.Phase).Should(Equal(operatorv1alpha1.OperatorRunning))
+	})
+
+	It("Should report the operator without Deployments as installed from its other objects", func() {
+		objs, err := r.GetManifestObjects(ctx, opt, registryNamespace, operatorNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		var withoutDeployment []*unstructured.Unstructured
+		for _, obj := range objs {
+			if obj.GetKind() != "Deployment" {
+				withoutDeployment = append(withoutDeployment, obj)
+			}
+		}
+
+		phase, installed, err := r.GetManifestsPhase(ctx, withoutDeployment)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(installed).Should(BeFalse())
+
+		for _, obj := range withoutDeployment {
+			_, err := r.ApplyManifestObject(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		phase, installed, err = r.GetManifestsPhase(ctx, withoutDeployment)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(installed).Should(BeTrue())
+		Expect(phase).Should(Equal(operatorv1alpha1.OperatorRunning))
+	})
+
+	It("Should only delete the objects created by ODLM", func() {
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		sa := &corev1.ServiceAccount{}
+		Expect(r.Reader.Get(ctx, types.NamespacedName{Name: "example-operator", Namespace: operatorNamespace}, sa)).To(Succeed())
+		sa.Labels = nil
+		Expect(r.Client.Update(ctx, sa)).To(Succeed())
+
+		Expect(r.Uninstall(ctx, request, registry, opt)).To(Succeed())
+		err := r.Reader.Get(ctx, types.NamespacedName{Name: "example-operator", Namespace: operatorNamespace}, &appsv1.Deployment{})
+		Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+		Expect(r.Reader.Get(ctx, types.NamespacedName{Name: "example-operator", Namespace: operatorNamespace}, &corev1.ServiceAccount{})).To(Succeed())
+	})
+
+	It("Should keep the manifests when any object has the do-not-uninstall label", func() {
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		role := &rbacv1.ClusterRole{}
+		Expect(r.Reader.Get(ctx, types.NamespacedName{Name: "example-operator"}, role)).To(Succeed())
+		role.Labels[constant.NotUninstallLabel] = "true"
+		Expect(r.Client.Update(ctx, role)).To(Succeed())
+
+		Expect(r.Uninstall(ctx, request, registry, opt)).To(Succeed())
+		getDeployment()
+		status, err := r.Status(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Installed).Should(BeTrue())
+	})
+})
+
+// applyClient creates or updates the objects of the server-side apply patches, which the fake client doesn't support
+type applyClient struct {
+	client.Client
+}
+
+func (c *applyClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch != client.Apply {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	u := obj.(*unstructured.Unstructured)
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(u.GroupVersionKind())
+	if err := c.Client.Get(ctx, types.NamespacedName{Name: u.GetName(), Namespace: u.GetNamespace()}, existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return c.Client.Create(ctx, u)
+		}
+		return err
+	}
+	u.SetResourceVersion(existing.GetResourceVersion())
+	// The status isn't changed by the apply patches
+	if status, ok := existing.Object["status"]; ok {
+		u.Object["status"] = status
+	}
+	return c.Client.Update(ctx, u)
+}
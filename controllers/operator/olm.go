@@ -0,0 +1,551 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/blang/semver"
+	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	constant "github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	util "github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+)
+
+// olmInstaller installs the operators by OLM Subscriptions, and offers the alm-examples of their ClusterServiceVersions as the templates
+type olmInstaller struct {
+	*ODLMOperator
+}
+
+type clusterObjects struct {
+	namespace     *corev1.Namespace
+	operatorGroup *olmv1.OperatorGroup
+	subscription  *olmv1alpha1.Subscription
+}
+
+// Install creates the Subscription of the operator, or updates the Subscription created by ODLM to the OperandRegistry
+func (i *olmInstaller) Install(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) error {
+	// Check subscription if exist
+	namespace := i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	sub, err := i.GetSubscription(ctx, opt.Name, namespace, opt.PackageName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Subscription does not exist, create a new one
+			if err = i.createSubscription(ctx, requestInstance, opt); err != nil {
+				requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorFailed, "")
+				return err
+			}
+			requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorInstalling, "")
+			return nil
+		}
+		return err
+	}
+
+	if _, ok := sub.Labels[constant.OpreqLabel]; !ok {
+		// Subscription existing and not managed by OperandRequest controller
+		klog.V(1).Infof("Subscription %s in namespace %s isn't created by ODLM. Ignore update/delete it.", sub.Name, sub.Namespace)
+		return nil
+	}
+
+	// Subscription existing and managed by OperandRequest controller
+	// Subscription channel changed, update it.
+	if compareSub(sub.Spec, opt) {
+		sub.Spec.CatalogSource = opt.SourceName
+		sub.Spec.Channel = opt.Channel
+		sub.Spec.CatalogSourceNamespace = opt.SourceNamespace
+		sub.Spec.Package = opt.PackageName
+		if approval := getInstallPlanApproval(opt); approval != "" && sub.Spec.InstallPlanApproval != approval {
+			sub.Spec.InstallPlanApproval = approval
+		}
+		if opt.SubscriptionConfig != nil {
+			sub.Spec.Config = *opt.SubscriptionConfig.DeepCopy()
+		}
+		if err = i.updateSubscription(ctx, requestInstance, sub); err != nil {
+			requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorFailed, "")
+			return err
+		}
+		requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorUpdating, "")
+	}
+	return i.approveInstallPlan(ctx, requestInstance, registryInstance, opt, sub)
+}
+
+// Uninstall deletes the ClusterServiceVersion and the Subscription created by ODLM,
+// unless the Subscription is labeled not to be uninstalled
+func (i *olmInstaller) Uninstall(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) error {
+	namespace := i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	sub, err := i.GetSubscription(ctx, opt.Name, namespace, opt.PackageName)
+	if apierrors.IsNotFound(err) {
+		klog.V(3).Infof("There is no Subscription %s or %s in the namespace %s", opt.Name, opt.PackageName, namespace)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, ok := sub.Labels[constant.OpreqLabel]; !ok {
+		// Subscription existing and not managed by OperandRequest controller
+		klog.V(2).Infof("Subscription %s in the namespace %s isn't created by ODLM", sub.Name, sub.Namespace)
+		return nil
+	}
+
+	csv, err := i.GetClusterServiceVersion(ctx, sub)
+	// If can't get CSV, requeue the request
+	if err != nil {
+		return err
+	}
+
+	if csv != nil {
+		if i.checkUninstallLabel(ctx, opt.Name, namespace) {
+			klog.V(1).Infof("Operator %s has label operator.ibm.com/opreq-do-not-uninstall. Skip the uninstall", opt.Name)
+			return nil
+		}
+
+		klog.V(3).Info("Set Deleting Condition in the operandRequest")
+		requestInstance.SetDeletingCondition(opt.Name, apiv1alpha1.ResourceTypeCsv, corev1.ConditionTrue)
+
+		klog.V(1).Infof("Deleting the ClusterServiceVersion, Namespace: %s, Name: %s", csv.Namespace, csv.Name)
+		if err := i.Delete(ctx, csv); err != nil {
+			requestInstance.SetDeletingCondition(opt.Name, apiv1alpha1.ResourceTypeCsv, corev1.ConditionFalse)
+			return errors.Wrap(err, "failed to delete the ClusterServiceVersion")
+		}
+	}
+
+	klog.V(2).Infof("Deleting the Subscription, Namespace: %s, Name: %s", namespace, opt.Name)
+	requestInstance.SetDeletingCondition(opt.Name, apiv1alpha1.ResourceTypeSub, corev1.ConditionTrue)
+
+	if err := i.Delete(ctx, sub); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.Warningf("Subscription %s was not found in namespace %s", opt.Name, namespace)
+		} else {
+			requestInstance.SetDeletingCondition(opt.Name, apiv1alpha1.ResourceTypeSub, corev1.ConditionFalse)
+			return errors.Wrap(err, "failed to delete subscription")
+		}
+	}
+
+	klog.V(1).Infof("Subscription %s/%s is deleted", namespace, opt.Name)
+	return nil
+}
+
+// Status returns the phase of the operator from the phase of the ClusterServiceVersion of its Subscription
+func (i *olmInstaller) Status(ctx context.Context, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) (*InstallStatus, error) {
+	namespace := i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	sub, err := i.GetSubscription(ctx, opt.Name, namespace, opt.PackageName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return &InstallStatus{}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the Subscription %s or %s in the namespace %s", opt.Name, opt.PackageName, namespace)
+	}
+	_, managed := sub.Labels[constant.OpreqLabel]
+	status := &InstallStatus{Installed: true, Managed: managed, Phase: apiv1alpha1.OperatorInstalling}
+
+	csv, err := i.GetClusterServiceVersion(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+	if csv == nil {
+		klog.V(2).Infof("ClusterServiceVersion for the Subscription %s in the namespace %s is not ready yet", sub.Name, namespace)
+		return status, nil
+	}
+	switch csv.Status.Phase {
+	case olmv1alpha1.CSVPhaseSucceeded:
+		status.Phase = apiv1alpha1.OperatorRunning
+	case olmv1alpha1.CSVPhaseFailed:
+		status.Phase = apiv1alpha1.OperatorFailed
+	}
+	return status, nil
+}
+
+// Templates returns the alm-examples of the ClusterServiceVersion of the operator
+func (i *olmInstaller) Templates(ctx context.Context, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) ([]unstructured.Unstructured, error) {
+	namespace := i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	sub, err := i.GetSubscription(ctx, opt.Name, namespace, opt.PackageName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the Subscription %s or %s in the namespace %s", opt.Name, opt.PackageName, namespace)
+	}
+	csv, err := i.GetClusterServiceVersion(ctx, sub)
+	if err != nil || csv == nil {
+		return nil, err
+	}
+	almExamples := csv.ObjectMeta.Annotations["alm-examples"]
+	if almExamples == "" {
+		klog.Warningf("Notfound alm-examples in the ClusterServiceVersion %s/%s", csv.Namespace, csv.Name)
+		return []unstructured.Unstructured{}, nil
+	}
+	return ParseTemplates(almExamples)
+}
+
+// ParseTemplates parses the templates of the custom resources in the JSON array, like the alm-examples
+func ParseTemplates(data string) ([]unstructured.Unstructured, error) {
+	var crTemplates []map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &crTemplates); err != nil {
+		return nil, errors.Wrap(err, "failed to convert the templates of the custom resources to slice")
+	}
+	templates := make([]unstructured.Unstructured, 0, len(crTemplates))
+	for _, crTemplate := range crTemplates {
+		templates = append(templates, unstructured.Unstructured{Object: crTemplate})
+	}
+	return templates, nil
+}
+
+// +kubebuilder:rbac:groups=operators.coreos.com,resources=installplans,verbs=get;list;watch;update;patch
+
+// approveInstallPlan approves the pending InstallPlan of the Subscription when all its ClusterServiceVersions
+// satisfy the version constraint of the operator, or, without a version constraint, when they all match the
+// allowlist of the operator. An InstallPlan is also approved when all its ClusterServiceVersions are listed in
+// the approval annotation of the OperandRequest or the OperandRegistry, even if they are out of the constraint.
+// The ClusterServiceVersions out of the constraint, and the InstallPlans still waiting for the approval,
+// are reported in the member status.
+func (i *olmInstaller) approveInstallPlan(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator, sub *olmv1alpha1.Subscription) error {
+	var inRange semver.Range
+	if opt.VersionConstraint != "" {
+		var err error
+		if inRange, err = semver.ParseRange(opt.VersionConstraint); err != nil {
+			return errors.Wrapf(err, "failed to parse the version constraint %s of operator %s", opt.VersionConstraint, opt.Name)
+		}
+	}
+
+	var outOfConstraint []string
+	var pending []apiv1alpha1.PendingInstallPlan
+
+	// Check the ClusterServiceVersion installed by the Subscription
+	if inRange != nil && sub.Status.InstalledCSV != "" {
+		csv := &olmv1alpha1.ClusterServiceVersion{}
+		csvKey := types.NamespacedName{Name: sub.Status.InstalledCSV, Namespace: sub.Namespace}
+		if err := i.Client.Get(ctx, csvKey, csv); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to get ClusterServiceVersion %s", csvKey.String())
+			}
+		} else if !inRange(csv.Spec.Version.Version) {
+			outOfConstraint = append(outOfConstraint, csv.Name)
+		}
+	}
+
+	// Check the InstallPlan waiting for approval
+	if sub.Status.InstallPlanRef != nil && sub.Status.CurrentCSV != sub.Status.InstalledCSV {
+		ip := &olmv1alpha1.InstallPlan{}
+		ipKey := types.NamespacedName{Name: sub.Status.InstallPlanRef.Name, Namespace: sub.Namespace}
+		if err := i.Client.Get(ctx, ipKey, ip); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to get InstallPlan %s", ipKey.String())
+			}
+		} else if ip.Spec.Approval == olmv1alpha1.ApprovalManual && !ip.Spec.Approved {
+			csvNames := getInstallPlanCSVNames(ip, sub.Status.CurrentCSV)
+			approve := false
+			if inRange != nil {
+				inConstraint := true
+				for _, csvName := range csvNames {
+					version, err := getInstallPlanCSVVersion(ip, csvName)
+					if err != nil {
+						klog.Warningf("failed to get the version of ClusterServiceVersion %s in InstallPlan %s: %v", csvName, ipKey.String(), err)
+					} else if inRange(version) {
+						continue
+					}
+					klog.V(1).Infof("ClusterServiceVersion %s doesn't satisfy the version constraint %s of InstallPlan %s", csvName, opt.VersionConstraint, ipKey.String())
+					outOfConstraint = append(outOfConstraint, csvName)
+					inConstraint = false
+				}
+				approve = inConstraint
+			} else if matchAllowlist(opt.InstallPlanAllowlist, csvNames) {
+				klog.V(2).Infof("ClusterServiceVersions %s of InstallPlan %s match the allowlist of operator %s", strings.Join(csvNames, ", "), ipKey.String(), opt.Name)
+				approve = true
+			}
+			if !approve && approvedByAnnotation(csvNames, requestInstance, registryInstance) {
+				klog.V(2).Infof("ClusterServiceVersions %s of InstallPlan %s are approved by the annotation %s", strings.Join(csvNames, ", "), ipKey.String(), constant.ApproveInstallPlansAnnotation)
+				approve = true
+			}
+			if approve {
+				klog.V(1).Infof("Approving InstallPlan %s for ClusterServiceVersion %s", ipKey.String(), strings.Join(csvNames, ", "))
+				ip.Spec.Approved = true
+				if err := i.Update(ctx, ip); err != nil {
+					return errors.Wrapf(err, "failed to approve InstallPlan %s", ipKey.String())
+				}
+			} else {
+				pending = append(pending, apiv1alpha1.PendingInstallPlan{Name: ip.Name, Namespace: ip.Namespace, ClusterServiceVersions: csvNames})
+			}
+		}
+	}
+
+	if len(outOfConstraint) != 0 {
+		requestInstance.SetOutofConstraintCondition(opt.Name, outOfConstraint, opt.VersionConstraint)
+	} else {
+		requestInstance.RemoveMemberCondition(opt.Name, apiv1alpha1.ConditionOutofConstraint)
+	}
+	requestInstance.SetPendingInstallPlans(opt.Name, pending)
+	return nil
+}
+
+// getInstallPlanCSVNames returns all the ClusterServiceVersions of the InstallPlan, from its spec and its bundle lookups.
+// It returns the current ClusterServiceVersion of the Subscription if the InstallPlan doesn't list any of them.
+func getInstallPlanCSVNames(ip *olmv1alpha1.InstallPlan, currentCSV string) []string {
+	var csvNames []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			csvNames = append(csvNames, name)
+		}
+	}
+	for _, name := range ip.Spec.ClusterServiceVersionNames {
+		add(name)
+	}
+	for _, lookup := range ip.Status.BundleLookups {
+		add(lookup.Identifier)
+	}
+	if len(csvNames) == 0 {
+		add(currentCSV)
+	}
+	return csvNames
+}
+
+// matchAllowlist checks if all the ClusterServiceVersions match one of the patterns in the allowlist
+func matchAllowlist(allowlist, csvNames []string) bool {
+	if len(allowlist) == 0 {
+		return false
+	}
+	for _, csvName := range csvNames {
+		matched := false
+		for _, pattern := range allowlist {
+			if ok, err := path.Match(pattern, csvName); err == nil && ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// approvedByAnnotation checks if all the ClusterServiceVersions are listed in the approval annotations
+// of the OperandRequest and the OperandRegistry. The annotation is a comma separated list of the names.
+func approvedByAnnotation(csvNames []string, requestInstance *apiv1alpha1.OperandRequest, registryInstance *apiv1alpha1.OperandRegistry) bool {
+	approved := make(map[string]bool)
+	for _, annotations := range []map[string]string{requestInstance.GetAnnotations(), registryInstance.GetAnnotations()} {
+		for _, name := range strings.Split(annotations[constant.ApproveInstallPlansAnnotation], ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				approved[name] = true
+			}
+		}
+	}
+	for _, csvName := range csvNames {
+		if !approved[csvName] {
+			return false
+		}
+	}
+	return true
+}
+
+// getInstallPlanCSVVersion gets the version of the ClusterServiceVersion from the steps of the InstallPlan.
+// If the manifest isn't in the steps, the version is parsed from the name, like etcdoperator.v0.9.4.
+func getInstallPlanCSVVersion(ip *olmv1alpha1.InstallPlan, csvName string) (semver.Version, error) {
+	for _, step := range ip.Status.Plan {
+		if step == nil || step.Resource.Kind != olmv1alpha1.ClusterServiceVersionKind || step.Resource.Name != csvName || step.Resource.Manifest == "" {
+			continue
+		}
+		csv := &olmv1alpha1.ClusterServiceVersion{}
+		if err := json.Unmarshal([]byte(step.Resource.Manifest), csv); err == nil && csv.Kind == olmv1alpha1.ClusterServiceVersionKind {
+			return csv.Spec.Version.Version, nil
+		}
+	}
+	pos := strings.Index(csvName, ".v")
+	if pos == -1 {
+		return semver.Version{}, fmt.Errorf("not found the version of ClusterServiceVersion %s", csvName)
+	}
+	return semver.ParseTolerant(csvName[pos+2:])
+}
+
+// getInstallPlanApproval returns the approval of the InstallPlans.
+// The InstallPlans are approved by ODLM when the operator has a version constraint or an allowlist.
+func getInstallPlanApproval(opt *apiv1alpha1.Operator) olmv1alpha1.Approval {
+	if opt.VersionConstraint != "" || len(opt.InstallPlanAllowlist) != 0 {
+		return olmv1alpha1.ApprovalManual
+	}
+	return opt.InstallPlanApproval
+}
+
+func (i *olmInstaller) createSubscription(ctx context.Context, cr *apiv1alpha1.OperandRequest, opt *apiv1alpha1.Operator) error {
+	namespace := i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	klog.V(3).Info("Subscription Namespace: ", namespace)
+
+	co := i.generateClusterObjects(opt)
+
+	// Create required namespace
+	ns := co.namespace
+	klog.V(3).Info("Creating the Namespace for Operator: " + opt.Name)
+
+	// Compare namespace and create namespace
+	oprNs := util.GetOperatorNamespace()
+	if ns.Name != oprNs && ns.Name != constant.ClusterOperatorNamespace {
+		if err := i.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
+			klog.Warningf("failed to create the namespace %s, please make sure it exists: %s", ns.Name, err)
+		}
+	}
+
+	if namespace != constant.ClusterOperatorNamespace {
+		// Create required operatorgroup
+		existOG := &olmv1.OperatorGroupList{}
+		if err := i.Client.List(ctx, existOG, &client.ListOptions{Namespace: co.operatorGroup.Namespace}); err != nil {
+			return err
+		}
+		if len(existOG.Items) == 0 {
+			og := co.operatorGroup
+			klog.V(3).Info("Creating the OperatorGroup for Subscription: " + opt.Name)
+			if err := i.Create(ctx, og); err != nil && !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+		}
+	}
+
+	// Create subscription
+	klog.V(2).Info("Creating the Subscription: " + opt.Name)
+	sub := co.subscription
+	cr.SetCreatingCondition(sub.Name, apiv1alpha1.ResourceTypeSub, corev1.ConditionTrue)
+
+	if err := i.Create(ctx, sub); err != nil && !apierrors.IsAlreadyExists(err) {
+		cr.SetCreatingCondition(sub.Name, apiv1alpha1.ResourceTypeSub, corev1.ConditionFalse)
+		return err
+	}
+	return nil
+}
+
+func (i *olmInstaller) updateSubscription(ctx context.Context, cr *apiv1alpha1.OperandRequest, sub *olmv1alpha1.Subscription) error {
+
+	klog.V(2).Infof("Updating Subscription %s/%s ...", sub.Namespace, sub.Name)
+	cr.SetUpdatingCondition(sub.Name, apiv1alpha1.ResourceTypeSub, corev1.ConditionTrue)
+
+	if err := i.Update(ctx, sub); err != nil {
+		cr.SetUpdatingCondition(sub.Name, apiv1alpha1.ResourceTypeSub, corev1.ConditionFalse)
+		return err
+	}
+	return nil
+}
+
+func (i *olmInstaller) generateClusterObjects(o *apiv1alpha1.Operator) *clusterObjects {
+	klog.V(3).Info("Generating Cluster Objects")
+	co := &clusterObjects{}
+	labels := map[string]string{
+		constant.OpreqLabel: "true",
+	}
+
+	klog.V(3).Info("Generating Namespace: ", o.Namespace)
+	// Namespace Object
+	co.namespace = &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Namespace",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   o.Namespace,
+			Labels: labels,
+		},
+	}
+
+	// Operator Group Object
+	klog.V(3).Info("Generating Operator Group in the Namespace: ", o.Namespace, " with target namespace: ", o.TargetNamespaces)
+	og := generateOperatorGroup(o.Namespace, o.TargetNamespaces)
+	co.operatorGroup = og
+
+	// The namespace is 'openshift-operators' when installMode is cluster
+	namespace := i.GetOperatorNamespace(o.InstallMode, o.Namespace)
+
+	// Subscription Object
+	sub := &olmv1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      o.Name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: &olmv1alpha1.SubscriptionSpec{
+			Channel:                o.Channel,
+			Package:                o.PackageName,
+			CatalogSource:          o.SourceName,
+			CatalogSourceNamespace: o.SourceNamespace,
+			InstallPlanApproval:    getInstallPlanApproval(o),
+			StartingCSV:            o.StartingCSV,
+		},
+	}
+	if o.SubscriptionConfig != nil {
+		sub.Spec.Config = *o.SubscriptionConfig.DeepCopy()
+	}
+	sub.SetGroupVersionKind(schema.GroupVersionKind{Group: olmv1alpha1.SchemeGroupVersion.Group, Kind: "Subscription", Version: olmv1alpha1.SchemeGroupVersion.Version})
+	klog.V(3).Info("Generating Subscription:  ", o.Name, " in the Namespace: ", namespace)
+	co.subscription = sub
+	return co
+}
+
+func generateOperatorGroup(namespace string, targetNamespaces []string) *olmv1.OperatorGroup {
+	labels := map[string]string{
+		constant.OpreqLabel: "true",
+	}
+	if targetNamespaces == nil {
+		targetNamespaces = append(targetNamespaces, namespace)
+	}
+	// Operator Group Object
+	og := &olmv1.OperatorGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "operand-deployment-lifecycle-manager-operatorgroup",
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: olmv1.OperatorGroupSpec{
+			TargetNamespaces: targetNamespaces,
+		},
+	}
+	og.SetGroupVersionKind(schema.GroupVersionKind{Group: olmv1.SchemeGroupVersion.Group, Kind: "OperatorGroup", Version: olmv1.SchemeGroupVersion.Version})
+
+	return og
+}
+
+func (i *olmInstaller) checkUninstallLabel(ctx context.Context, name, namespace string) bool {
+	sub := &olmv1alpha1.Subscription{}
+	subKey := types.NamespacedName{Name: name, Namespace: namespace}
+	if err := i.Client.Get(ctx, subKey, sub); err != nil {
+		klog.Warning("failed to get subscription: ", err)
+		return true
+	}
+	subLabels := sub.GetLabels()
+	return subLabels[constant.NotUninstallLabel] == "true"
+}
+
+func compareSub(spec *olmv1alpha1.SubscriptionSpec, template *apiv1alpha1.Operator) (needUpdate bool) {
+	// The config of the Subscription is only compared when it is managed by the OperandRegistry
+	if template.SubscriptionConfig != nil && !equality.Semantic.DeepEqual(spec.Config, *template.SubscriptionConfig) {
+		return true
+	}
+	return spec.CatalogSource != template.SourceName || spec.Channel != template.Channel || spec.CatalogSourceNamespace != template.SourceNamespace || spec.Package != template.PackageName || spec.InstallPlanApproval != getInstallPlanApproval(template)
+}
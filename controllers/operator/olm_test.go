@@ -0,0 +1,383 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+
+	"github.com/blang/semver"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/operator-framework/api/pkg/lib/version"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
+)
+
+var _ = Describe("Approving InstallPlans with the version constraint", func() {
+	const (
+		registryName      = "common-service"
+		registryNamespace = "ibm-common-services"
+		operatorNamespace = "ibm-operators"
+	)
+
+	var (
+		ctx      context.Context
+		opt      *operatorv1alpha1.Operator
+		registry *operatorv1alpha1.OperandRegistry
+		request  *operatorv1alpha1.OperandRequest
+		sub      *olmv1alpha1.Subscription
+		ip       *olmv1alpha1.InstallPlan
+	)
+
+	newInstaller := func(objs ...runtime.Object) *olmInstaller {
+		scheme := runtime.NewScheme()
+		Expect(operatorv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		Expect(olmv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		return &olmInstaller{&ODLMOperator{
+			Client: fake.NewFakeClientWithScheme(scheme, objs...),
+			Scheme: scheme,
+		}}
+	}
+
+	getInstallPlan := func(r *olmInstaller) *olmv1alpha1.InstallPlan {
+		found := &olmv1alpha1.InstallPlan{}
+		Expect(r.Client.Get(ctx, types.NamespacedName{Name: ip.Name, Namespace: ip.Namespace}, found)).Should(Succeed())
+		return found
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		registry = testutil.OperandRegistryObj(registryName, registryNamespace, operatorNamespace)
+		opt = registry.GetOperator("etcd")
+		opt.VersionConstraint = ">=3.5.0 <3.7.0"
+		request = testutil.OperandRequestObj(registryName, registryNamespace, "ibm-cloudpak-name", "ibm-cloudpak")
+
+		sub = testutil.Subscription("etcd", operatorNamespace)
+		sub.Status = testutil.SubscriptionStatus("etcd", operatorNamespace, "3.6.0")
+		sub.Status.InstalledCSV = ""
+		ip = testutil.InstallPlan("etcd-install-plan", operatorNamespace)
+		ip.Spec.Approval = olmv1alpha1.ApprovalManual
+		ip.Spec.ClusterServiceVersionNames = []string{sub.Status.CurrentCSV}
+	})
+
+	It("Should approve the InstallPlan whose CSV satisfies the constraint", func() {
+		r := newInstaller(ip)
+		Expect(r.approveInstallPlan(ctx, request, registry, opt, sub)).Should(Succeed())
+		Expect(getInstallPlan(r).Spec.Approved).Should(BeTrue())
+		Expect(request.Status.Members).Should(BeEmpty())
+	})
+
+	It("Should not approve the InstallPlan whose CSV is out of the constraint", func() {
+		sub.Status.CurrentCSV = "etcd-csv.v3.7.1"
+		ip.Spec.ClusterServiceVersionNames = []string{sub.Status.CurrentCSV}
+		r := newInstaller(ip)
+		Expect(r.approveInstallPlan(ctx, request, registry, opt, sub)).Should(Succeed())
+		Expect(getInstallPlan(r).Spec.Approved).Should(BeFalse())
+		Expect(request.Status.Members).Should(HaveLen(1))
+		Expect(request.HasMemberCondition("etcd", operatorv1alpha1.ConditionOutofConstraint)).Should(BeTrue())
+		Expect(request.Status.Members[0].Conditions[0].Message).Should(ContainSubstring("etcd-csv.v3.7.1"))
+		Expect(request.Status.Members[0].InstallPlans).Should(Equal([]operatorv1alpha1.PendingInstallPlan{
+			{Name: ip.Name, Namespace: operatorNamespace, ClusterServiceVersions: []string{"etcd-csv.v3.7.1"}},
+		}))
+	})
+
+	It("Should check all the CSVs of the InstallPlan against the constraint", func() {
+		ip.Spec.ClusterServiceVersionNames = []string{sub.Status.CurrentCSV, "etcd-backup-csv.v3.7.1"}
+		r := newInstaller(ip)
+		Expect(r.approveInstallPlan(ctx, request, registry, opt, sub)).Should(Succeed())
+		Expect(getInstallPlan(r).Spec.Approved).Should(BeFalse())
+		Expect(request.Status.Members[0].Conditions[0].Message).Should(ContainSubstring("etcd-backup-csv.v3.7.1"))
+		Expect(request.Status.Members[0].Conditions[0].Message).ShouldNot(ContainSubstring(sub.Status.CurrentCSV))
+		Expect(request.Status.Members[0].InstallPlans).Should(Equal([]operatorv1alpha1.PendingInstallPlan{
+			{Name: ip.Name, Namespace: operatorNamespace, ClusterServiceVersions: ip.Spec.ClusterServiceVersionNames},
+		}))
+	})
+
+	It("Should check the CSVs in the bundle lookups of the InstallPlan", func() {
+		ip.Spec.ClusterServiceVersionNames = nil
+		ip.Status.BundleLookups = []olmv1alpha1.BundleLookup{
+			{Identifier: sub.Status.CurrentCSV},
+			{Identifier: "etcd-backup-csv.v3.7.1"},
+		}
+		r := newInstaller(ip)
+		Expect(r.approveInstallPlan(ctx, request, registry, opt, sub)).Should(Succeed())
+		Expect(getInstallPlan(r).Spec.Approved).Should(BeFalse())
+		Expect(request.Status.Members[0].InstallPlans[0].ClusterServiceVersions).Should(Equal([]string{sub.Status.CurrentCSV, "etcd-backup-csv.v3.7.1"}))
+	})
+
+	It("Should report the installed CSV which is out of the constraint", func() {
+		sub.Status.InstalledCSV = "etcd-csv.v3.4.0"
+		csv := testutil.ClusterServiceVersion("etcd-csv.v3.4.0", operatorNamespace, "[]")
+		csv.Spec.Version = version.OperatorVersion{Version: semver.MustParse("3.4.0")}
+		r := newInstaller(ip, csv)
+		Expect(r.approveInstallPlan(ctx, request, registry, opt, sub)).Should(Succeed())
+		Expect(getInstallPlan(r).Spec.Approved).Should(BeTrue())
+		Expect(request.Status.Members[0].Conditions[0].Message).Should(ContainSubstring("etcd-csv.v3.4.0"))
+	})
+
+	It("Should read the version of the CSV from the InstallPlan steps", func() {
+		ip.Status.Plan = []*olmv1alpha1.Step{
+			{
+				Resolving: sub.Status.CurrentCSV,
+				Resource: olmv1alpha1.StepResource{
+					Kind:     olmv1alpha1.ClusterServiceVersionKind,
+					Name:     sub.Status.CurrentCSV,
+					Manifest: `{"apiVersion":"operators.coreos.com/v1alpha1","kind":"ClusterServiceVersion","spec":{"version":"3.8.0"}}`,
+				},
+			},
+		}
+		v, err := getInstallPlanCSVVersion(ip, sub.Status.CurrentCSV)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v.String()).Should(Equal("3.8.0"))
+	})
+})
+
+var _ = Describe("Approving InstallPlans manually", func() {
+	const (
+		registryName      = "common-service"
+		registryNamespace = "ibm-common-services"
+		operatorNamespace = "ibm-operators"
+	)
+
+	var (
+		ctx      context.Context
+		opt      *operatorv1alpha1.Operator
+		registry *operatorv1alpha1.OperandRegistry
+		request  *operatorv1alpha1.OperandRequest
+		sub      *olmv1alpha1.Subscription
+		ip       *olmv1alpha1.InstallPlan
+	)
+
+	newInstaller := func(objs ...runtime.Object) *olmInstaller {
+		scheme := runtime.NewScheme()
+		Expect(operatorv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		Expect(olmv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		return &olmInstaller{&ODLMOperator{
+			Client: fake.NewFakeClientWithScheme(scheme, objs...),
+			Scheme: scheme,
+		}}
+	}
+
+	getInstallPlan := func(r *olmInstaller) *olmv1alpha1.InstallPlan {
+		found := &olmv1alpha1.InstallPlan{}
+		Expect(r.Client.Get(ctx, types.NamespacedName{Name: ip.Name, Namespace: ip.Namespace}, found)).Should(Succeed())
+		return found
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		registry = testutil.OperandRegistryObj(registryName, registryNamespace, operatorNamespace)
+		opt = registry.GetOperator("etcd")
+		opt.InstallPlanApproval = olmv1alpha1.ApprovalManual
+		request = testutil.OperandRequestObj(registryName, registryNamespace, "ibm-cloudpak-name", "ibm-cloudpak")
+
+		sub = testutil.Subscription("etcd", operatorNamespace)
+		sub.Status = testutil.SubscriptionStatus("etcd", operatorNamespace, "0.0.2")
+		sub.Status.InstalledCSV = "etcd-csv.v0.0.1"
+		ip = testutil.InstallPlan("etcd-install-plan", operatorNamespace)
+		ip.Spec.Approval = olmv1alpha1.ApprovalManual
+		ip.Spec.ClusterServiceVersionNames = []string{sub.Status.CurrentCSV, "etcd-backup-csv.v0.0.2"}
+	})
+
+	It("Should report the InstallPlan waiting for the approval", func() {
+		r := newInstaller(ip)
+		Expect(r.approveInstallPlan(ctx, request, registry, opt, sub)).Should(Succeed())
+		Expect(getInstallPlan(r).Spec.Approved).Should(BeFalse())
+		Expect(request.Status.Members).Should(HaveLen(1))
+		Expect(request.Status.Members[0].InstallPlans).Should(Equal([]operatorv1alpha1.PendingInstallPlan{
+			{Name: ip.Name, Namespace: operatorNamespace, ClusterServiceVersions: ip.Spec.ClusterServiceVersionNames},
+		}))
+		Expect(request.HasMemberCondition("etcd", operatorv1alpha1.ConditionPendingApproval)).Should(BeTrue())
+
+		By("Approving the InstallPlan by hand")
+		ip = getInstallPlan(r)
+		ip.Spec.Approved = true
+		Expect(r.Update(ctx, ip)).Should(Succeed())
+		Expect(r.approveInstallPlan(ctx, request, registry, opt, sub)).Should(Succeed())
+		Expect(request.Status.Members[0].InstallPlans).Should(BeEmpty())
+		Expect(request.HasMemberCondition("etcd", operatorv1alpha1.ConditionPendingApproval)).Should(BeFalse())
+	})
+
+	It("Should approve the InstallPlan whose CSVs all match the allowlist", func() {
+		opt.InstallPlanAllowlist = []string{"etcd-csv.v0.0.*", "etcd-backup-csv.*"}
+		r := newInstaller(ip)
+		Expect(r.approveInstallPlan(ctx, request, registry, opt, sub)).Should(Succeed())
+		Expect(getInstallPlan(r).Spec.Approved).Should(BeTrue())
+		Expect(request.Status.Members).Should(BeEmpty())
+	})
+
+	It("Should not approve the InstallPlan with a CSV out of the allowlist", func() {
+		opt.InstallPlanAllowlist = []string{"etcd-csv.v0.0.*"}
+		r := newInstaller(ip)
+		Expect(r.approveInstallPlan(ctx, request, registry, opt, sub)).Should(Succeed())
+		Expect(getInstallPlan(r).Spec.Approved).Should(BeFalse())
+		Expect(request.Status.Members[0].InstallPlans).Should(HaveLen(1))
+	})
+
+	It("Should approve the InstallPlan whose CSVs are listed in the annotations", func() {
+		request.Annotations = map[string]string{constant.ApproveInstallPlansAnnotation: "etcd-csv.v0.0.2"}
+		r := newInstaller(ip)
+		Expect(r.approveInstallPlan(ctx, request, registry, opt, sub)).Should(Succeed())
+		Expect(getInstallPlan(r).Spec.Approved).Should(BeFalse())
+
+		registry.Annotations = map[string]string{constant.ApproveInstallPlansAnnotation: "jenkins-csv.v0.0.2, etcd-backup-csv.v0.0.2"}
+		Expect(r.approveInstallPlan(ctx, request, registry, opt, sub)).Should(Succeed())
+		Expect(getInstallPlan(r).Spec.Approved).Should(BeTrue())
+		Expect(request.Status.Members[0].InstallPlans).Should(BeEmpty())
+	})
+
+	It("Should approve the InstallPlan out of the version constraint by the annotation", func() {
+		opt.VersionConstraint = "<0.0.2"
+		r := newInstaller(ip)
+		Expect(r.approveInstallPlan(ctx, request, registry, opt, sub)).Should(Succeed())
+		Expect(getInstallPlan(r).Spec.Approved).Should(BeFalse())
+		Expect(request.HasMemberCondition("etcd", operatorv1alpha1.ConditionOutofConstraint)).Should(BeTrue())
+		Expect(request.Status.Members[0].InstallPlans).Should(Equal([]operatorv1alpha1.PendingInstallPlan{
+			{Name: ip.Name, Namespace: operatorNamespace, ClusterServiceVersions: ip.Spec.ClusterServiceVersionNames},
+		}))
+
+		request.Annotations = map[string]string{constant.ApproveInstallPlansAnnotation: "etcd-csv.v0.0.2,etcd-backup-csv.v0.0.2"}
+		Expect(r.approveInstallPlan(ctx, request, registry, opt, sub)).Should(Succeed())
+		Expect(getInstallPlan(r).Spec.Approved).Should(BeTrue())
+		Expect(request.HasMemberCondition("etcd", operatorv1alpha1.ConditionOutofConstraint)).Should(BeTrue())
+		Expect(request.Status.Members[0].InstallPlans).Should(BeEmpty())
+	})
+
+	It("Should subscribe with the manual approval when the operator has an allowlist", func() {
+		opt.InstallPlanApproval = olmv1alpha1.ApprovalAutomatic
+		opt.InstallPlanAllowlist = []string{"etcd-csv.*"}
+		Expect(getInstallPlanApproval(opt)).Should(Equal(olmv1alpha1.ApprovalManual))
+	})
+})
+
+var _ = Describe("Generating the Subscription with the config", func() {
+	var opt *operatorv1alpha1.Operator
+
+	BeforeEach(func() {
+		registry := testutil.OperandRegistryObj("common-service", "ibm-common-services", "ibm-operators")
+		opt = registry.GetOperator("etcd")
+		opt.SubscriptionConfig = &olmv1alpha1.SubscriptionConfig{
+			Env:          []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}},
+			NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+			Tolerations:  []corev1.Toleration{{Key: "node-role.kubernetes.io/infra", Effect: corev1.TaintEffectNoSchedule}},
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+		}
+	})
+
+	It("Should set the config in the Subscription", func() {
+		r := &olmInstaller{&ODLMOperator{}}
+		sub := r.generateClusterObjects(opt).subscription
+		Expect(sub.Spec.Config).Should(Equal(*opt.SubscriptionConfig))
+		Expect(compareSub(sub.Spec, opt)).Should(BeFalse())
+	})
+
+	It("Should update the Subscription when its config is changed", func() {
+		r := &olmInstaller{&ODLMOperator{}}
+		sub := r.generateClusterObjects(opt).subscription
+		sub.Spec.Config.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("1Gi")
+		Expect(compareSub(sub.Spec, opt)).Should(BeTrue())
+	})
+
+	It("Should not manage the config of the Subscription when it isn't set", func() {
+		r := &olmInstaller{&ODLMOperator{}}
+		sub := r.generateClusterObjects(opt).subscription
+		opt.SubscriptionConfig = nil
+		Expect(compareSub(sub.Spec, opt)).Should(BeFalse())
+	})
+})
+
+var _ = Describe("Getting the status and the templates of the operator installed by OLM", func() {
+	const operatorNamespace = "ibm-operators"
+
+	var (
+		ctx context.Context
+		opt *operatorv1alpha1.Operator
+		sub *olmv1alpha1.Subscription
+		csv *olmv1alpha1.ClusterServiceVersion
+	)
+
+	newInstaller := func(objs ...runtime.Object) *olmInstaller {
+		scheme := runtime.NewScheme()
+		Expect(operatorv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		Expect(olmv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		return &olmInstaller{&ODLMOperator{
+			Client: fake.NewFakeClientWithScheme(scheme, objs...),
+			Scheme: scheme,
+		}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		registry := testutil.OperandRegistryObj("common-service", "ibm-common-services", operatorNamespace)
+		opt = registry.GetOperator("etcd")
+		sub = testutil.Subscription("etcd", operatorNamespace)
+		sub.Status = testutil.SubscriptionStatus("etcd", operatorNamespace, "0.0.1")
+		csv = testutil.ClusterServiceVersion("etcd-csv.v0.0.1", operatorNamespace, testutil.EtcdExample)
+	})
+
+	It("Should not be installed without the Subscription", func() {
+		r := newInstaller()
+		status, err := r.Status(ctx, nil, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Installed).Should(BeFalse())
+		Expect(r.Templates(ctx, nil, opt)).Should(BeNil())
+	})
+
+	It("Should follow the phase of the ClusterServiceVersion", func() {
+		r := newInstaller(sub, csv)
+		status, err := r.Status(ctx, nil, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*status).Should(Equal(InstallStatus{Installed: true, Managed: true, Phase: operatorv1alpha1.OperatorInstalling}))
+
+		csv.Status = testutil.ClusterServiceVersionStatus()
+		r = newInstaller(sub, csv)
+		status, err = r.Status(ctx, nil, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Phase).Should(Equal(operatorv1alpha1.OperatorRunning))
+	})
+
+	It("Should not manage the Subscription created by the others", func() {
+		sub.Labels = nil
+		status, err := newInstaller(sub, csv).Status(ctx, nil, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Installed).Should(BeTrue())
+		Expect(status.Managed).Should(BeFalse())
+	})
+
+	It("Should parse the alm-examples of the ClusterServiceVersion", func() {
+		templates, err := newInstaller(sub, csv).Templates(ctx, nil, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templates).Should(HaveLen(1))
+		Expect(templates[0].GetKind()).Should(Equal("EtcdCluster"))
+
+		csv.Annotations = nil
+		templates, err = newInstaller(sub, csv).Templates(ctx, nil, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templates).ShouldNot(BeNil())
+		Expect(templates).Should(BeEmpty())
+	})
+})
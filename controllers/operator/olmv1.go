@@ -0,0 +1,505 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	constant "github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	util "github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+)
+
+var (
+	// ClusterExtensionGVK is the GroupVersionKind of the ClusterExtensions which install the operators by OLM v1
+	ClusterExtensionGVK = schema.GroupVersionKind{Group: "olm.operatorframework.io", Version: "v1", Kind: "ClusterExtension"}
+	// ClusterCatalogGVK is the GroupVersionKind of the ClusterCatalogs which serve the packages of OLM v1
+	ClusterCatalogGVK = schema.GroupVersionKind{Group: "olm.operatorframework.io", Version: "v1", Kind: "ClusterCatalog"}
+)
+
+// catalogNameLabel is the label of the name of a ClusterCatalog, which selects the catalog of a ClusterExtension
+const catalogNameLabel = "olm.operatorframework.io/metadata.name"
+
+// catalogTemplates are the templates of the bundles in the content of a ClusterCatalog,
+// which is identified by the resolved reference of the catalog image
+type catalogTemplates struct {
+	ref     string
+	bundles map[string][]unstructured.Unstructured
+}
+
+// bundleTemplates caches the templates of the installed bundles by the ClusterCatalog, so the catalogs are not
+// fetched in every reconcile. The templates of a ClusterCatalog are dropped once its content changes.
+var bundleTemplates = struct {
+	sync.Mutex
+	catalogs map[string]*catalogTemplates
+}{catalogs: map[string]*catalogTemplates{}}
+
+// loadBundleTemplates returns a copy of the cached templates of the bundle in the content of the ClusterCatalog
+func loadBundleTemplates(catalog, ref, bundle string) ([]unstructured.Unstructured, bool) {
+	bundleTemplates.Lock()
+	defer bundleTemplates.Unlock()
+	cached, ok := bundleTemplates.catalogs[catalog]
+	if !ok || ref == "" || cached.ref != ref {
+		return nil, false
+	}
+	templates, ok := cached.bundles[bundle]
+	if !ok {
+		return nil, false
+	}
+	copied := make([]unstructured.Unstructured, len(templates))
+	for i := range templates {
+		templates[i].DeepCopyInto(&copied[i])
+	}
+	return copied, true
+}
+
+// storeBundleTemplates caches the templates of the bundle, and drops the templates of the previous content of the ClusterCatalog
+func storeBundleTemplates(catalog, ref, bundle string, templates []unstructured.Unstructured) {
+	if ref == "" {
+		return
+	}
+	bundleTemplates.Lock()
+	defer bundleTemplates.Unlock()
+	cached, ok := bundleTemplates.catalogs[catalog]
+	if !ok || cached.ref != ref {
+		cached = &catalogTemplates{ref: ref, bundles: map[string][]unstructured.Unstructured{}}
+		bundleTemplates.catalogs[catalog] = cached
+	}
+	copied := make([]unstructured.Unstructured, len(templates))
+	for i := range templates {
+		templates[i].DeepCopyInto(&copied[i])
+	}
+	cached.bundles[bundle] = copied
+}
+
+// NewCatalogClient returns the client of the catalogs served by the ClusterCatalogs. It trusts the system roots and
+// the CA certificates in caFile, if it is set, and its requests time out after constant.DefaultCatalogTimeout.
+func NewCatalogClient(caFile string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	httpClient := &http.Client{Transport: transport, Timeout: constant.DefaultCatalogTimeout}
+	if caFile == "" {
+		return httpClient, nil
+	}
+	caPEM, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the CA file %s of the ClusterCatalogs", caFile)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.Errorf("not found any CA certificate in the CA file %s of the ClusterCatalogs", caFile)
+	}
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return httpClient, nil
+}
+
+// +kubebuilder:rbac:groups=olm.operatorframework.io,resources=clusterextensions,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=olm.operatorframework.io,resources=clustercatalogs,verbs=get;list;watch
+
+// olmv1Installer installs the operators by the ClusterExtensions of OLM v1, and offers the alm-examples of their
+// installed bundles in the ClusterCatalogs as the templates
+type olmv1Installer struct {
+	*ODLMOperator
+}
+
+// Install creates the ClusterExtension of the operator, or updates the ClusterExtension created by ODLM to the OperandRegistry
+func (i *olmv1Installer) Install(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) error {
+	ext, err := i.getClusterExtension(ctx, opt.Name)
+	if err != nil {
+		return err
+	}
+
+	if ext == nil {
+		i.createOperatorNamespace(ctx, opt)
+
+		ext = &unstructured.Unstructured{}
+		ext.SetGroupVersionKind(ClusterExtensionGVK)
+		ext.SetName(opt.Name)
+		ext.SetLabels(map[string]string{constant.OpreqLabel: "true"})
+		if err := i.setClusterExtensionSpec(ext, opt); err != nil {
+			return err
+		}
+
+		klog.V(2).Info("Creating the ClusterExtension: " + opt.Name)
+		requestInstance.SetCreatingCondition(opt.Name, apiv1alpha1.ResourceTypeExtension, corev1.ConditionTrue)
+		if err := i.Create(ctx, ext); err != nil && !apierrors.IsAlreadyExists(err) {
+			requestInstance.SetCreatingCondition(opt.Name, apiv1alpha1.ResourceTypeExtension, corev1.ConditionFalse)
+			requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorFailed, "")
+			return errors.Wrapf(err, "failed to create the ClusterExtension %s", opt.Name)
+		}
+		requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorInstalling, "")
+		return nil
+	}
+
+	if _, ok := ext.GetLabels()[constant.OpreqLabel]; !ok {
+		// ClusterExtension existing and not managed by OperandRequest controller
+		klog.V(1).Infof("ClusterExtension %s isn't created by ODLM. Ignore update/delete it.", ext.GetName())
+		return nil
+	}
+
+	// ClusterExtension existing and managed by OperandRequest controller, update it when the operator is changed
+	updated := ext.DeepCopy()
+	if err := i.setClusterExtensionSpec(updated, opt); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(ext.Object["spec"], updated.Object["spec"]) {
+		return nil
+	}
+
+	klog.V(2).Infof("Updating the ClusterExtension %s ...", opt.Name)
+	requestInstance.SetUpdatingCondition(opt.Name, apiv1alpha1.ResourceTypeExtension, corev1.ConditionTrue)
+	if err := i.Update(ctx, updated); err != nil {
+		requestInstance.SetUpdatingCondition(opt.Name, apiv1alpha1.ResourceTypeExtension, corev1.ConditionFalse)
+		requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorFailed, "")
+		return errors.Wrapf(err, "failed to update the ClusterExtension %s", opt.Name)
+	}
+	requestInstance.SetMemberStatus(opt.Name, apiv1alpha1.OperatorUpdating, "")
+	return nil
+}
+
+// Uninstall deletes the ClusterExtension created by ODLM, unless it is labeled not to be uninstalled
+func (i *olmv1Installer) Uninstall(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) error {
+	ext, err := i.getClusterExtension(ctx, opt.Name)
+	if err != nil {
+		return err
+	}
+	if ext == nil {
+		klog.V(3).Infof("There is no ClusterExtension %s", opt.Name)
+		return nil
+	}
+
+	labels := ext.GetLabels()
+	if _, ok := labels[constant.OpreqLabel]; !ok {
+		klog.V(2).Infof("ClusterExtension %s isn't created by ODLM", ext.GetName())
+		return nil
+	}
+	if labels[constant.NotUninstallLabel] == "true" {
+		klog.V(1).Infof("Operator %s has label %s. Skip the uninstall", opt.Name, constant.NotUninstallLabel)
+		return nil
+	}
+
+	klog.V(2).Infof("Deleting the ClusterExtension %s", opt.Name)
+	requestInstance.SetDeletingCondition(opt.Name, apiv1alpha1.ResourceTypeExtension, corev1.ConditionTrue)
+	if err := i.Delete(ctx, ext); err != nil && !apierrors.IsNotFound(err) {
+		requestInstance.SetDeletingCondition(opt.Name, apiv1alpha1.ResourceTypeExtension, corev1.ConditionFalse)
+		return errors.Wrapf(err, "failed to delete the ClusterExtension %s", opt.Name)
+	}
+	klog.V(1).Infof("ClusterExtension %s is deleted", opt.Name)
+	return nil
+}
+
+// Status returns the phase of the operator from the Installed and Progressing conditions of its ClusterExtension
+func (i *olmv1Installer) Status(ctx context.Context, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) (*InstallStatus, error) {
+	ext, err := i.getClusterExtension(ctx, opt.Name)
+	if err != nil {
+		return nil, err
+	}
+	if ext == nil {
+		return &InstallStatus{}, nil
+	}
+	_, managed := ext.GetLabels()[constant.OpreqLabel]
+	return &InstallStatus{Installed: true, Managed: managed, Phase: GetClusterExtensionPhase(ext)}, nil
+}
+
+// Templates returns the alm-examples of the bundle installed by the ClusterExtension of the operator
+func (i *olmv1Installer) Templates(ctx context.Context, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) ([]unstructured.Unstructured, error) {
+	ext, err := i.getClusterExtension(ctx, opt.Name)
+	if err != nil || ext == nil {
+		return nil, err
+	}
+	bundle, _, _ := unstructured.NestedString(ext.Object, "status", "install", "bundle", "name")
+	if bundle == "" {
+		klog.V(2).Infof("The bundle of the ClusterExtension %s is not installed yet", opt.Name)
+		return nil, nil
+	}
+
+	catalogs, err := i.getClusterCatalogs(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	for _, catalog := range catalogs {
+		ref, _, _ := unstructured.NestedString(catalog.Object, "status", "resolvedSource", "image", "ref")
+		if templates, ok := loadBundleTemplates(catalog.GetName(), ref, bundle); ok {
+			return templates, nil
+		}
+		baseURL, _, _ := unstructured.NestedString(catalog.Object, "status", "urls", "base")
+		if baseURL == "" {
+			klog.V(3).Infof("ClusterCatalog %s is not serving yet", catalog.GetName())
+			continue
+		}
+		almExamples, found, err := i.getBundleALMExamples(ctx, baseURL, bundle)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the bundle %s from the ClusterCatalog %s", bundle, catalog.GetName())
+		}
+		if !found {
+			continue
+		}
+		templates := []unstructured.Unstructured{}
+		if almExamples == "" {
+			klog.Warningf("Notfound alm-examples in the bundle %s of the ClusterCatalog %s", bundle, catalog.GetName())
+		} else if templates, err = ParseTemplates(almExamples); err != nil {
+			return nil, err
+		}
+		storeBundleTemplates(catalog.GetName(), ref, bundle, templates)
+		return templates, nil
+	}
+	return nil, errors.Errorf("not found the bundle %s of the ClusterExtension %s in the ClusterCatalogs", bundle, opt.Name)
+}
+
+// GetClusterExtensionPhase returns the phase of the operator installed by a ClusterExtension.
+// The operator is running once the bundle is installed, and it is failed when the ClusterExtension is blocked from progressing.
+func GetClusterExtensionPhase(ext *unstructured.Unstructured) apiv1alpha1.OperatorPhase {
+	if getClusterExtensionCondition(ext, "Installed") == metav1.ConditionTrue {
+		return apiv1alpha1.OperatorRunning
+	}
+	if getClusterExtensionCondition(ext, "Progressing") == metav1.ConditionFalse {
+		return apiv1alpha1.OperatorFailed
+	}
+	return apiv1alpha1.OperatorInstalling
+}
+
+func getClusterExtensionCondition(ext *unstructured.Unstructured, conditionType string) metav1.ConditionStatus {
+	conditions, _, _ := unstructured.NestedSlice(ext.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		// The condition is out of date when it is observed before the latest spec
+		if generation, ok := condition["observedGeneration"].(int64); ok && generation < ext.GetGeneration() {
+			return metav1.ConditionUnknown
+		}
+		status, _ := condition["status"].(string)
+		return metav1.ConditionStatus(status)
+	}
+	return metav1.ConditionUnknown
+}
+
+// getClusterExtension gets the ClusterExtension of the operator, it returns nil if the ClusterExtension is not found
+func (i *olmv1Installer) getClusterExtension(ctx context.Context, name string) (*unstructured.Unstructured, error) {
+	ext := &unstructured.Unstructured{}
+	ext.SetGroupVersionKind(ClusterExtensionGVK)
+	if err := i.Client.Get(ctx, types.NamespacedName{Name: name}, ext); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the ClusterExtension %s", name)
+	}
+	return ext, nil
+}
+
+// setClusterExtensionSpec sets the package, the channel, the version range and the catalog of the operator in the
+// ClusterExtension, the other fields of the spec, like the ones defaulted by OLM, are kept
+func (i *olmv1Installer) setClusterExtensionSpec(ext *unstructured.Unstructured, opt *apiv1alpha1.Operator) error {
+	catalog := []string{"spec", "source", "catalog"}
+	fields := []struct {
+		path  []string
+		value interface{}
+		empty bool
+	}{
+		{[]string{"spec", "namespace"}, i.GetOperatorNamespace(opt.InstallMode, opt.Namespace), false},
+		{[]string{"spec", "serviceAccount", "name"}, opt.ServiceAccountName, false},
+		{[]string{"spec", "source", "sourceType"}, "Catalog", false},
+		{append(catalog, "packageName"), opt.PackageName, false},
+		{append(catalog, "channels"), []interface{}{opt.Channel}, opt.Channel == ""},
+		{append(catalog, "version"), opt.VersionConstraint, opt.VersionConstraint == ""},
+		{append(catalog, "selector"), map[string]interface{}{
+			"matchLabels": map[string]interface{}{catalogNameLabel: opt.SourceName},
+		}, opt.SourceName == ""},
+	}
+	for _, f := range fields {
+		if f.empty {
+			unstructured.RemoveNestedField(ext.Object, f.path...)
+			continue
+		}
+		if err := unstructured.SetNestedField(ext.Object, f.value, f.path...); err != nil {
+			return errors.Wrapf(err, "failed to set %s of the ClusterExtension %s", strings.Join(f.path, "."), opt.Name)
+		}
+	}
+	return nil
+}
+
+// createOperatorNamespace creates the namespace of the operator, OLM v1 installs the operator in an existing namespace
+func (i *olmv1Installer) createOperatorNamespace(ctx context.Context, opt *apiv1alpha1.Operator) {
+	namespace := i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	if namespace == util.GetOperatorNamespace() || namespace == constant.ClusterOperatorNamespace {
+		return
+	}
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   namespace,
+			Labels: map[string]string{constant.OpreqLabel: "true"},
+		},
+	}
+	if err := i.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
+		klog.Warningf("failed to create the namespace %s, please make sure it exists: %s", namespace, err)
+	}
+}
+
+// getClusterCatalogs returns the ClusterCatalog of the operator, or all the ClusterCatalogs if it isn't set
+func (i *olmv1Installer) getClusterCatalogs(ctx context.Context, opt *apiv1alpha1.Operator) ([]unstructured.Unstructured, error) {
+	if opt.SourceName != "" {
+		catalog := unstructured.Unstructured{}
+		catalog.SetGroupVersionKind(ClusterCatalogGVK)
+		if err := i.Client.Get(ctx, types.NamespacedName{Name: opt.SourceName}, &catalog); err != nil {
+			return nil, errors.Wrapf(err, "failed to get the ClusterCatalog %s", opt.SourceName)
+		}
+		return []unstructured.Unstructured{catalog}, nil
+	}
+	catalogs := &unstructured.UnstructuredList{}
+	catalogs.SetGroupVersionKind(ClusterCatalogGVK.GroupVersion().WithKind(ClusterCatalogGVK.Kind + "List"))
+	if err := i.Client.List(ctx, catalogs); err != nil {
+		return nil, errors.Wrap(err, "failed to list the ClusterCatalogs")
+	}
+	return catalogs.Items, nil
+}
+
+// catalogObject is an object in the file-based catalog served by a ClusterCatalog
+type catalogObject struct {
+	Schema     string `json:"schema"`
+	Name       string `json:"name"`
+	Properties []struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	} `json:"properties"`
+}
+
+// getBundleALMExamples gets the alm-examples of the bundle in the file-based catalog served from the base URL.
+// The bundle is queried from the metas endpoint, and it is looked up in the whole catalog if the endpoint isn't served.
+// The annotations of the ClusterServiceVersion are read from its olm.csv.metadata property, or from its
+// manifest in the olm.bundle.object properties.
+func (i *olmv1Installer) getBundleALMExamples(ctx context.Context, baseURL, bundle string) (string, bool, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	query := url.Values{"schema": []string{"olm.bundle"}, "name": []string{bundle}}
+	resp, err := i.getCatalogContent(ctx, baseURL+"/api/v1/metas?"+query.Encode())
+	if err != nil {
+		return "", false, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		klog.V(3).Infof("The metas endpoint isn't served from %s, looking up the bundle %s in the whole catalog", baseURL, bundle)
+		if resp, err = i.getCatalogContent(ctx, baseURL+"/api/v1/all"); err != nil {
+			return "", false, err
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", false, errors.Errorf("unexpected status %s", resp.Status)
+	}
+
+	decoder := json.NewDecoder(bufio.NewReader(resp.Body))
+	for {
+		obj := catalogObject{}
+		if err := decoder.Decode(&obj); err != nil {
+			if err == io.EOF {
+				return "", false, nil
+			}
+			return "", false, errors.Wrap(err, "failed to decode the catalog")
+		}
+		if obj.Schema != "olm.bundle" || obj.Name != bundle {
+			continue
+		}
+		for _, p := range obj.Properties {
+			var annotations map[string]string
+			switch p.Type {
+			case "olm.csv.metadata":
+				metadata := struct {
+					Annotations map[string]string `json:"annotations"`
+				}{}
+				if err := json.Unmarshal(p.Value, &metadata); err != nil {
+					return "", false, errors.Wrap(err, "failed to decode the olm.csv.metadata property")
+				}
+				annotations = metadata.Annotations
+			case "olm.bundle.object":
+				if annotations, err = getBundleObjectCSVAnnotations(p.Value); err != nil {
+					return "", false, err
+				}
+			}
+			if annotations != nil {
+				return annotations["alm-examples"], true, nil
+			}
+		}
+		return "", true, nil
+	}
+}
+
+// getCatalogContent sends the GET request of the catalog content by the CatalogClient
+func (i *olmv1Installer) getCatalogContent(ctx context.Context, contentURL string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, contentURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := i.CatalogClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: constant.DefaultCatalogTimeout}
+	}
+	return httpClient.Do(req.WithContext(ctx))
+}
+
+// getBundleObjectCSVAnnotations returns the annotations of the manifest in an olm.bundle.object property,
+// it returns nil if the manifest isn't a ClusterServiceVersion
+func getBundleObjectCSVAnnotations(value json.RawMessage) (map[string]string, error) {
+	object := struct {
+		Data string `json:"data"`
+	}{}
+	if err := json.Unmarshal(value, &object); err != nil {
+		return nil, errors.Wrap(err, "failed to decode the olm.bundle.object property")
+	}
+	data, err := base64.StdEncoding.DecodeString(object.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode the data of the olm.bundle.object property")
+	}
+	manifest := struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, errors.Wrap(err, "failed to decode the manifest of the olm.bundle.object property")
+	}
+	if manifest.Kind != "ClusterServiceVersion" {
+		return nil, nil
+	}
+	if manifest.Metadata.Annotations == nil {
+		return map[string]string{}, nil
+	}
+	return manifest.Metadata.Annotations, nil
+}
@@ -0,0 +1,270 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
+)
+
+var _ = Describe("Installing the operators by OLM v1", func() {
+	const (
+		registryNamespace = "ibm-common-services"
+		operatorNamespace = "ibm-operators"
+	)
+
+	var (
+		ctx      context.Context
+		r        *olmv1Installer
+		olm      *testutil.FakeOLMv1
+		caDir    string
+		registry *operatorv1alpha1.OperandRegistry
+		request  *operatorv1alpha1.OperandRequest
+		opt      *operatorv1alpha1.Operator
+	)
+
+	getClusterExtension := func() *unstructured.Unstructured {
+		ext, err := r.getClusterExtension(ctx, "etcd")
+		Expect(err).NotTo(HaveOccurred())
+		return ext
+	}
+
+	getStatus := func() *InstallStatus {
+		status, err := r.Status(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		return status
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		testutil.AddOLMv1ToScheme(scheme)
+		c := fake.NewFakeClientWithScheme(scheme)
+		r = &olmv1Installer{&ODLMOperator{Client: c, Reader: c}}
+
+		var err error
+		olm, err = testutil.NewFakeOLMv1(ctx, c, "operatorhubio",
+			testutil.Bundle{Package: "etcd", Channel: "stable", Version: "3.5.0", ALMExamples: testutil.EtcdExample},
+			testutil.Bundle{Package: "etcd", Channel: "stable", Version: "3.6.0", ALMExamples: testutil.EtcdExample},
+			testutil.Bundle{Package: "etcd", Channel: "alpha", Version: "3.7.0", ALMExamples: "[]"},
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		caDir, err = ioutil.TempDir("", "catalogd-ca")
+		Expect(err).NotTo(HaveOccurred())
+		caFile := filepath.Join(caDir, "ca.crt")
+		Expect(ioutil.WriteFile(caFile, olm.CertificatePEM(), 0600)).To(Succeed())
+		r.CatalogClient, err = NewCatalogClient(caFile)
+		Expect(err).NotTo(HaveOccurred())
+
+		bundleTemplates.Lock()
+		bundleTemplates.catalogs = map[string]*catalogTemplates{}
+		bundleTemplates.Unlock()
+
+		registry = testutil.OperandRegistryObj("common-service", registryNamespace, operatorNamespace)
+		registry.Spec.InstallerGeneration = operatorv1alpha1.InstallerGenerationV1
+		registry.Spec.Operators[0].SourceName = "operatorhubio"
+		registry.Spec.Operators[0].Channel = "stable"
+		registry.Spec.Operators[0].ServiceAccountName = "etcd-installer"
+		registry.Default()
+		opt = registry.GetOperator("etcd")
+		request = testutil.OperandRequestObj("common-service", registryNamespace, "cloudpak", "cloudpak")
+	})
+
+	AfterEach(func() {
+		olm.Close()
+		Expect(os.RemoveAll(caDir)).To(Succeed())
+	})
+
+	It("Should be the installer of the OLM v1 registry", func() {
+		Expect(r.GetInstaller(registry, opt)).Should(BeAssignableToTypeOf(&olmv1Installer{}))
+		registry.Spec.InstallerGeneration = operatorv1alpha1.InstallerGenerationV0
+		Expect(r.GetInstaller(registry, opt)).Should(BeAssignableToTypeOf(&olmInstaller{}))
+	})
+
+	It("Should create the ClusterExtension with the package, the channel and the version range", func() {
+		opt.VersionConstraint = ">=3.5.0 <3.6.0"
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(request.Status.Members[0].Phase.OperatorPhase).Should(Equal(operatorv1alpha1.OperatorInstalling))
+
+		ext := getClusterExtension()
+		Expect(ext.GetLabels()).Should(HaveKeyWithValue(constant.OpreqLabel, "true"))
+		Expect(ext.Object["spec"]).Should(Equal(map[string]interface{}{
+			"namespace":      operatorNamespace,
+			"serviceAccount": map[string]interface{}{"name": "etcd-installer"},
+			"source": map[string]interface{}{
+				"sourceType": "Catalog",
+				"catalog": map[string]interface{}{
+					"packageName": "etcd",
+					"channels":    []interface{}{"stable"},
+					"version":     ">=3.5.0 <3.6.0",
+					"selector": map[string]interface{}{
+						"matchLabels": map[string]interface{}{"olm.operatorframework.io/metadata.name": "operatorhubio"},
+					},
+				},
+			},
+		}))
+
+		Expect(olm.Reconcile(ctx)).To(Succeed())
+		bundle, _, _ := unstructured.NestedString(getClusterExtension().Object, "status", "install", "bundle", "name")
+		Expect(bundle).Should(Equal("etcd.v3.5.0"))
+	})
+
+	It("Should only update the ClusterExtension when the operator changes", func() {
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		// The fields defaulted by OLM are kept
+		ext := getClusterExtension()
+		Expect(unstructured.SetNestedField(ext.Object, "CatalogProvided", "spec", "source", "catalog", "upgradeConstraintPolicy")).To(Succeed())
+		Expect(r.Client.Update(ctx, ext)).To(Succeed())
+		version := getClusterExtension().GetResourceVersion()
+
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(getClusterExtension().GetResourceVersion()).Should(Equal(version))
+
+		opt.Channel = "alpha"
+		opt.SourceName = ""
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(request.Status.Members[0].Phase.OperatorPhase).Should(Equal(operatorv1alpha1.OperatorUpdating))
+		catalog, _, _ := unstructured.NestedMap(getClusterExtension().Object, "spec", "source", "catalog")
+		Expect(catalog).Should(Equal(map[string]interface{}{
+			"packageName":             "etcd",
+			"channels":                []interface{}{"alpha"},
+			"upgradeConstraintPolicy": "CatalogProvided",
+		}))
+	})
+
+	It("Should follow the Installed and Progressing conditions of the ClusterExtension", func() {
+		Expect(getStatus().Installed).Should(BeFalse())
+
+		opt.VersionConstraint = ">=4.0.0"
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(olm.Reconcile(ctx)).To(Succeed())
+		Expect(*getStatus()).Should(Equal(InstallStatus{Installed: true, Managed: true, Phase: operatorv1alpha1.OperatorInstalling}))
+
+		opt.VersionConstraint = ""
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(olm.Reconcile(ctx)).To(Succeed())
+		Expect(getStatus().Phase).Should(Equal(operatorv1alpha1.OperatorRunning))
+
+		olm.Blocked["etcd"] = "the ServiceAccount etcd-installer doesn't have the permissions"
+		Expect(olm.Reconcile(ctx)).To(Succeed())
+		Expect(getStatus().Phase).Should(Equal(operatorv1alpha1.OperatorFailed))
+	})
+
+	It("Should take the templates from the installed bundle in the ClusterCatalog", func() {
+		Expect(r.Templates(ctx, registry, opt)).Should(BeNil())
+
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(olm.Reconcile(ctx)).To(Succeed())
+		templates, err := r.Templates(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templates).Should(HaveLen(1))
+		Expect(templates[0].GetKind()).Should(Equal("EtcdCluster"))
+
+		// The bundle is resolved from all the ClusterCatalogs without the catalog of the operator
+		opt.SourceName = ""
+		opt.Channel = "alpha"
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(olm.Reconcile(ctx)).To(Succeed())
+		templates, err = r.Templates(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templates).ShouldNot(BeNil())
+		Expect(templates).Should(BeEmpty())
+	})
+
+	It("Should query the installed bundle and cache its templates until the content of the ClusterCatalog changes", func() {
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(olm.Reconcile(ctx)).To(Succeed())
+		templates, err := r.Templates(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templates).Should(HaveLen(1))
+		Expect(olm.Requests).Should(Equal([]string{"/catalogs/operatorhubio/api/v1/metas"}))
+
+		// The cached templates are not changed by the caller
+		templates[0].SetName("changed")
+		templates, err = r.Templates(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templates[0].GetName()).Should(Equal("example"))
+		Expect(olm.Requests).Should(HaveLen(1))
+
+		olm.Bundles[1].ALMExamples = "[]"
+		Expect(olm.Publish(ctx, "sha256:1")).To(Succeed())
+		templates, err = r.Templates(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templates).Should(BeEmpty())
+		Expect(olm.Requests).Should(HaveLen(2))
+	})
+
+	It("Should look up the installed bundle in the whole catalog without the metas endpoint", func() {
+		olm.MetasDisabled = true
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(olm.Reconcile(ctx)).To(Succeed())
+		templates, err := r.Templates(ctx, registry, opt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templates).Should(HaveLen(1))
+		Expect(olm.Requests).Should(Equal([]string{"/catalogs/operatorhubio/api/v1/metas", "/catalogs/operatorhubio/api/v1/all"}))
+	})
+
+	It("Should only trust the catalog server with the CA", func() {
+		var err error
+		r.CatalogClient, err = NewCatalogClient("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		Expect(olm.Reconcile(ctx)).To(Succeed())
+		_, err = r.Templates(ctx, registry, opt)
+		Expect(err).To(HaveOccurred())
+
+		_, err = NewCatalogClient(filepath.Join(caDir, "missing.crt"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should only uninstall the ClusterExtension created by ODLM", func() {
+		Expect(r.Install(ctx, request, registry, opt)).To(Succeed())
+		ext := getClusterExtension()
+		ext.SetLabels(map[string]string{constant.OpreqLabel: "true", constant.NotUninstallLabel: "true"})
+		Expect(r.Client.Update(ctx, ext)).To(Succeed())
+		Expect(r.Uninstall(ctx, request, registry, opt)).To(Succeed())
+		Expect(getClusterExtension()).NotTo(BeNil())
+
+		ext = getClusterExtension()
+		ext.SetLabels(nil)
+		Expect(r.Client.Update(ctx, ext)).To(Succeed())
+		Expect(getStatus().Managed).Should(BeFalse())
+		Expect(r.Uninstall(ctx, request, registry, opt)).To(Succeed())
+		Expect(getClusterExtension()).NotTo(BeNil())
+
+		ext = getClusterExtension()
+		ext.SetLabels(map[string]string{constant.OpreqLabel: "true"})
+		Expect(r.Client.Update(ctx, ext)).To(Succeed())
+		Expect(r.Uninstall(ctx, request, registry, opt)).To(Succeed())
+		Expect(getClusterExtension()).To(BeNil())
+	})
+})
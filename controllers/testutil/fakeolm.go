@@ -0,0 +1,348 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/operator-framework/api/pkg/lib/version"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Failure is the step at which FakeOLM fails to install a package
+type Failure string
+
+const (
+	// FailInstallPlan fails the InstallPlans of the package
+	FailInstallPlan Failure = "InstallPlan"
+	// FailClusterServiceVersion fails the ClusterServiceVersions of the package
+	FailClusterServiceVersion Failure = "ClusterServiceVersion"
+)
+
+// FakeOLM is a fake OLM controller for the tests with envtest. It watches the Subscriptions, and resolves the latest
+// bundle in their channels from a fixture catalog. An InstallPlan is created for the ClusterServiceVersion of the
+// bundle, it requires an approval with the manual approval, and its ClusterServiceVersion is created once it is complete.
+// The InstallPlans and the ClusterServiceVersions move through their phases after the delays.
+type FakeOLM struct {
+	client.Client
+	// Catalog is the fixture catalog shared by all the CatalogSources, the name of the ClusterServiceVersion of a bundle is its name
+	Catalog []Bundle
+	// InstallPlanDelay is the time to complete an approved InstallPlan
+	InstallPlanDelay time.Duration
+	// ClusterServiceVersionDelay is the time for a ClusterServiceVersion to succeed
+	ClusterServiceVersionDelay time.Duration
+
+	mu       sync.Mutex
+	failures map[string]Failure
+	since    map[string]time.Time
+}
+
+// NewFakeOLM creates the fake OLM controller with the bundles in its catalog
+func NewFakeOLM(c client.Client, catalog ...Bundle) *FakeOLM {
+	return &FakeOLM{
+		Client:   c,
+		Catalog:  catalog,
+		failures: map[string]Failure{},
+		since:    map[string]time.Time{},
+	}
+}
+
+// SetFailure fails the installation of the package at the step, the empty step clears the failure
+func (f *FakeOLM) SetFailure(pkg string, failure Failure) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if failure == "" {
+		delete(f.failures, pkg)
+		return
+	}
+	f.failures[pkg] = failure
+}
+
+func (f *FakeOLM) getFailure(pkg string) Failure {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.failures[pkg]
+}
+
+// start records the time when the object enters its phase
+func (f *FakeOLM) start(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.since[key] = time.Now()
+}
+
+// wait returns the time to wait for the delay since the object entered its phase
+func (f *FakeOLM) wait(key string, delay time.Duration) time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	start, ok := f.since[key]
+	if !ok {
+		start = time.Now()
+		f.since[key] = start
+	}
+	if remaining := delay - time.Since(start); remaining > 0 {
+		return remaining
+	}
+	delete(f.since, key)
+	return 0
+}
+
+// SetupWithManager runs the fake OLM controller in the manager
+func (f *FakeOLM) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("fake-olm").
+		For(&olmv1alpha1.Subscription{}).
+		Owns(&olmv1alpha1.InstallPlan{}).
+		Complete(f)
+}
+
+// Reconcile moves the Subscription, its InstallPlan and its ClusterServiceVersion to their next phases
+func (f *FakeOLM) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	sub := &olmv1alpha1.Subscription{}
+	if err := f.Get(ctx, req.NamespacedName, sub); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	bundle := f.resolve(sub)
+	if bundle == nil {
+		sub.Status.SetCondition(olmv1alpha1.SubscriptionCondition{
+			Type:    "ResolutionFailed",
+			Status:  corev1.ConditionTrue,
+			Reason:  "ConstraintsNotSatisfiable",
+			Message: "no operators found in channel " + sub.Spec.Channel + " of package " + sub.Spec.Package,
+		})
+		return ctrl.Result{}, f.updateSubscriptionStatus(ctx, sub)
+	}
+
+	if sub.Status.CurrentCSV != bundle.Name() {
+		return ctrl.Result{}, f.createInstallPlan(ctx, sub, bundle)
+	}
+	if sub.Status.InstallPlanRef == nil {
+		return ctrl.Result{}, nil
+	}
+
+	ip := &olmv1alpha1.InstallPlan{}
+	if err := f.Get(ctx, types.NamespacedName{Name: sub.Status.InstallPlanRef.Name, Namespace: sub.Namespace}, ip); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	switch ip.Status.Phase {
+	case olmv1alpha1.InstallPlanPhaseRequiresApproval:
+		if !ip.Spec.Approved {
+			return ctrl.Result{}, nil
+		}
+		ip.Status.Phase = olmv1alpha1.InstallPlanPhaseInstalling
+		f.start("InstallPlan/" + ip.Namespace + "/" + ip.Name)
+		return ctrl.Result{RequeueAfter: f.InstallPlanDelay}, f.Status().Update(ctx, ip)
+	case olmv1alpha1.InstallPlanPhaseInstalling:
+		return f.completeInstallPlan(ctx, sub, ip, bundle)
+	case olmv1alpha1.InstallPlanPhaseComplete:
+		return f.completeClusterServiceVersion(ctx, sub, bundle)
+	}
+	return ctrl.Result{}, nil
+}
+
+// resolve returns the latest bundle in the channel of the Subscription, or the bundle of its starting CSV
+func (f *FakeOLM) resolve(sub *olmv1alpha1.Subscription) *Bundle {
+	var latest *Bundle
+	for i, b := range f.Catalog {
+		if b.Package != sub.Spec.Package || b.Channel != sub.Spec.Channel {
+			continue
+		}
+		if sub.Status.InstalledCSV == "" && sub.Spec.StartingCSV == b.Name() {
+			return &f.Catalog[i]
+		}
+		if latest == nil || semver.MustParse(b.Version).GT(semver.MustParse(latest.Version)) {
+			latest = &f.Catalog[i]
+		}
+	}
+	return latest
+}
+
+// createInstallPlan creates the InstallPlan of the bundle, which is approved with the automatic approval
+func (f *FakeOLM) createInstallPlan(ctx context.Context, sub *olmv1alpha1.Subscription, bundle *Bundle) error {
+	csv := f.newClusterServiceVersion(sub.Namespace, bundle)
+	manifest, err := json.Marshal(csv)
+	if err != nil {
+		return err
+	}
+	approval := sub.Spec.InstallPlanApproval
+	if approval == "" {
+		approval = olmv1alpha1.ApprovalAutomatic
+	}
+	ip := InstallPlan("install-"+bundle.Name(), sub.Namespace)
+	ip.Spec.ClusterServiceVersionNames = []string{csv.Name}
+	ip.Spec.Approval = approval
+	ip.Spec.Approved = approval == olmv1alpha1.ApprovalAutomatic
+	ip.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(sub, olmv1alpha1.SchemeGroupVersion.WithKind(olmv1alpha1.SubscriptionKind))}
+	if err := f.Create(ctx, ip); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		if err := f.Get(ctx, types.NamespacedName{Name: ip.Name, Namespace: ip.Namespace}, ip); err != nil {
+			return err
+		}
+	}
+	phase := olmv1alpha1.InstallPlanPhaseRequiresApproval
+	if ip.Spec.Approved {
+		phase = olmv1alpha1.InstallPlanPhaseInstalling
+		f.start("InstallPlan/" + ip.Namespace + "/" + ip.Name)
+	}
+	ip.Status = olmv1alpha1.InstallPlanStatus{
+		Phase:          phase,
+		CatalogSources: []string{sub.Spec.CatalogSource},
+		Plan: []*olmv1alpha1.Step{{
+			Resolving: csv.Name,
+			Resource: olmv1alpha1.StepResource{
+				CatalogSource:          sub.Spec.CatalogSource,
+				CatalogSourceNamespace: sub.Spec.CatalogSourceNamespace,
+				Group:                  olmv1alpha1.GroupName,
+				Version:                olmv1alpha1.GroupVersion,
+				Kind:                   olmv1alpha1.ClusterServiceVersionKind,
+				Name:                   csv.Name,
+				Manifest:               string(manifest),
+			},
+			Status: olmv1alpha1.StepStatusUnknown,
+		}},
+	}
+	if err := f.Status().Update(ctx, ip); err != nil {
+		return err
+	}
+
+	sub.Status.CurrentCSV = csv.Name
+	sub.Status.State = olmv1alpha1.SubscriptionStateUpgradePending
+	sub.Status.Install = &olmv1alpha1.InstallPlanReference{
+		APIVersion: olmv1alpha1.SchemeGroupVersion.String(),
+		Kind:       olmv1alpha1.InstallPlanKind,
+		Name:       ip.Name,
+		UID:        ip.UID,
+	}
+	sub.Status.InstallPlanRef = &corev1.ObjectReference{
+		APIVersion: olmv1alpha1.SchemeGroupVersion.String(),
+		Kind:       olmv1alpha1.InstallPlanKind,
+		Name:       ip.Name,
+		Namespace:  ip.Namespace,
+		UID:        ip.UID,
+	}
+	sub.Status.RemoveConditions("ResolutionFailed")
+	return f.updateSubscriptionStatus(ctx, sub)
+}
+
+// completeInstallPlan creates the ClusterServiceVersion of the InstallPlan after its delay, unless the package fails at it
+func (f *FakeOLM) completeInstallPlan(ctx context.Context, sub *olmv1alpha1.Subscription, ip *olmv1alpha1.InstallPlan, bundle *Bundle) (ctrl.Result, error) {
+	if wait := f.wait("InstallPlan/"+ip.Namespace+"/"+ip.Name, f.InstallPlanDelay); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	if f.getFailure(bundle.Package) == FailInstallPlan {
+		ip.Status.Phase = olmv1alpha1.InstallPlanPhaseFailed
+		ip.Status.SetCondition(olmv1alpha1.InstallPlanCondition{
+			Type:    olmv1alpha1.InstallPlanInstalled,
+			Status:  corev1.ConditionFalse,
+			Reason:  olmv1alpha1.InstallPlanReasonComponentFailed,
+			Message: "the fake OLM fails the InstallPlan",
+		})
+		if err := f.Status().Update(ctx, ip); err != nil {
+			return ctrl.Result{}, err
+		}
+		sub.Status.State = olmv1alpha1.SubscriptionStateFailed
+		return ctrl.Result{}, f.updateSubscriptionStatus(ctx, sub)
+	}
+
+	csv := f.newClusterServiceVersion(sub.Namespace, bundle)
+	if err := f.Create(ctx, csv); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return ctrl.Result{}, err
+		}
+		if err := f.Get(ctx, types.NamespacedName{Name: csv.Name, Namespace: csv.Namespace}, csv); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	csv.Status = olmv1alpha1.ClusterServiceVersionStatus{
+		Phase:  olmv1alpha1.CSVPhaseInstalling,
+		Reason: olmv1alpha1.CSVReasonWaiting,
+	}
+	if err := f.Status().Update(ctx, csv); err != nil {
+		return ctrl.Result{}, err
+	}
+	f.start("ClusterServiceVersion/" + csv.Namespace + "/" + csv.Name)
+
+	ip.Status.Phase = olmv1alpha1.InstallPlanPhaseComplete
+	for _, step := range ip.Status.Plan {
+		step.Status = olmv1alpha1.StepStatusCreated
+	}
+	if err := f.Status().Update(ctx, ip); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	sub.Status.InstalledCSV = csv.Name
+	sub.Status.State = olmv1alpha1.SubscriptionStateAtLatest
+	return ctrl.Result{RequeueAfter: f.ClusterServiceVersionDelay}, f.updateSubscriptionStatus(ctx, sub)
+}
+
+// completeClusterServiceVersion moves the ClusterServiceVersion to Succeeded after its delay, unless the package fails at it
+func (f *FakeOLM) completeClusterServiceVersion(ctx context.Context, sub *olmv1alpha1.Subscription, bundle *Bundle) (ctrl.Result, error) {
+	csv := &olmv1alpha1.ClusterServiceVersion{}
+	if err := f.Get(ctx, types.NamespacedName{Name: bundle.Name(), Namespace: sub.Namespace}, csv); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if csv.Status.Phase != olmv1alpha1.CSVPhaseInstalling {
+		return ctrl.Result{}, nil
+	}
+	if wait := f.wait("ClusterServiceVersion/"+csv.Namespace+"/"+csv.Name, f.ClusterServiceVersionDelay); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	csv.Status.Phase = olmv1alpha1.CSVPhaseSucceeded
+	csv.Status.Reason = olmv1alpha1.CSVReasonInstallSuccessful
+	if f.getFailure(bundle.Package) == FailClusterServiceVersion {
+		csv.Status.Phase = olmv1alpha1.CSVPhaseFailed
+		csv.Status.Reason = olmv1alpha1.CSVReasonComponentFailed
+		csv.Status.Message = "the fake OLM fails the ClusterServiceVersion"
+	}
+	return ctrl.Result{}, f.Status().Update(ctx, csv)
+}
+
+func (f *FakeOLM) newClusterServiceVersion(namespace string, bundle *Bundle) *olmv1alpha1.ClusterServiceVersion {
+	csv := ClusterServiceVersion(bundle.Name(), namespace, bundle.ALMExamples)
+	csv.TypeMeta = metav1.TypeMeta{APIVersion: olmv1alpha1.SchemeGroupVersion.String(), Kind: olmv1alpha1.ClusterServiceVersionKind}
+	csv.Spec.Version = version.OperatorVersion{Version: semver.MustParse(bundle.Version)}
+	return csv
+}
+
+func (f *FakeOLM) updateSubscriptionStatus(ctx context.Context, sub *olmv1alpha1.Subscription) error {
+	sub.Status.LastUpdated = metav1.Now()
+	return f.Status().Update(ctx, sub)
+}
+
+// FakeOLMCatalog returns the catalog of the etcd and jenkins operators in the OperandRegistry of OperandRegistryObj
+func FakeOLMCatalog() []Bundle {
+	return []Bundle{
+		{Package: "etcd", Channel: "singlenamespace-alpha", Version: "0.0.1", ALMExamples: EtcdExample},
+		{Package: "jenkins-operator", Channel: "alpha", Version: "0.0.1", ALMExamples: JenkinsExample},
+	}
+}
@@ -0,0 +1,254 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	goruntime "runtime"
+
+	"github.com/blang/semver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	clusterExtensionGVK = schema.GroupVersionKind{Group: "olm.operatorframework.io", Version: "v1", Kind: "ClusterExtension"}
+	clusterCatalogGVK   = schema.GroupVersionKind{Group: "olm.operatorframework.io", Version: "v1", Kind: "ClusterCatalog"}
+)
+
+// OLMv1CRDPath returns the path of the ClusterExtension and ClusterCatalog CRDs of OLM v1 in the test data
+func OLMv1CRDPath() string {
+	_, file, _, _ := goruntime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata", "olmv1")
+}
+
+// AddOLMv1ToScheme registers the ClusterExtensions and ClusterCatalogs as unstructured objects,
+// so they can be listed with the fake client
+func AddOLMv1ToScheme(scheme *runtime.Scheme) {
+	for _, gvk := range []schema.GroupVersionKind{clusterExtensionGVK, clusterCatalogGVK} {
+		scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+	}
+}
+
+// Bundle is a bundle of an operator in the catalog of FakeOLMv1
+type Bundle struct {
+	Package     string
+	Channel     string
+	Version     string
+	ALMExamples string
+}
+
+// Name returns the name of the bundle, like etcd.v0.0.1
+func (b Bundle) Name() string {
+	return b.Package + ".v" + b.Version
+}
+
+// FakeOLMv1 simulates OLM v1 with a ClusterCatalog served from a local HTTPS server.
+// It resolves the bundles of the ClusterExtensions from the catalog and sets their status like operator-controller.
+type FakeOLMv1 struct {
+	client.Client
+	// Catalog is the name of the ClusterCatalog
+	Catalog string
+	// Bundles are the bundles in the catalog
+	Bundles []Bundle
+	// Blocked are the messages of the ClusterExtensions blocked from progressing by their names
+	Blocked map[string]string
+	// MetasDisabled stops serving the metas endpoint, like catalogd without the feature
+	MetasDisabled bool
+	// Requests are the paths of the requests to the catalog
+	Requests []string
+
+	server *httptest.Server
+}
+
+// NewFakeOLMv1 creates the ClusterCatalog of the bundles and starts serving its file-based catalog
+func NewFakeOLMv1(ctx context.Context, c client.Client, catalog string, bundles ...Bundle) (*FakeOLMv1, error) {
+	f := &FakeOLMv1{Client: c, Catalog: catalog, Bundles: bundles, Blocked: map[string]string{}}
+	f.server = httptest.NewTLSServer(http.HandlerFunc(f.serveCatalog))
+
+	cc := &unstructured.Unstructured{}
+	cc.SetGroupVersionKind(clusterCatalogGVK)
+	cc.SetName(catalog)
+	cc.SetLabels(map[string]string{"olm.operatorframework.io/metadata.name": catalog})
+	if err := c.Create(ctx, cc); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Publish(ctx, "sha256:0"); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// Publish sets the resolved reference of the catalog image in the status of the ClusterCatalog,
+// like catalogd does when it unpacks a new content of the catalog
+func (f *FakeOLMv1) Publish(ctx context.Context, digest string) error {
+	cc := &unstructured.Unstructured{}
+	cc.SetGroupVersionKind(clusterCatalogGVK)
+	if err := f.Get(ctx, client.ObjectKey{Name: f.Catalog}, cc); err != nil {
+		return err
+	}
+	cc.Object["status"] = map[string]interface{}{
+		"urls": map[string]interface{}{"base": f.server.URL + "/catalogs/" + f.Catalog},
+		"resolvedSource": map[string]interface{}{
+			"type":  "Image",
+			"image": map[string]interface{}{"ref": "quay.io/operatorhubio/catalog@" + digest},
+		},
+	}
+	return f.Status().Update(ctx, cc)
+}
+
+// CertificatePEM returns the PEM encoded certificate of the catalog server
+func (f *FakeOLMv1) CertificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: f.server.Certificate().Raw})
+}
+
+// Close stops serving the catalog
+func (f *FakeOLMv1) Close() {
+	f.server.Close()
+}
+
+// serveCatalog serves the olm.bundle objects of the bundles with their olm.csv.metadata properties,
+// from the whole catalog or from the metas endpoint filtered by the schema and the name
+func (f *FakeOLMv1) serveCatalog(w http.ResponseWriter, r *http.Request) {
+	f.Requests = append(f.Requests, r.URL.Path)
+	schema, name := "", ""
+	switch r.URL.Path {
+	case "/catalogs/" + f.Catalog + "/api/v1/all":
+	case "/catalogs/" + f.Catalog + "/api/v1/metas":
+		if f.MetasDisabled {
+			http.NotFound(w, r)
+			return
+		}
+		schema, name = r.URL.Query().Get("schema"), r.URL.Query().Get("name")
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	for _, b := range f.Bundles {
+		if (schema != "" && schema != "olm.bundle") || (name != "" && name != b.Name()) {
+			continue
+		}
+		bundle := map[string]interface{}{
+			"schema":  "olm.bundle",
+			"name":    b.Name(),
+			"package": b.Package,
+			"properties": []interface{}{
+				map[string]interface{}{"type": "olm.package", "value": map[string]interface{}{"packageName": b.Package, "version": b.Version}},
+				map[string]interface{}{"type": "olm.csv.metadata", "value": map[string]interface{}{
+					"annotations": map[string]interface{}{"alm-examples": b.ALMExamples},
+				}},
+			},
+		}
+		if err := encoder.Encode(bundle); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Reconcile installs the latest bundle of every ClusterExtension which satisfies its channels and version range.
+// The ClusterExtension keeps retrying when no bundle is found, and is blocked when it is in Blocked.
+func (f *FakeOLMv1) Reconcile(ctx context.Context) error {
+	exts := &unstructured.UnstructuredList{}
+	exts.SetGroupVersionKind(clusterExtensionGVK.GroupVersion().WithKind(clusterExtensionGVK.Kind + "List"))
+	if err := f.List(ctx, exts); err != nil {
+		return err
+	}
+	for i := range exts.Items {
+		ext := &exts.Items[i]
+		installed, progressing := metav1.ConditionFalse, metav1.ConditionTrue
+		reason, message := "Succeeded", ""
+		bundle := f.resolve(ext)
+		switch {
+		case f.Blocked[ext.GetName()] != "":
+			progressing, reason, message = metav1.ConditionFalse, "Blocked", f.Blocked[ext.GetName()]
+		case bundle == nil:
+			reason, message = "Retrying", "no bundles found for the package"
+		default:
+			installed = metav1.ConditionTrue
+			ext.Object["status"] = map[string]interface{}{
+				"install": map[string]interface{}{
+					"bundle": map[string]interface{}{"name": bundle.Name(), "version": bundle.Version},
+				},
+			}
+		}
+		condition := func(conditionType string, status metav1.ConditionStatus) interface{} {
+			return map[string]interface{}{
+				"type":               conditionType,
+				"status":             string(status),
+				"reason":             reason,
+				"message":            message,
+				"observedGeneration": ext.GetGeneration(),
+			}
+		}
+		if err := unstructured.SetNestedSlice(ext.Object, []interface{}{
+			condition("Installed", installed),
+			condition("Progressing", progressing),
+		}, "status", "conditions"); err != nil {
+			return err
+		}
+		if err := f.Status().Update(ctx, ext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve returns the latest bundle of the ClusterExtension, it returns nil if no bundle is found
+func (f *FakeOLMv1) resolve(ext *unstructured.Unstructured) *Bundle {
+	pkg, _, _ := unstructured.NestedString(ext.Object, "spec", "source", "catalog", "packageName")
+	channels, _, _ := unstructured.NestedStringSlice(ext.Object, "spec", "source", "catalog", "channels")
+	versionRange, _, _ := unstructured.NestedString(ext.Object, "spec", "source", "catalog", "version")
+	var latest *Bundle
+	for i, b := range f.Bundles {
+		if b.Package != pkg || (len(channels) != 0 && !containsString(channels, b.Channel)) {
+			continue
+		}
+		version := semver.MustParse(b.Version)
+		if versionRange != "" {
+			r, err := semver.ParseRange(versionRange)
+			if err != nil || !r(version) {
+				continue
+			}
+		}
+		if latest == nil || version.GT(semver.MustParse(latest.Version)) {
+			latest = &f.Bundles[i]
+		}
+	}
+	return latest
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
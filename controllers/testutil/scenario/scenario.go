@@ -0,0 +1,399 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package scenario runs the YAML scenarios of the controllers against a cluster.
+//
+// A scenario is a directory of steps, the steps are its subdirectories in the order of their names. Each step may have
+//
+//	apply.yaml   the objects to create, or to merge into the existing objects
+//	delete.yaml  the objects to delete
+//	expect.yaml  the objects expected after the step, only the fields in the fixtures are compared
+//	absent.yaml  the objects expected not to exist after the step
+//
+// The files may have multiple YAML documents.
+package scenario
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	applyFile  = "apply.yaml"
+	deleteFile = "delete.yaml"
+	expectFile = "expect.yaml"
+	absentFile = "absent.yaml"
+)
+
+// Scenario is a sequence of steps loaded from a fixtures directory
+type Scenario struct {
+	Name  string
+	Steps []Step
+}
+
+// Step changes the objects in the cluster, and checks the state of the cluster afterwards
+type Step struct {
+	Name   string
+	Apply  []*unstructured.Unstructured
+	Delete []*unstructured.Unstructured
+	Expect []*unstructured.Unstructured
+	Absent []*unstructured.Unstructured
+}
+
+// LoadAll loads every scenario in the subdirectories of the directory, in the order of their names
+func LoadAll(dir string) ([]*Scenario, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the scenarios directory %s", dir)
+	}
+	var scenarios []*Scenario
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		s, err := Load(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
+
+// Load loads the scenario in the directory
+func Load(dir string) (*Scenario, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the scenario directory %s", dir)
+	}
+	s := &Scenario{Name: filepath.Base(dir)}
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		step, err := loadStep(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load the scenario %s", s.Name)
+		}
+		s.Steps = append(s.Steps, *step)
+	}
+	if len(s.Steps) == 0 {
+		return nil, errors.Errorf("the scenario %s has no steps", s.Name)
+	}
+	return s, nil
+}
+
+func loadStep(dir string) (*Step, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the step directory %s", dir)
+	}
+	step := &Step{Name: filepath.Base(dir)}
+	for _, file := range files {
+		var objs *[]*unstructured.Unstructured
+		switch file.Name() {
+		case applyFile:
+			objs = &step.Apply
+		case deleteFile:
+			objs = &step.Delete
+		case expectFile:
+			objs = &step.Expect
+		case absentFile:
+			objs = &step.Absent
+		default:
+			return nil, errors.Errorf("unknown file %s in the step %s", file.Name(), step.Name)
+		}
+		if *objs, err = loadObjects(filepath.Join(dir, file.Name())); err != nil {
+			return nil, errors.Wrapf(err, "failed to load the step %s", step.Name)
+		}
+	}
+	return step, nil
+}
+
+func loadObjects(path string) ([]*unstructured.Unstructured, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var objs []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrapf(err, "failed to parse %s", filepath.Base(path))
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetAPIVersion() == "" || obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, errors.Errorf("the object in %s has no apiVersion, kind or name", filepath.Base(path))
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// Runner runs the scenarios with the client
+type Runner struct {
+	client.Client
+	// Timeout is the time to wait for the expected state after each step
+	Timeout time.Duration
+	// Interval is the interval to check the state of the cluster
+	Interval time.Duration
+}
+
+// Run runs the steps of the scenario in order. It fails at the first step whose expected state isn't reached
+// within the timeout, and the error shows the differences between the expected and the actual objects.
+func (r *Runner) Run(ctx context.Context, s *Scenario) error {
+	for _, step := range s.Steps {
+		for _, obj := range step.Apply {
+			if err := r.apply(ctx, obj); err != nil {
+				return errors.Wrapf(err, "failed to apply %s in the step %s", objectName(obj), step.Name)
+			}
+		}
+		for _, obj := range step.Delete {
+			if err := r.Delete(ctx, obj.DeepCopy()); err != nil && !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to delete %s in the step %s", objectName(obj), step.Name)
+			}
+		}
+
+		var diffs []string
+		err := wait.PollImmediate(r.Interval, r.Timeout, func() (bool, error) {
+			var err error
+			if diffs, err = r.check(ctx, &step); err != nil {
+				return false, err
+			}
+			return len(diffs) == 0, nil
+		})
+		if err == wait.ErrWaitTimeout {
+			return errors.Errorf("the step %s of the scenario %s didn't reach the expected state:\n%s", step.Name, s.Name, strings.Join(diffs, "\n"))
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to check the step %s of the scenario %s", step.Name, s.Name)
+		}
+	}
+	return nil
+}
+
+// Cleanup deletes the objects applied by the scenario in the reverse order, and waits for them to be removed.
+// The Namespaces are kept, because they are never removed from the test environment.
+func (r *Runner) Cleanup(ctx context.Context, s *Scenario) error {
+	var objs []*unstructured.Unstructured
+	for i := len(s.Steps) - 1; i >= 0; i-- {
+		for j := len(s.Steps[i].Apply) - 1; j >= 0; j-- {
+			if obj := s.Steps[i].Apply[j]; obj.GetKind() != "Namespace" {
+				objs = append(objs, obj)
+			}
+		}
+	}
+	for _, obj := range objs {
+		if err := r.Delete(ctx, obj.DeepCopy()); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete %s", objectName(obj))
+		}
+	}
+	return wait.PollImmediate(r.Interval, r.Timeout, func() (bool, error) {
+		for _, obj := range objs {
+			if exist, err := r.exists(ctx, obj); exist || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+// apply creates the object if it doesn't exist, or merges it into the existing object.
+// The status in the fixture is merged into the status of the object afterwards.
+func (r *Runner) apply(ctx context.Context, obj *unstructured.Unstructured) error {
+	obj = obj.DeepCopy()
+	status, hasStatus := obj.Object["status"]
+	delete(obj.Object, "status")
+
+	existing := newObject(obj)
+	err := r.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if apierrors.IsNotFound(err) {
+		if err := r.Create(ctx, obj); err != nil {
+			return err
+		}
+	} else {
+		data, err := json.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		if err := r.Patch(ctx, existing, client.RawPatch(types.MergePatchType, data)); err != nil {
+			return err
+		}
+	}
+
+	if !hasStatus {
+		return nil
+	}
+	data, err := json.Marshal(map[string]interface{}{"status": status})
+	if err != nil {
+		return err
+	}
+	return r.Status().Patch(ctx, newObject(obj), client.RawPatch(types.MergePatchType, data))
+}
+
+// check returns the differences between the expected and the actual state of the cluster
+func (r *Runner) check(ctx context.Context, step *Step) ([]string, error) {
+	var diffs []string
+	for _, expected := range step.Expect {
+		actual := newObject(expected)
+		err := r.Get(ctx, types.NamespacedName{Name: expected.GetName(), Namespace: expected.GetNamespace()}, actual)
+		if apierrors.IsNotFound(err) {
+			diffs = append(diffs, fmt.Sprintf("%s: not found", objectName(expected)))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, diff := range Diff(expected.Object, actual.Object) {
+			diffs = append(diffs, fmt.Sprintf("%s: %s", objectName(expected), diff))
+		}
+	}
+	for _, absent := range step.Absent {
+		exist, err := r.exists(ctx, absent)
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			diffs = append(diffs, fmt.Sprintf("%s: still exists", objectName(absent)))
+		}
+	}
+	return diffs, nil
+}
+
+func (r *Runner) exists(ctx context.Context, obj *unstructured.Unstructured) (bool, error) {
+	err := r.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, newObject(obj))
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Diff returns the differences between the expected and the actual values, only the fields of the expected maps are
+// compared. The items of the lists are matched regardless of their order, and the lists must have the same length.
+func Diff(expected, actual interface{}) []string {
+	return diff("", expected, actual)
+}
+
+func diff(path string, expected, actual interface{}) []string {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected %s, got %s", fieldPath(path), format(expected), format(actual))}
+		}
+		keys := make([]string, 0, len(e))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var diffs []string
+		for _, k := range keys {
+			av, ok := a[k]
+			if !ok {
+				diffs = append(diffs, fmt.Sprintf("%s: expected %s, got nothing", fieldPath(path+"."+k), format(e[k])))
+				continue
+			}
+			diffs = append(diffs, diff(path+"."+k, e[k], av)...)
+		}
+		return diffs
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected %s, got %s", fieldPath(path), format(expected), format(actual))}
+		}
+		if len(e) != len(a) {
+			return []string{fmt.Sprintf("%s: expected %d items, got %d: %s", fieldPath(path), len(e), len(a), format(actual))}
+		}
+		var diffs []string
+		matched := make([]bool, len(a))
+		for i, ev := range e {
+			found := false
+			for j, av := range a {
+				if !matched[j] && len(diff("", ev, av)) == 0 {
+					matched[j], found = true, true
+					break
+				}
+			}
+			if !found {
+				diffs = append(diffs, fmt.Sprintf("%s: no item matches %s", fieldPath(fmt.Sprintf("%s[%d]", path, i)), format(ev)))
+			}
+		}
+		return diffs
+	default:
+		// The values are compared in JSON, because the numbers decoded from the fixtures and from the cluster may have different types
+		if format(expected) != format(actual) {
+			return []string{fmt.Sprintf("%s: expected %s, got %s", fieldPath(path), format(expected), format(actual))}
+		}
+		return nil
+	}
+}
+
+func fieldPath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
+
+func format(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func newObject(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(obj.GroupVersionKind())
+	u.SetName(obj.GetName())
+	u.SetNamespace(obj.GetNamespace())
+	return u
+}
+
+func objectName(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetKind() + " " + obj.GetName()
+	}
+	return obj.GetKind() + " " + obj.GetNamespace() + "/" + obj.GetName()
+}
@@ -0,0 +1,91 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package scenario
+
+import (
+	"context"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
+)
+
+var _ = Describe("Scenario", func() {
+
+	Context("Loading the scenarios", func() {
+		It("Should load the steps of the scenario in order", func() {
+			s, err := Load(filepath.Join("testdata", "install-operators"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Name).To(Equal("install-operators"))
+			Expect(len(s.Steps)).To(BeNumerically(">", 1))
+			Expect(s.Steps[0].Name).To(Equal("00-init"))
+			Expect(s.Steps[0].Apply).NotTo(BeEmpty())
+		})
+	})
+
+	Context("Comparing the objects", func() {
+		It("Should only compare the expected fields", func() {
+			expected := map[string]interface{}{"spec": map[string]interface{}{"size": int64(3)}}
+			actual := map[string]interface{}{"spec": map[string]interface{}{"size": float64(3), "version": "3.2.13"}, "status": map[string]interface{}{}}
+			Expect(Diff(expected, actual)).To(BeEmpty())
+		})
+
+		It("Should show the path of the different fields", func() {
+			expected := map[string]interface{}{"status": map[string]interface{}{"phase": "Running", "reason": "Ready"}}
+			actual := map[string]interface{}{"status": map[string]interface{}{"phase": "Installing"}}
+			Expect(Diff(expected, actual)).To(Equal([]string{
+				`.status.phase: expected "Running", got "Installing"`,
+				`.status.reason: expected "Ready", got nothing`,
+			}))
+		})
+
+		It("Should match the items of the lists regardless of their order", func() {
+			expected := []interface{}{
+				map[string]interface{}{"name": "jenkins"},
+				map[string]interface{}{"name": "etcd"},
+			}
+			actual := []interface{}{
+				map[string]interface{}{"name": "etcd", "phase": "Running"},
+				map[string]interface{}{"name": "jenkins", "phase": "Running"},
+			}
+			Expect(Diff(expected, actual)).To(BeEmpty())
+			Expect(Diff(expected, actual[:1])).To(HaveLen(1))
+			Expect(Diff(expected[:1], []interface{}{actual[0], actual[0]})).To(HaveLen(1))
+		})
+	})
+
+	scenarios, err := LoadAll("testdata")
+
+	Context("Running the scenarios", func() {
+		It("Should load all the scenarios", func() {
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		for _, s := range scenarios {
+			s := s
+			It("Should run the scenario "+s.Name, func() {
+				runner := &Runner{Client: k8sClient, Timeout: testutil.Timeout, Interval: testutil.Interval}
+				defer func() {
+					Expect(runner.Cleanup(context.Background(), s)).To(Succeed())
+				}()
+				Expect(runner.Run(context.Background(), s)).To(Succeed())
+			})
+		}
+	})
+})
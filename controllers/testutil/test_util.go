@@ -19,9 +19,15 @@ package testutil
 import (
 	"crypto/rand"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
 	"time"
 
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -341,3 +347,68 @@ func InstallPlanStatus() olmv1alpha1.InstallPlanStatus {
 		CatalogSources: []string{},
 	}
 }
+
+// HelmChartPath returns the path of the example operator chart in the test data
+func HelmChartPath() string {
+	_, file, _, _ := goruntime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata", "charts", "example-operator")
+}
+
+// HelmChartConfigMap returns the ConfigMap which stores the packaged example operator chart
+func HelmChartConfigMap(name, namespace string) (*corev1.ConfigMap, error) {
+	chrt, err := loader.Load(HelmChartPath())
+	if err != nil {
+		return nil, err
+	}
+	dir, err := ioutil.TempDir("", "chart")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	archive, err := chartutil.Save(chrt, dir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(archive)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		BinaryData: map[string][]byte{
+			constant.DefaultHelmChartKey: data,
+		},
+	}, nil
+}
+
+// ManifestsPath returns the path of the example operator manifests in the test data
+func ManifestsPath() string {
+	_, file, _, _ := goruntime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata", "manifests", "example-operator")
+}
+
+// ManifestsConfigMap returns the ConfigMap which stores the example operator manifests
+func ManifestsConfigMap(name, namespace string) (*corev1.ConfigMap, error) {
+	files, err := ioutil.ReadDir(ManifestsPath())
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]string{}
+	for _, file := range files {
+		manifest, err := ioutil.ReadFile(filepath.Join(ManifestsPath(), file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		data[file.Name()] = string(manifest)
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: data,
+	}, nil
+}
@@ -0,0 +1,105 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// DiffSpec compares the spec rendered by ODLM with the spec of the live custom resource.
+// Only the fields in the rendered spec are compared, the fields set by the other managers are not drift.
+// It returns the paths of the drifted fields without their values, since the values can be resolved from Secrets.
+func DiffSpec(rendered, live map[string]interface{}) ([]string, error) {
+	expected, err := normalize(rendered)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to normalize the rendered spec")
+	}
+	actual, err := normalize(live)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to normalize the live spec")
+	}
+	var diffs []string
+	diffValue("spec", expected, actual, true, &diffs)
+	sort.Strings(diffs)
+	return diffs, nil
+}
+
+// HashSpec returns the hash of the spec, it is used to find if the rendered spec has changed
+func HashSpec(spec map[string]interface{}) (string, error) {
+	// The keys of the maps are sorted by json.Marshal
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the spec")
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// normalize converts the value to its JSON form, so the numbers decoded from the API server and
+// the numbers rendered from the templates can be compared
+func normalize(value map[string]interface{}) (interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+func diffValue(path string, expected, actual interface{}, found bool, diffs *[]string) {
+	if !found {
+		if expected != nil {
+			*diffs = append(*diffs, path+": removed")
+		}
+		return
+	}
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			*diffs = append(*diffs, path+": changed")
+			return
+		}
+		for key, value := range e {
+			v, found := a[key]
+			diffValue(path+"."+key, value, v, found, diffs)
+		}
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(e) {
+			*diffs = append(*diffs, path+": changed")
+			return
+		}
+		for i := range e {
+			diffValue(fmt.Sprintf("%s[%d]", path, i), e[i], a[i], true, diffs)
+		}
+	default:
+		if !reflect.DeepEqual(expected, actual) {
+			*diffs = append(*diffs, path+": changed")
+		}
+	}
+}
@@ -0,0 +1,81 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiffSpec", func() {
+
+	var rendered map[string]interface{}
+
+	BeforeEach(func() {
+		rendered = map[string]interface{}{}
+		Expect(json.Unmarshal([]byte(`{"replicas":1,"containers":[{"name":"jenkins","image":"jenkins:2.0"}],"service":{"port":8080}}`), &rendered)).Should(Succeed())
+	})
+
+	It("Should not report the fields set by the other managers", func() {
+		live := map[string]interface{}{
+			"replicas":   int64(1),
+			"containers": []interface{}{map[string]interface{}{"name": "jenkins", "image": "jenkins:2.0", "imagePullPolicy": "Always"}},
+			"service":    map[string]interface{}{"port": int64(8080), "type": "ClusterIP"},
+		}
+		diffs, err := DiffSpec(rendered, live)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diffs).Should(BeEmpty())
+	})
+
+	It("Should report the changed and removed fields", func() {
+		live := map[string]interface{}{
+			"replicas":   int64(3),
+			"containers": []interface{}{map[string]interface{}{"name": "jenkins", "image": "jenkins:2.1"}},
+		}
+		diffs, err := DiffSpec(rendered, live)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diffs).Should(Equal([]string{
+			"spec.containers[0].image: changed",
+			"spec.replicas: changed",
+			"spec.service: removed",
+		}))
+	})
+
+	It("Should report the list whose length is changed", func() {
+		live := map[string]interface{}{
+			"replicas":   int64(1),
+			"containers": []interface{}{},
+			"service":    map[string]interface{}{"port": int64(8080)},
+		}
+		diffs, err := DiffSpec(rendered, live)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diffs).Should(Equal([]string{"spec.containers: changed"}))
+	})
+})
+
+var _ = Describe("HashSpec", func() {
+
+	It("Should not depend on the order of the keys", func() {
+		a, err := HashSpec(map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": true, "d": "e"}})
+		Expect(err).NotTo(HaveOccurred())
+		b, err := HashSpec(map[string]interface{}{"b": map[string]interface{}{"d": "e", "c": true}, "a": 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(a).Should(Equal(b))
+	})
+})
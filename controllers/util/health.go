@@ -0,0 +1,130 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Health is the health of a custom resource computed from its status
+type Health string
+
+const (
+	HealthReady      Health = "Ready"
+	HealthInProgress Health = "InProgress"
+	HealthFailed     Health = "Failed"
+)
+
+// CheckHealth computes the health of the custom resource from its status, by the rules of kstatus:
+// the status must be observed for the current generation, the Stalled condition fails the custom resource,
+// and the Reconciling condition, or a Ready or Available condition which isn't True, keeps it in progress.
+// A custom resource without any of these signals is ready.
+// It returns the health and a message describing why the custom resource isn't ready.
+func CheckHealth(cr *unstructured.Unstructured) (Health, string) {
+	if cr.GetDeletionTimestamp() != nil {
+		return HealthInProgress, "the custom resource is being deleted"
+	}
+	observedGeneration, found, err := unstructured.NestedInt64(cr.Object, "status", "observedGeneration")
+	if err == nil && found && observedGeneration < cr.GetGeneration() {
+		return HealthInProgress, fmt.Sprintf("the status is observed for the generation %d, the current generation is %d", observedGeneration, cr.GetGeneration())
+	}
+
+	conditions := getConditions(cr)
+	if c, ok := conditions["Stalled"]; ok && c.status == "True" {
+		return HealthFailed, c.String()
+	}
+	if c, ok := conditions["Reconciling"]; ok && c.status == "True" {
+		return HealthInProgress, c.String()
+	}
+	for _, t := range []string{"Ready", "Available"} {
+		if c, ok := conditions[t]; ok && c.status != "True" {
+			return HealthInProgress, c.String()
+		}
+	}
+	return HealthReady, ""
+}
+
+// CheckHealthByExpression computes the health of the custom resource from the result of the JSONPath expression.
+// The custom resource is ready or failed if the result is in the ready or failed values, otherwise it is in progress.
+func CheckHealthByExpression(cr *unstructured.Unstructured, expression string, readyValues, failedValues []string) (Health, string, error) {
+	j := jsonpath.New("readiness").AllowMissingKeys(true)
+	if err := j.Parse(expression); err != nil {
+		return "", "", errors.Wrapf(err, "failed to parse the readiness expression %s", expression)
+	}
+	buf := &bytes.Buffer{}
+	if err := j.Execute(buf, cr.Object); err != nil {
+		return "", "", errors.Wrapf(err, "failed to evaluate the readiness expression %s", expression)
+	}
+	result := strings.TrimSpace(buf.String())
+	message := fmt.Sprintf("%s is %q", expression, result)
+	for _, v := range readyValues {
+		if result == v {
+			return HealthReady, "", nil
+		}
+	}
+	for _, v := range failedValues {
+		if result == v {
+			return HealthFailed, message, nil
+		}
+	}
+	return HealthInProgress, message, nil
+}
+
+type condition struct {
+	conditionType string
+	status        string
+	reason        string
+	message       string
+}
+
+func (c condition) String() string {
+	s := c.conditionType + " is " + c.status
+	if c.reason != "" {
+		s += ", reason: " + c.reason
+	}
+	if c.message != "" {
+		s += ", message: " + c.message
+	}
+	return s
+}
+
+// getConditions returns the conditions in the status of the custom resource by their type
+func getConditions(cr *unstructured.Unstructured) map[string]condition {
+	conditions := make(map[string]condition)
+	list, _, _ := unstructured.NestedSlice(cr.Object, "status", "conditions")
+	for _, item := range list {
+		c, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		t, _ := c["type"].(string)
+		if t == "" {
+			continue
+		}
+		status, _ := c["status"].(string)
+		reason, _ := c["reason"].(string)
+		message, _ := c["message"].(string)
+		conditions[t] = condition{conditionType: t, status: status, reason: reason, message: message}
+	}
+	return conditions
+}
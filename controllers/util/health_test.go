@@ -0,0 +1,102 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Checking the health of the custom resource", func() {
+
+	newCR := func(status map[string]interface{}) *unstructured.Unstructured {
+		cr := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "jenkins.io/v1alpha2",
+			"kind":       "Jenkins",
+			"metadata":   map[string]interface{}{"name": "example", "generation": int64(2)},
+		}}
+		if status != nil {
+			cr.Object["status"] = status
+		}
+		return cr
+	}
+
+	condition := func(t, status string) map[string]interface{} {
+		return map[string]interface{}{"type": t, "status": status, "reason": "Testing", "message": "testing " + t}
+	}
+
+	It("Should be ready without any status", func() {
+		health, _ := CheckHealth(newCR(nil))
+		Expect(health).Should(Equal(HealthReady))
+	})
+
+	It("Should be in progress until the status is observed for the current generation", func() {
+		health, message := CheckHealth(newCR(map[string]interface{}{"observedGeneration": int64(1)}))
+		Expect(health).Should(Equal(HealthInProgress))
+		Expect(message).Should(ContainSubstring("generation 1"))
+	})
+
+	It("Should be in progress until the Ready and Available conditions are True", func() {
+		health, message := CheckHealth(newCR(map[string]interface{}{
+			"conditions": []interface{}{condition("Ready", "True"), condition("Available", "False")},
+		}))
+		Expect(health).Should(Equal(HealthInProgress))
+		Expect(message).Should(Equal("Available is False, reason: Testing, message: testing Available"))
+
+		health, _ = CheckHealth(newCR(map[string]interface{}{
+			"observedGeneration": int64(2),
+			"conditions":         []interface{}{condition("Ready", "True"), condition("Available", "True")},
+		}))
+		Expect(health).Should(Equal(HealthReady))
+	})
+
+	It("Should follow the kstatus Stalled and Reconciling conditions", func() {
+		health, _ := CheckHealth(newCR(map[string]interface{}{
+			"conditions": []interface{}{condition("Ready", "True"), condition("Reconciling", "True")},
+		}))
+		Expect(health).Should(Equal(HealthInProgress))
+
+		health, message := CheckHealth(newCR(map[string]interface{}{
+			"conditions": []interface{}{condition("Stalled", "True"), condition("Reconciling", "True")},
+		}))
+		Expect(health).Should(Equal(HealthFailed))
+		Expect(message).Should(ContainSubstring("Stalled is True"))
+	})
+
+	It("Should use the readiness expression", func() {
+		cr := newCR(map[string]interface{}{"phase": "Installing", "conditions": []interface{}{condition("Ready", "True")}})
+		health, message, err := CheckHealthByExpression(cr, "{.status.phase}", []string{"Running"}, []string{"Failed"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(health).Should(Equal(HealthInProgress))
+		Expect(message).Should(Equal(`{.status.phase} is "Installing"`))
+
+		cr.Object["status"].(map[string]interface{})["phase"] = "Failed"
+		health, _, err = CheckHealthByExpression(cr, "{.status.phase}", []string{"Running"}, []string{"Failed"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(health).Should(Equal(HealthFailed))
+
+		health, _, err = CheckHealthByExpression(cr, `{.status.conditions[?(@.type=="Ready")].status}`, []string{"True"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(health).Should(Equal(HealthReady))
+	})
+
+	It("Should return an error if the readiness expression is invalid", func() {
+		_, _, err := CheckHealthByExpression(newCR(nil), "{.status.phase", []string{"Running"}, nil)
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -18,13 +18,31 @@ package util
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"strings"
 
 	"k8s.io/klog"
 )
 
+// The strategies of merging a list in the custom resource template with the list in the spec
+const (
+	// ListMergeReplace replaces the list in the template with the list in the spec
+	ListMergeReplace = "replace"
+	// ListMergeAppend appends the items in the spec to the list in the template
+	ListMergeAppend = "append"
+	// ListMergeByKey merges the items with the same value of a key field, for example "merge:name"
+	ListMergeByKey = "merge"
+)
+
 // MergeCR deep merge two custom resource spec
 func MergeCR(defaultCR, changedCR []byte) map[string]interface{} {
+	return MergeCRWithStrategy(defaultCR, changedCR, nil)
+}
+
+// MergeCRWithStrategy deep merge two custom resource spec, the lists are merged with the strategies
+// keyed by their dotted path in the spec. The lists without a strategy are replaced.
+func MergeCRWithStrategy(defaultCR, changedCR []byte, strategies map[string]string) map[string]interface{} {
 	if len(defaultCR) == 0 && len(changedCR) == 0 {
 		return make(map[string]interface{})
 	}
@@ -53,12 +71,12 @@ func MergeCR(defaultCR, changedCR []byte) map[string]interface{} {
 		klog.Errorf("failed to unmarshal service spec: %v", changedCRUnmarshalErr)
 	}
 	for key := range defaultCRDecoded {
-		checkKeyBeforeMerging(key, defaultCRDecoded[key], changedCRDecoded[key], changedCRDecoded)
+		checkKeyBeforeMerging(key, defaultCRDecoded[key], changedCRDecoded[key], changedCRDecoded, key, strategies)
 	}
 	return changedCRDecoded
 }
 
-func checkKeyBeforeMerging(key string, defaultMap interface{}, changedMap interface{}, finalMap map[string]interface{}) {
+func checkKeyBeforeMerging(key string, defaultMap interface{}, changedMap interface{}, finalMap map[string]interface{}, path string, strategies map[string]string) {
 	if !reflect.DeepEqual(defaultMap, changedMap) {
 		switch defaultMap := defaultMap.(type) {
 		case map[string]interface{}:
@@ -69,9 +87,15 @@ func checkKeyBeforeMerging(key string, defaultMap interface{}, changedMap interf
 				defaultMapRef := defaultMap
 				changedMapRef := changedMap.(map[string]interface{})
 				for newKey := range defaultMapRef {
-					checkKeyBeforeMerging(newKey, defaultMapRef[newKey], changedMapRef[newKey], finalMap[key].(map[string]interface{}))
+					checkKeyBeforeMerging(newKey, defaultMapRef[newKey], changedMapRef[newKey], finalMap[key].(map[string]interface{}), path+"."+newKey, strategies)
 				}
 			}
+		case []interface{}:
+			if changedMap == nil {
+				finalMap[key] = defaultMap
+			} else if changedList, ok := changedMap.([]interface{}); ok {
+				finalMap[key] = mergeList(defaultMap, changedList, path, strategies)
+			}
 		default:
 			//Check if the value was set, otherwise set it
 			if changedMap == nil {
@@ -80,3 +104,78 @@ func checkKeyBeforeMerging(key string, defaultMap interface{}, changedMap interf
 		}
 	}
 }
+
+// mergeList merges the list in the template with the list in the spec by the strategy of the path.
+// The items of a list don't add a segment to the path, so the lists nested in the items
+// are configured by the path of the outer list followed by their key.
+func mergeList(defaultList, changedList []interface{}, path string, strategies map[string]string) []interface{} {
+	strategy, mergeKey, err := parseListMergeStrategy(strategies[path])
+	if err != nil {
+		klog.Errorf("failed to merge the list %s: %v", path, err)
+		return changedList
+	}
+	switch strategy {
+	case ListMergeAppend:
+		merged := append([]interface{}{}, defaultList...)
+		for _, item := range changedList {
+			if !containsItem(defaultList, item) {
+				merged = append(merged, item)
+			}
+		}
+		return merged
+	case ListMergeByKey:
+		merged := append([]interface{}{}, defaultList...)
+		for _, item := range changedList {
+			pos := findItemByKey(defaultList, mergeKey, item)
+			if pos == -1 {
+				merged = append(merged, item)
+				continue
+			}
+			defaultItem := defaultList[pos].(map[string]interface{})
+			changedItem := item.(map[string]interface{})
+			for k := range defaultItem {
+				checkKeyBeforeMerging(k, defaultItem[k], changedItem[k], changedItem, path+"."+k, strategies)
+			}
+			merged[pos] = changedItem
+		}
+		return merged
+	default:
+		return changedList
+	}
+}
+
+func parseListMergeStrategy(strategy string) (string, string, error) {
+	switch {
+	case strategy == "" || strategy == ListMergeReplace:
+		return ListMergeReplace, "", nil
+	case strategy == ListMergeAppend:
+		return ListMergeAppend, "", nil
+	case strings.HasPrefix(strategy, ListMergeByKey+":") && len(strategy) > len(ListMergeByKey)+1:
+		return ListMergeByKey, strings.TrimPrefix(strategy, ListMergeByKey+":"), nil
+	default:
+		return "", "", fmt.Errorf("unknown merge strategy %q, it should be %q, %q or %q", strategy, ListMergeReplace, ListMergeAppend, ListMergeByKey+":<key>")
+	}
+}
+
+func containsItem(list []interface{}, item interface{}) bool {
+	for _, i := range list {
+		if reflect.DeepEqual(i, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// findItemByKey returns the position of the map in the list which has the same value of the key as the item
+func findItemByKey(list []interface{}, key string, item interface{}) int {
+	itemMap, ok := item.(map[string]interface{})
+	if !ok || itemMap[key] == nil {
+		return -1
+	}
+	for i, candidate := range list {
+		if candidateMap, ok := candidate.(map[string]interface{}); ok && reflect.DeepEqual(candidateMap[key], itemMap[key]) {
+			return i
+		}
+	}
+	return -1
+}
@@ -54,4 +54,45 @@ var _ = Describe("DeepMerge", func() {
 			Expect(mergedJSON).Should(Equal([]byte(resultJSON)))
 		})
 	})
+
+	Context("Deep Merge two JSON files with list merge strategies", func() {
+		defaultJSON := `{"containers":[{"name":"jenkins","image":"jenkins:2.0","ports":[{"name":"http","port":8080},{"name":"agent","port":50000}]},{"name":"sidecar","image":"proxy:1.0"}],"ingress":{"hosts":["jenkins.example.com"]}}`
+
+		merge := func(changedJSON string, strategies map[string]string) string {
+			mergedJSON, err := json.Marshal(MergeCRWithStrategy([]byte(defaultJSON), []byte(changedJSON), strategies))
+			Expect(err).NotTo(HaveOccurred())
+			return string(mergedJSON)
+		}
+
+		It("Should replace the list without a strategy", func() {
+			changedJSON := `{"ingress":{"hosts":["ci.example.com"]}}`
+			Expect(merge(changedJSON, nil)).Should(Equal(`{"containers":[{"image":"jenkins:2.0","name":"jenkins","ports":[{"name":"http","port":8080},{"name":"agent","port":50000}]},{"image":"proxy:1.0","name":"sidecar"}],"ingress":{"hosts":["ci.example.com"]}}`))
+		})
+
+		It("Should append the items to the list", func() {
+			changedJSON := `{"ingress":{"hosts":["jenkins.example.com","ci.example.com"]}}`
+			Expect(merge(changedJSON, map[string]string{"ingress.hosts": ListMergeAppend})).Should(ContainSubstring(`"ingress":{"hosts":["jenkins.example.com","ci.example.com"]}`))
+		})
+
+		It("Should merge the items by the key field", func() {
+			changedJSON := `{"containers":[{"name":"jenkins","image":"jenkins:2.1"},{"name":"exporter","image":"exporter:1.0"}]}`
+			Expect(merge(changedJSON, map[string]string{"containers": "merge:name"})).Should(Equal(`{"containers":[{"image":"jenkins:2.1","name":"jenkins","ports":[{"name":"http","port":8080},{"name":"agent","port":50000}]},{"image":"proxy:1.0","name":"sidecar"},{"image":"exporter:1.0","name":"exporter"}],"ingress":{"hosts":["jenkins.example.com"]}}`))
+		})
+
+		It("Should merge the nested lists of maps by the key field", func() {
+			changedJSON := `{"containers":[{"name":"jenkins","ports":[{"name":"http","port":8081},{"name":"metrics","port":9090}]}]}`
+			strategies := map[string]string{"containers": "merge:name", "containers.ports": "merge:name"}
+			Expect(merge(changedJSON, strategies)).Should(Equal(`{"containers":[{"image":"jenkins:2.0","name":"jenkins","ports":[{"name":"http","port":8081},{"name":"agent","port":50000},{"name":"metrics","port":9090}]},{"image":"proxy:1.0","name":"sidecar"}],"ingress":{"hosts":["jenkins.example.com"]}}`))
+		})
+
+		It("Should replace the nested list without a strategy", func() {
+			changedJSON := `{"containers":[{"name":"jenkins","ports":[{"name":"http","port":8081}]}]}`
+			Expect(merge(changedJSON, map[string]string{"containers": "merge:name"})).Should(ContainSubstring(`{"image":"jenkins:2.0","name":"jenkins","ports":[{"name":"http","port":8081}]}`))
+		})
+
+		It("Should replace the list with an unknown strategy", func() {
+			changedJSON := `{"ingress":{"hosts":["ci.example.com"]}}`
+			Expect(merge(changedJSON, map[string]string{"ingress.hosts": "merge:"})).Should(ContainSubstring(`"ingress":{"hosts":["ci.example.com"]}`))
+		})
+	})
 })
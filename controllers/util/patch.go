@@ -0,0 +1,60 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+)
+
+// PatchError is the error of applying a JSON patch to the spec of a custom resource
+type PatchError struct {
+	err error
+}
+
+func (e *PatchError) Error() string {
+	return "failed to apply the JSON patch: " + e.err.Error()
+}
+
+// IsPatchError checks if the cause of the error is a PatchError
+func IsPatchError(err error) bool {
+	_, ok := errors.Cause(err).(*PatchError)
+	return ok
+}
+
+// ApplyJSONPatch applies the RFC 6902 JSON patch to the spec of a custom resource
+func ApplyJSONPatch(spec map[string]interface{}, patch []byte) (map[string]interface{}, error) {
+	p, err := jsonpatch.DecodePatch(patch)
+	if err != nil {
+		return nil, &PatchError{err: err}
+	}
+	doc, err := json.Marshal(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the spec")
+	}
+	patched, err := p.Apply(doc)
+	if err != nil {
+		return nil, &PatchError{err: err}
+	}
+	patchedSpec := make(map[string]interface{})
+	if err := json.Unmarshal(patched, &patchedSpec); err != nil {
+		return nil, &PatchError{err: errors.Wrap(err, "the patched spec is not an object")}
+	}
+	return patchedSpec, nil
+}
@@ -0,0 +1,57 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("ApplyJSONPatch", func() {
+
+	var spec map[string]interface{}
+
+	BeforeEach(func() {
+		spec = map[string]interface{}{}
+		Expect(json.Unmarshal([]byte(`{"containers":[{"name":"jenkins"},{"name":"sidecar"}],"service":{"port":8080,"type":"ClusterIP"}}`), &spec)).Should(Succeed())
+	})
+
+	It("Should apply the operations in order", func() {
+		patch := `[{"op":"add","path":"/containers/1","value":{"name":"exporter"}},{"op":"replace","path":"/service","value":{"port":443}},{"op":"remove","path":"/containers/2"}]`
+		patched, err := ApplyJSONPatch(spec, []byte(patch))
+		Expect(err).NotTo(HaveOccurred())
+		result, err := json.Marshal(patched)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(result)).Should(Equal(`{"containers":[{"name":"jenkins"},{"name":"exporter"}],"service":{"port":443}}`))
+	})
+
+	It("Should return a PatchError if the patch fails to apply", func() {
+		patch := `[{"op":"test","path":"/service/port","value":80}]`
+		_, err := ApplyJSONPatch(spec, []byte(patch))
+		Expect(err).Should(HaveOccurred())
+		Expect(IsPatchError(errors.Wrap(err, "failed to create custom resource"))).Should(BeTrue())
+	})
+
+	It("Should return a PatchError if the path doesn't exist", func() {
+		patch := `[{"op":"replace","path":"/ingress/host","value":"jenkins.example.com"}]`
+		_, err := ApplyJSONPatch(spec, []byte(patch))
+		Expect(IsPatchError(err)).Should(BeTrue())
+	})
+})
@@ -0,0 +1,97 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// TemplateValues are the values which can be referenced by the templates
+// in the spec of the OperandConfig and OperandRequest, for example
+// "{{ .RequestNamespace }}" or "{{ .Parameters.storageClass }}"
+type TemplateValues struct {
+	// RequestNamespace is the namespace of the OperandRequest
+	RequestNamespace string
+	// RegistryNamespace is the namespace of the OperandRegistry
+	RegistryNamespace string
+	// OperatorNamespace is the namespace the operator is installed in
+	OperatorNamespace string
+	// Parameters are the data of the cluster-wide parameters ConfigMap
+	Parameters map[string]string
+}
+
+// RenderTemplate resolves the templates in the string values of a JSON document.
+// It returns an error if any variable can't be resolved, so a half-rendered
+// document is never returned.
+func RenderTemplate(raw []byte, values TemplateValues) ([]byte, error) {
+	if len(raw) == 0 || !bytes.Contains(raw, []byte("{{")) {
+		return raw, nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the templated spec")
+	}
+	rendered, err := renderValue("spec", decoded, values)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(rendered)
+}
+
+func renderValue(path string, value interface{}, values TemplateValues) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			rendered, err := renderValue(path+"."+key, child, values)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = rendered
+		}
+		return v, nil
+	case []interface{}:
+		for i, child := range v {
+			rendered, err := renderValue(path+"["+strconv.Itoa(i)+"]", child, values)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = rendered
+		}
+		return v, nil
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		tmpl, err := template.New(path).Option("missingkey=error").Parse(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the template %q in %s", v, path)
+		}
+		var out strings.Builder
+		if err := tmpl.Execute(&out, values); err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve the template %q in %s", v, path)
+		}
+		return out.String(), nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,61 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RenderTemplate", func() {
+
+	values := TemplateValues{
+		RequestNamespace:  "cloudpak",
+		RegistryNamespace: "ibm-common-services",
+		OperatorNamespace: "ibm-operators",
+		Parameters:        map[string]string{"storageClass": "rook-ceph", "domain": "apps.example.com"},
+	}
+
+	It("Should resolve the templates in nested maps and lists", func() {
+		spec := `{"jenkins":{"namespace":"{{ .RequestNamespace }}","hosts":["jenkins.{{ .Parameters.domain }}"],"storage":{"class":"{{ .Parameters.storageClass }}","size":10}}}`
+		rendered, err := RenderTemplate([]byte(spec), values)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(rendered)).Should(Equal(`{"jenkins":{"hosts":["jenkins.apps.example.com"],"namespace":"cloudpak","storage":{"class":"rook-ceph","size":10}}}`))
+	})
+
+	It("Should keep the spec without templates unchanged", func() {
+		spec := `{"jenkins":{"port":8081}}`
+		rendered, err := RenderTemplate([]byte(spec), values)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(rendered)).Should(Equal(spec))
+	})
+
+	It("Should report the parameter which is not found", func() {
+		spec := `{"jenkins":{"storage":{"class":"{{ .Parameters.storage }}"}}}`
+		_, err := RenderTemplate([]byte(spec), values)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("spec.jenkins.storage.class"))
+		Expect(err.Error()).Should(ContainSubstring(`map has no entry for key "storage"`))
+	})
+
+	It("Should report the variable which is not defined", func() {
+		spec := `{"jenkins":{"namespace":"{{ .ClusterName }}"}}`
+		_, err := RenderTemplate([]byte(spec), values)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("ClusterName"))
+	})
+})
@@ -32,6 +32,13 @@ func GetOperatorNamespace() string {
 	return ns
 }
 
+// IsOperatorNamespace returns if the namespace is the namespace of the operator,
+// whose objects can only be changed by the cluster administrators
+func IsOperatorNamespace(namespace string) bool {
+	operatorNamespace := GetOperatorNamespace()
+	return operatorNamespace != "" && namespace == operatorNamespace
+}
+
 // GetWatchNamespace returns the Namespace of the operator
 func GetWatchNamespace() string {
 	ns, found := os.LookupEnv("WATCH_NAMESPACE")
@@ -50,6 +57,17 @@ func GetInstallScope() string {
 	return ns
 }
 
+// GetCatalogCAFile returns the path of the CA certificate of the catalogs served by the ClusterCatalogs of OLM v1
+func GetCatalogCAFile() string {
+	return os.Getenv("CATALOGD_CA_FILE")
+}
+
+// IsWebhookEnabled returns if the admission webhooks of ODLM are enabled.
+// The conversion webhook is always enabled, since the CRDs store v1beta1.
+func IsWebhookEnabled() bool {
+	return os.Getenv("ENABLE_WEBHOOKS") != "false"
+}
+
 // ResourceExists returns true if the given resource kind exists
 // in the given api groupversion
 func ResourceExists(dc discovery.DiscoveryInterface, apiGroupVersion, kind string) (bool, error) {
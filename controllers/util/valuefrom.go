@@ -0,0 +1,116 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ValueFromKey is the key of a value which is read from a Secret or ConfigMap, for example
+// {"password": {"valueFrom": {"secretKeyRef": {"name": "db-credentials", "key": "password"}}}}
+const ValueFromKey = "valueFrom"
+
+// ValueSource is the source of a value which is read from a Secret or ConfigMap
+type ValueSource struct {
+	// SecretKeyRef selects a key of a Secret
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// ConfigMapKeyRef selects a key of a ConfigMap
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// ValueResolver reads the value of a ValueSource. It returns found as false
+// if the optional Secret, ConfigMap or key doesn't exist.
+type ValueResolver func(source *ValueSource) (value string, found bool, err error)
+
+// ResolveValueFrom replaces every valueFrom object in a JSON document with the value
+// it references. A field whose optional reference is not found is removed.
+func ResolveValueFrom(raw []byte, resolve ValueResolver) ([]byte, error) {
+	if len(raw) == 0 || !bytes.Contains(raw, []byte(`"`+ValueFromKey+`"`)) {
+		return raw, nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the spec")
+	}
+	resolved, _, err := resolveValue("spec", decoded, resolve)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(resolved)
+}
+
+func resolveValue(path string, value interface{}, resolve ValueResolver) (interface{}, bool, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if source, ok := v[ValueFromKey]; ok && len(v) == 1 {
+			return resolveValueSource(path, source, resolve)
+		}
+		for key, child := range v {
+			resolved, found, err := resolveValue(path+"."+key, child, resolve)
+			if err != nil {
+				return nil, false, err
+			}
+			if found {
+				v[key] = resolved
+			} else {
+				delete(v, key)
+			}
+		}
+		return v, true, nil
+	case []interface{}:
+		list := make([]interface{}, 0, len(v))
+		for i, child := range v {
+			resolved, found, err := resolveValue(path+"["+strconv.Itoa(i)+"]", child, resolve)
+			if err != nil {
+				return nil, false, err
+			}
+			if found {
+				list = append(list, resolved)
+			}
+		}
+		return list, true, nil
+	default:
+		return v, true, nil
+	}
+}
+
+func resolveValueSource(path string, raw interface{}, resolve ValueResolver) (interface{}, bool, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to marshal the %s of %s", ValueFromKey, path)
+	}
+	source := &ValueSource{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(source); err != nil {
+		return nil, false, errors.Wrapf(err, "invalid %s in %s", ValueFromKey, path)
+	}
+	if (source.SecretKeyRef == nil) == (source.ConfigMapKeyRef == nil) {
+		return nil, false, fmt.Errorf("invalid %s in %s: exactly one of secretKeyRef and configMapKeyRef must be set", ValueFromKey, path)
+	}
+	value, found, err := resolve(source)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to resolve the %s of %s", ValueFromKey, path)
+	}
+	return value, found, nil
+}
@@ -0,0 +1,84 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResolveValueFrom", func() {
+
+	values := map[string]string{
+		"Secret/db-credentials/password": "passw0rd",
+		"ConfigMap/ca/ca.crt":            "-----BEGIN CERTIFICATE-----",
+	}
+
+	resolve := func(source *ValueSource) (string, bool, error) {
+		var key string
+		var optional bool
+		if source.SecretKeyRef != nil {
+			key = "Secret/" + source.SecretKeyRef.Name + "/" + source.SecretKeyRef.Key
+			optional = source.SecretKeyRef.Optional != nil && *source.SecretKeyRef.Optional
+		} else {
+			key = "ConfigMap/" + source.ConfigMapKeyRef.Name + "/" + source.ConfigMapKeyRef.Key
+			optional = source.ConfigMapKeyRef.Optional != nil && *source.ConfigMapKeyRef.Optional
+		}
+		if value, ok := values[key]; ok {
+			return value, true, nil
+		}
+		if optional {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("not found %s", key)
+	}
+
+	It("Should replace the references in nested maps and lists", func() {
+		spec := `{"db":{"password":{"valueFrom":{"secretKeyRef":{"name":"db-credentials","key":"password"}}}},"certs":[{"valueFrom":{"configMapKeyRef":{"name":"ca","key":"ca.crt"}}}],"port":5432}`
+		resolved, err := ResolveValueFrom([]byte(spec), resolve)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(resolved)).Should(Equal(`{"certs":["-----BEGIN CERTIFICATE-----"],"db":{"password":"passw0rd"},"port":5432}`))
+	})
+
+	It("Should remove the field whose optional reference is not found", func() {
+		spec := `{"db":{"user":"admin","password":{"valueFrom":{"secretKeyRef":{"name":"db-credentials","key":"token","optional":true}}}}}`
+		resolved, err := ResolveValueFrom([]byte(spec), resolve)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(resolved)).Should(Equal(`{"db":{"user":"admin"}}`))
+	})
+
+	It("Should report the reference which is not found", func() {
+		spec := `{"db":{"password":{"valueFrom":{"secretKeyRef":{"name":"db-credentials","key":"token"}}}}}`
+		_, err := ResolveValueFrom([]byte(spec), resolve)
+		Expect(err).Should(MatchError("failed to resolve the valueFrom of spec.db.password: not found Secret/db-credentials/token"))
+	})
+
+	It("Should reject the valueFrom with both references", func() {
+		spec := `{"password":{"valueFrom":{"secretKeyRef":{"name":"db-credentials","key":"password"},"configMapKeyRef":{"name":"ca","key":"ca.crt"}}}}`
+		_, err := ResolveValueFrom([]byte(spec), resolve)
+		Expect(err).Should(MatchError("invalid valueFrom in spec.password: exactly one of secretKeyRef and configMapKeyRef must be set"))
+	})
+
+	It("Should keep the valueFrom field with siblings", func() {
+		spec := `{"env":{"name":"PASSWORD","valueFrom":{"fieldRef":{"fieldPath":"metadata.name"}}}}`
+		resolved, err := ResolveValueFrom([]byte(spec), resolve)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(resolved)).Should(Equal(spec))
+	})
+})
@@ -0,0 +1,48 @@
+//
+// Copyright 2021 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DecodeAdmissionObject decodes the object of an admission request into the spoke version.
+// The object is decoded into the hub first if the request is made in the hub version.
+func DecodeAdmissionObject(decoder *admission.Decoder, raw runtime.RawExtension, isHub bool, into conversion.Convertible, hub conversion.Hub) error {
+	if !isHub {
+		return decoder.DecodeRaw(raw, into)
+	}
+	if err := decoder.DecodeRaw(raw, hub); err != nil {
+		return err
+	}
+	return into.ConvertFrom(hub)
+}
+
+// EncodeAdmissionObject encodes the spoke object back into the version of the admission request.
+func EncodeAdmissionObject(obj conversion.Convertible, isHub bool, hub conversion.Hub) ([]byte, error) {
+	if !isHub {
+		return json.Marshal(obj)
+	}
+	if err := obj.ConvertTo(hub); err != nil {
+		return nil, err
+	}
+	return json.Marshal(hub)
+}
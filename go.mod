@@ -5,18 +5,24 @@ go 1.15
 require (
 	github.com/IBM/controller-filtered-cache v0.2.0
 	github.com/IBM/ibm-namespace-scope-operator v1.0.0-alpha
+	github.com/blang/semver v3.5.0+incompatible
 	github.com/coreos/etcd-operator v0.9.4
 	github.com/deckarep/golang-set v1.7.1
+	github.com/evanphx/json-patch v4.5.0+incompatible
 	github.com/onsi/ginkgo v1.12.1
 	github.com/onsi/gomega v1.10.1
 	github.com/operator-framework/api v0.3.10
 	github.com/pkg/errors v0.9.1
-	k8s.io/api v0.18.6
-	k8s.io/apimachinery v0.18.6
-	k8s.io/client-go v0.18.6
+	helm.sh/helm/v3 v3.3.4
+	k8s.io/api v0.18.8
+	k8s.io/apiextensions-apiserver v0.18.8
+	k8s.io/apimachinery v0.18.8
+	k8s.io/cli-runtime v0.18.8
+	k8s.io/client-go v0.18.8
 	k8s.io/klog v1.0.0
 	sigs.k8s.io/controller-runtime v0.6.2
 	sigs.k8s.io/kubebuilder v1.0.9-0.20200805184228-f7a3b65dd250
+	sigs.k8s.io/yaml v1.2.0
 )
 
 // fix vulnerability: CVE-2021-3121 in github.com/gogo/protobuf v1.2.1
@@ -35,6 +35,7 @@ import (
 	cache "github.com/IBM/controller-filtered-cache/filteredcache"
 	nssv1 "github.com/IBM/ibm-namespace-scope-operator/api/v1"
 	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	operatorv1beta1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1beta1"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/k8sutil"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandbindinfo"
@@ -57,6 +58,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(operatorv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -125,6 +127,43 @@ func main() {
 		klog.Errorf("unable to create controller OperandRegistry: %v", err)
 		os.Exit(1)
 	}
+	// The conversion webhook is always served, since the CRDs store v1beta1 and convert the other versions by it
+	if err = (&operatorv1beta1.OperandRequest{}).SetupWebhookWithManager(mgr); err != nil {
+		klog.Errorf("unable to create webhook OperandRequest: %v", err)
+		os.Exit(1)
+	}
+	if err = (&operatorv1beta1.OperandRegistry{}).SetupWebhookWithManager(mgr); err != nil {
+		klog.Errorf("unable to create webhook OperandRegistry: %v", err)
+		os.Exit(1)
+	}
+	if err = (&operatorv1beta1.OperandConfig{}).SetupWebhookWithManager(mgr); err != nil {
+		klog.Errorf("unable to create webhook OperandConfig: %v", err)
+		os.Exit(1)
+	}
+	if err = (&operatorv1beta1.OperandBindInfo{}).SetupWebhookWithManager(mgr); err != nil {
+		klog.Errorf("unable to create webhook OperandBindInfo: %v", err)
+		os.Exit(1)
+	}
+	if util.IsWebhookEnabled() {
+		if err = (&operandregistry.Defaulter{}).SetupWebhookWithManager(mgr); err != nil {
+			klog.Errorf("unable to create mutating webhook OperandRegistry: %v", err)
+			os.Exit(1)
+		}
+		if err = (&operandregistry.Validator{}).SetupWebhookWithManager(mgr); err != nil {
+			klog.Errorf("unable to create validating webhook OperandRegistry: %v", err)
+			os.Exit(1)
+		}
+		if err = (&operandrequest.Defaulter{}).SetupWebhookWithManager(mgr); err != nil {
+			klog.Errorf("unable to create mutating webhook OperandRequest: %v", err)
+			os.Exit(1)
+		}
+		if err = (&operandrequest.Validator{
+			ODLMOperator: deploy.NewODLMOperator(mgr, "OperandRequestValidator"),
+		}).SetupWebhookWithManager(mgr); err != nil {
+			klog.Errorf("unable to create validating webhook OperandRequest: %v", err)
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	klog.Info("starting manager")